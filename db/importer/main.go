@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/importer"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/reserve"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/sighting"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/tiger"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/user"
+)
+
+func main() {
+	tigersPath := flag.String("tigers", "", "path to the CSV file of tigers")
+	sightingsPath := flag.String("sightings", "", "path to the CSV file of sightings")
+	userEmail := flag.String("user", "", "email of the user the imported sightings are reported by")
+	isDryRun := flag.Bool("dry-run", false, "validate the files without storing anything")
+	flag.Parse()
+
+	if *userEmail == "" || (*tigersPath == "" && *sightingsPath == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	d := db.GetDB()
+
+	userRepo := user.NewUserRepository(d)
+	tigerRepo := tiger.NewTigerRepository(d)
+	sightingRepo := sighting.NewSightingRepository(d)
+	reserveRepo := reserve.NewReserveRepository(d)
+	uow := db.NewUnitOfWork(d)
+	policy := sighting.NewSightingPolicy(reserveRepo, sighting.DefaultSightingRule())
+
+	// imported rows have no images and nobody is notified about them, so neither the
+	// S3 client nor the email queue is needed
+	tigerUsecase := tiger.NewTigerUsecase(tigerRepo, sightingRepo, reserveRepo, uow, policy, nil)
+	sightingUsecase := sighting.NewSightingUsecase(sightingRepo, tigerRepo, userRepo, uow, policy, nil, nil)
+	importUsecase := importer.NewImportUsecase(tigerUsecase, sightingUsecase, uow)
+
+	u, err := userRepo.FindByEmail(ctx, *userEmail)
+	if err != nil {
+		log.Fatalf("failed to find user %s: %v", *userEmail, err)
+	}
+
+	tigers, closeTigers := open(*tigersPath)
+	defer closeTigers()
+
+	sightings, closeSightings := open(*sightingsPath)
+	defer closeSightings()
+
+	report, err := importUsecase.Import(ctx, tigers, sightings, u.ID, *isDryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, e := range report.Errors {
+		fmt.Printf("%s:%d: %s\n", e.File, e.Row, e.Message)
+	}
+
+	verb := "imported"
+	if report.DryRun {
+		verb = "would import"
+	}
+	fmt.Printf("%s %d tigers and %d sightings, skipped %d rows\n", verb, report.TigersImported, report.SightingsImported, len(report.Errors))
+
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+// open returns a reader for the file at path, or nil when no path is given.
+func open(path string) (io.Reader, func()) {
+	if path == "" {
+		return nil, func() {}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return f, func() { f.Close() }
+}
@@ -28,11 +28,21 @@ func runAutoMigrate(d *gorm.DB) {
 		panic(err)
 	}
 
+	err = d.AutoMigrate(&entities.Reserve{})
+	if err != nil {
+		panic(err)
+	}
+
 	err = d.AutoMigrate(&entities.Tiger{})
 	if err != nil {
 		panic(err)
 	}
 
+	err = d.AutoMigrate(&entities.TigerStatusChange{})
+	if err != nil {
+		panic(err)
+	}
+
 	err = d.AutoMigrate(&entities.Sighting{})
 	if err != nil {
 		panic(err)
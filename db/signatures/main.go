@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
+)
+
+// main computes the image signature and hash of the sightings whose photo was uploaded
+// before they were stored, so suggestTigers and the duplicate check cover them as well.
+// Sightings that only miss the bands of their hash get them without a download.
+func main() {
+	isDryRun := flag.Bool("dry-run", false, "count the sightings without a signature or hash without downloading anything")
+	flag.Parse()
+
+	d := db.GetDB()
+
+	var sightings []entities.Sighting
+	err := d.
+		Where("image_url <> '' AND (image_signature IS NULL OR image_signature = '' OR image_hash IS NULL OR image_hash = '' OR image_hash_band0 IS NULL)").
+		Order("id ASC").
+		Find(&sightings).
+		Error
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *isDryRun {
+		fmt.Printf("%d sightings have no image signature or hash bands\n", len(sightings))
+		return
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	failed := 0
+	for _, s := range sightings {
+		sig, err := imageproc.ParseSignature(s.ImageSignature)
+		hash, hashErr := imageproc.ParseHash(s.ImageHash)
+		if err != nil || hashErr != nil {
+			sig, hash, err = download(client, s.ImageURL)
+		}
+		if err != nil {
+			log.Printf("sighting %d: %v", s.ID, err)
+			failed++
+			continue
+		}
+
+		s.ImageSignature = sig.String()
+		s.SetImageHash(hash)
+
+		err = d.Model(&entities.Sighting{}).
+			Where("id = ?", s.ID).
+			Updates(map[string]interface{}{
+				"image_signature":  s.ImageSignature,
+				"image_hash":       s.ImageHash,
+				"image_hash_band0": s.ImageHashBand0,
+				"image_hash_band1": s.ImageHashBand1,
+				"image_hash_band2": s.ImageHashBand2,
+				"image_hash_band3": s.ImageHashBand3,
+			}).
+			Error
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	fmt.Printf("processed %d images, %d failed\n", len(sightings)-failed, failed)
+}
+
+// download fetches the image at url and computes its signature and hash.
+func download(client *http.Client, url string) (imageproc.Signature, imageproc.Hash, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return imageproc.Signature{}, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return imageproc.Signature{}, 0, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return imageproc.Signature{}, 0, err
+	}
+
+	r := bytes.NewReader(b)
+
+	sig, err := imageproc.ComputeSignature(r)
+	if err != nil {
+		return imageproc.Signature{}, 0, err
+	}
+
+	hash, err := imageproc.ComputeHash(r)
+	if err != nil {
+		return imageproc.Signature{}, 0, err
+	}
+
+	return sig, hash, nil
+}
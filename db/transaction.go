@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+// Do implements entities.UnitOfWork.
+//
+// Nested calls join the outer transaction through a savepoint instead of starting a
+// new one, so an error only rolls back the writes of the nested call and the outer
+// call decides whether to give up on the whole transaction.
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	d := u.db.WithContext(ctx)
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		d = tx.WithContext(ctx)
+	}
+
+	return d.Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+func NewUnitOfWork(d *gorm.DB) entities.UnitOfWork {
+	return &unitOfWork{d}
+}
+
+// Conn returns the transaction carried by ctx if there is one, otherwise it falls
+// back to d. Repositories should use it instead of d.WithContext(ctx) so they can
+// take part in a UnitOfWork.
+func Conn(ctx context.Context, d *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+
+	return d.WithContext(ctx)
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWork_Do(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		fnErr      error
+		swallowErr bool
+		wantErr    error
+		wantCount  int64
+	}{
+		{
+			name:      "should commit every write given fn returns nil",
+			fnErr:     nil,
+			wantErr:   nil,
+			wantCount: 2,
+		},
+		{
+			name:      "should roll back every write given fn returns error",
+			fnErr:     errors.New("boom"),
+			wantErr:   errors.New("boom"),
+			wantCount: 0,
+		},
+		{
+			name:       "should only roll back nested writes given outer fn ignores the nested error",
+			fnErr:      errors.New("boom"),
+			swallowErr: true,
+			wantErr:    nil,
+			wantCount:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := GetTestDB()
+			err := d.AutoMigrate(&entities.User{})
+			assert.NoError(t, err)
+
+			uow := NewUnitOfWork(d)
+
+			err = uow.Do(context.Background(), func(ctx context.Context) error {
+				err := Conn(ctx, d).Create(&entities.User{Name: "user-1"}).Error
+				assert.NoError(t, err)
+
+				// nested units join the outer transaction through a savepoint
+				err = uow.Do(ctx, func(ctx context.Context) error {
+					err := Conn(ctx, d).Create(&entities.User{Name: "user-2"}).Error
+					assert.NoError(t, err)
+
+					return tc.fnErr
+				})
+				if tc.swallowErr {
+					return nil
+				}
+
+				return err
+			})
+
+			assert.Equal(t, tc.wantErr, err)
+
+			var count int64
+			d.Model(&entities.User{}).Count(&count)
+			assert.Equal(t, tc.wantCount, count)
+		})
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"time"
 
 	"github.com/muhwyndhamhp/tigerhall-kittens/db"
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/importer"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/reserve"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/sighting"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/tiger"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/tokenhistory"
@@ -24,16 +27,21 @@ func Setup(t *testing.T, now time.Time, randomDBErr bool) (*Resolver, *s3mock.S3
 	tigerRepo := tiger.NewTigerRepository(d)
 	sightingRepo := sighting.NewSightingRepository(d)
 	tokenRepo := tokenhistory.NewTokenHistoryRepository(d)
+	reserveRepo := reserve.NewReserveRepository(d)
+	uow := db.NewUnitOfWork(d)
+	policy := sighting.NewSightingPolicy(reserveRepo, sighting.DefaultSightingRule())
 
 	mockS3 := s3mock.NewS3ClientInterface(t)
 
 	emailQueue := make(chan email.SightingEmail)
 
 	userUsecase := user.NewUserUsecase(userRepo, tokenRepo)
-	tigerUsecase := tiger.NewTigerUsecase(tigerRepo, sightingRepo, mockS3)
-	sightingUsecase := sighting.NewSightingUsecase(sightingRepo, tigerRepo, userRepo, mockS3, emailQueue)
+	tigerUsecase := tiger.NewTigerUsecase(tigerRepo, sightingRepo, reserveRepo, uow, policy, mockS3)
+	sightingUsecase := sighting.NewSightingUsecase(sightingRepo, tigerRepo, userRepo, uow, policy, mockS3, emailQueue)
+	reserveUsecase := reserve.NewReserveUsecase(reserveRepo)
+	importUsecase := importer.NewImportUsecase(tigerUsecase, sightingUsecase, uow)
 
-	r := NewResolver(userUsecase, tigerUsecase, sightingUsecase)
+	r := NewResolver(userUsecase, tigerUsecase, sightingUsecase, reserveUsecase, importUsecase)
 
 	return r, mockS3, emailQueue
 }
@@ -61,7 +69,7 @@ func SeedDB(d *gorm.DB, now time.Time, simulateErr bool) {
 			panic(err)
 		}
 	} else {
-		err := d.AutoMigrate(&entities.Tiger{}, &entities.Sighting{}, &entities.User{})
+		err := d.AutoMigrate(&entities.Reserve{}, &entities.Tiger{}, &entities.TigerStatusChange{}, &entities.Sighting{}, &entities.User{})
 		if err != nil {
 			panic(err)
 		}
@@ -92,6 +100,16 @@ func SeedDB(d *gorm.DB, now time.Time, simulateErr bool) {
 	}
 }
 
+// newSighting returns the input of a sighting without an image.
+func newSighting(tigerID uint, date time.Time, latitude, longitude float64) model.NewSighting {
+	return model.NewSighting{
+		TigerID:   tigerID,
+		Date:      &date,
+		Latitude:  &latitude,
+		Longitude: &longitude,
+	}
+}
+
 func GenerateJWT(u *entities.User) string {
 	if u == nil {
 		u = &entities.User{
@@ -3,25 +3,137 @@
 package model
 
 import (
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/geojson"
 )
 
+// Input type for a rectangular area on the map. When minLng is greater than maxLng the area is taken to cross the antimeridian.
+type Bounds struct {
+	// This is the southern edge of the area.
+	MinLat float64 `json:"minLat"`
+	// This is the western edge of the area.
+	MinLng float64 `json:"minLng"`
+	// This is the northern edge of the area.
+	MaxLat float64 `json:"maxLat"`
+	// This is the eastern edge of the area.
+	MaxLng float64 `json:"maxLng"`
+}
+
+// A type that describes the family of a tiger.
+type Family struct {
+	// These are the parents, grandparents and so on of the tiger, closest generation first.
+	Ancestors []*FamilyMember `json:"ancestors"`
+	// These are the tigers sharing the mother or the father with the tiger, oldest first.
+	Siblings []*Tiger `json:"siblings"`
+	// These are the cubs, grandcubs and so on of the tiger, closest generation first.
+	Descendants []*FamilyMember `json:"descendants"`
+}
+
+// A type that describes a relative of a tiger.
+type FamilyMember struct {
+	// This is the relative.
+	Tiger *Tiger `json:"tiger"`
+	// This is how many generations the relative is away from the tiger, 1 being parents or cubs.
+	Generation int `json:"generation"`
+}
+
+// A type that describes the home range of a tiger, the area it lives in, estimated from its sightings.
+type HomeRange struct {
+	// This is the number of sightings the home range is estimated from.
+	Sightings int `json:"sightings"`
+	// This is the minimum convex polygon around the sightings as a GeoJSON Polygon.
+	Mcp geojson.Geometry `json:"mcp"`
+	// This is the area of the minimum convex polygon in km².
+	McpAreaKm2 float64 `json:"mcpAreaKm2"`
+	// This is the contour holding 95% of the kernel density of the sightings as a GeoJSON MultiPolygon, estimated with a Gaussian kernel and the reference bandwidth. It is only computed when requested with kde.
+	Kde *geojson.Geometry `json:"kde,omitempty"`
+	// This is the area of the 95% kernel density contour in km². It is only computed when requested with kde.
+	KdeAreaKm2 *float64 `json:"kdeAreaKm2,omitempty"`
+}
+
+// This is the result of a CSV import.
+type ImportReport struct {
+	// This is true when the import was a dry run and nothing was stored.
+	DryRun bool `json:"dryRun"`
+	// This is the number of tigers imported, or that would be imported on a dry run.
+	TigersImported int `json:"tigersImported"`
+	// This is the number of sightings imported, or that would be imported on a dry run.
+	SightingsImported int `json:"sightingsImported"`
+	// This is the list of rows that were skipped, in the order they appear in the files.
+	Errors []*ImportRowError `json:"errors"`
+}
+
+// This is a row of an imported CSV file that was skipped.
+type ImportRowError struct {
+	// This is the file the row belongs to, either `tigers` or `sightings`.
+	File string `json:"file"`
+	// This is the line number of the row in the file, the header being line 1.
+	Row int `json:"row"`
+	// This is the error code, the same as the one returned by createTiger or createSighting for the same data.
+	Code string `json:"code"`
+	// This is the reason the row was skipped.
+	Message string `json:"message"`
+}
+
+// A type that summarizes how a tiger moved according to its sightings.
+type MovementStats struct {
+	// This is the total great-circle distance in km between the consecutive sightings of the tiger, ordered by date.
+	TotalDistanceKm float64 `json:"totalDistanceKm"`
+	// This is the total distance travelled divided by the number of days between the first and the last sighting of the tiger, counting at least one day.
+	AverageDailyDisplacementKm float64 `json:"averageDailyDisplacementKm"`
+	// This is the longest distance in km between two consecutive sightings of the tiger.
+	MaxJumpKm float64 `json:"maxJumpKm"`
+	// This is the number of full days since the tiger was last seen.
+	DaysSinceLastSeen int `json:"daysSinceLastSeen"`
+	// This is the number of different users who reported sightings of the tiger.
+	DistinctReporters int `json:"distinctReporters"`
+}
+
 // Mutation type for the GraphQL schema. It contains mutations that modify the data. Each mutation requires authentication with a valid JWT token in the header `Authorization` with the value of the token. If not, it will return an error code `ErrUserByCtxNotFound` in the `errors.extensions.code` field in the response.
 type Mutation struct {
 }
 
+// NearbyTiger type describes a tiger together with its distance from a searched point.
+type NearbyTiger struct {
+	// This is the tiger found near the searched point.
+	Tiger *Tiger `json:"tiger"`
+	// This is the great-circle distance in kilometers between the searched point and the last known location of the tiger.
+	DistanceKm float64 `json:"distanceKm"`
+}
+
+// This is a pagination object for the NearbyTiger type.
+type NearbyTigerPagination struct {
+	// This is a list of tigers in the current page, sorted by their distance from the searched point.
+	Tigers []*NearbyTiger `json:"tigers"`
+	// This is the total number of tigers within the radius.
+	Total int `json:"total"`
+}
+
+// Input type for creating a new reserve.
+type NewReserve struct {
+	// This is the name of the reserve. It is a required field.
+	Name string `json:"name"`
+	// This is the minimum distance in km between adjacent sightings of tigers in the reserve. It is a required field.
+	MinDistanceKm float64 `json:"minDistanceKm"`
+	// This is the number of hours after which nearby sightings are accepted. It is an optional field, nearby sightings are never accepted if empty.
+	NearbyAfterHours *float64 `json:"nearbyAfterHours,omitempty"`
+}
+
 // Input type for creating a new sighting for a tiger.
 type NewSighting struct {
 	// This is the unique identifier of the tiger associated with the sighting. It is a required field.
 	TigerID uint `json:"tigerID"`
-	// This is the date of the sighting in RFC3339Nano format. It is a required field.
-	Date time.Time `json:"date"`
-	// This is the latitude of the sighting. It is a required field.
-	Latitude float64 `json:"latitude"`
-	// This is the longitude of the sighting. It is a required field.
-	Longitude float64 `json:"longitude"`
+	// This is the date of the sighting in RFC3339Nano format. It is optional when the image is a JPEG with the date it was taken in its EXIF data.
+	Date *time.Time `json:"date,omitempty"`
+	// This is the latitude of the sighting. It is optional when the image is a JPEG with a GPS position in its EXIF data, and should be given together with longitude.
+	Latitude *float64 `json:"latitude,omitempty"`
+	// This is the longitude of the sighting. It is optional when the image is a JPEG with a GPS position in its EXIF data, and should be given together with latitude.
+	Longitude *float64 `json:"longitude,omitempty"`
 	// This is the Multi-Part scalar for uploading image of the sighting. It is an optional field.
 	Image *graphql.Upload `json:"image,omitempty"`
 }
@@ -40,6 +152,22 @@ type NewTiger struct {
 	LastLongitude float64 `json:"lastLongitude"`
 	// This is the Multi-Part scalar for uploading image of the tiger. It is an optional field.
 	Image *graphql.Upload `json:"image,omitempty"`
+	// This is the sex of the tiger. It is an optional field, UNKNOWN if empty.
+	Sex *Sex `json:"sex,omitempty"`
+	// This is the subspecies of the tiger. It is an optional field.
+	Subspecies *string `json:"subspecies,omitempty"`
+	// These are notes on the stripe pattern and other marks that identify the tiger. It is an optional field.
+	Marks *string `json:"marks,omitempty"`
+	// This is the unique identifier of the mother of the tiger. It is an optional field.
+	MotherID *uint `json:"motherID,omitempty"`
+	// This is the unique identifier of the father of the tiger. It is an optional field.
+	FatherID *uint `json:"fatherID,omitempty"`
+	// This is the unique identifier of the reserve the tiger lives in. It is an optional field.
+	ReserveID *uint `json:"reserveID,omitempty"`
+	// This overrides the minimum distance in km between adjacent sightings of the tiger. It is an optional field.
+	MinDistanceKm *float64 `json:"minDistanceKm,omitempty"`
+	// This overrides the number of hours after which nearby sightings of the tiger are accepted. It is an optional field.
+	NearbyAfterHours *float64 `json:"nearbyAfterHours,omitempty"`
 }
 
 // Input type for creating a new user profile.
@@ -56,6 +184,18 @@ type NewUser struct {
 type Query struct {
 }
 
+// A type that describes a reserve. Tigers assigned to a reserve follow its sighting rule unless they override it.
+type Reserve struct {
+	// This is the unique identifier for the reserve. It is an auto-incrementing integer.
+	ID uint `json:"id"`
+	// This is the name of the reserve.
+	Name string `json:"name"`
+	// This is the minimum distance in km a new sighting should be away from the adjacent sightings of the same tiger.
+	MinDistanceKm float64 `json:"minDistanceKm"`
+	// This is the number of hours after which a sighting closer than the minimum distance is accepted. 0 means nearby sightings are never accepted.
+	NearbyAfterHours float64 `json:"nearbyAfterHours"`
+}
+
 // A type that describes a sighting of a tiger. It contains the date, latitude, and longitude of the sighting. It also contains the tigerID and userID of the tiger and user associated with the sighting.
 type Sighting struct {
 	// This is the unique identifier for the sighting. It is an auto-incrementing integer.
@@ -74,8 +214,40 @@ type Sighting struct {
 	UserID uint `json:"userID"`
 	// This is the user associated with the sighting.
 	User *User `json:"user"`
-	// This is the URL of the image uploaded for the sighting.
+	// This is the URL of the image uploaded for the sighting in the given size. Sightings reported before images were stored in several sizes only have a thumbnail, which is returned for MEDIUM as well. The ORIGINAL image is private: its URL only works for a few minutes and is null for users other than admins and the user who reported the sighting.
 	ImageURL *string `json:"imageURL,omitempty"`
+	// This is true when the sighting implies the tiger moved faster than plausible, its image is a duplicate of an earlier one, or its date or position disagrees with the EXIF data of its image, and it was accepted for review instead of being rejected.
+	NeedsReview bool `json:"needsReview"`
+	// This is where and when the image of the sighting was taken according to its EXIF data. Metadata is stripped from stored images, so this is the only place it is kept. It is null for users who are not admins, and for sightings without EXIF data.
+	Exif *SightingExif `json:"exif,omitempty"`
+	// URL of the medium rendition of the image, empty for sightings that only have a thumbnail.
+	MediumImageURL string `json:"-"`
+}
+
+// A type that describes a cluster of sightings that fall into the same cell of the map grid. It contains the number of sightings, their centroid and the most recent tiger seen in the cell.
+type SightingCluster struct {
+	// This is the number of sightings in the cluster.
+	Count int `json:"count"`
+	// This is the latitude of the centroid of the sightings in the cluster.
+	Latitude float64 `json:"latitude"`
+	// This is the longitude of the centroid of the sightings in the cluster.
+	Longitude float64 `json:"longitude"`
+	// This is the date of the most recent sighting in the cluster in RFC3339Nano format.
+	LastSeen time.Time `json:"lastSeen"`
+	// This is the unique identifier of the tiger seen most recently in the cluster.
+	TigerID uint `json:"tigerID"`
+	// This is the tiger seen most recently in the cluster.
+	Tiger *Tiger `json:"tiger"`
+}
+
+// A type that describes the position and date read from the EXIF data of the image of a sighting. Fields missing from the EXIF data are null.
+type SightingExif struct {
+	// This is the latitude in the GPS data of the image.
+	Latitude *float64 `json:"latitude,omitempty"`
+	// This is the longitude in the GPS data of the image.
+	Longitude *float64 `json:"longitude,omitempty"`
+	// This is the date the image was taken in RFC3339Nano format.
+	Date *time.Time `json:"date,omitempty"`
 }
 
 // This is a pagination object for the Sighting type.
@@ -94,6 +266,24 @@ type Tiger struct {
 	Name string `json:"name"`
 	// This is the date of birth of the tiger in RFC3339Nano format.
 	DateOfBirth time.Time `json:"dateOfBirth"`
+	// This is the sex of the tiger.
+	Sex Sex `json:"sex"`
+	// This is the subspecies of the tiger, e.g. Bengal or Sumatran.
+	Subspecies *string `json:"subspecies,omitempty"`
+	// These are notes on the stripe pattern and other marks that identify the tiger.
+	Marks *string `json:"marks,omitempty"`
+	// This is the current status of the tiger.
+	Status TigerStatus `json:"status"`
+	// This is the list of status changes of the tiger, oldest first.
+	StatusHistory []*TigerStatusChange `json:"statusHistory"`
+	// This is the unique identifier of the mother of the tiger.
+	MotherID *uint `json:"motherID,omitempty"`
+	// This is the unique identifier of the father of the tiger.
+	FatherID *uint `json:"fatherID,omitempty"`
+	// These are the duplicate profiles that were merged into the tiger, oldest merge first. Looking them up by their ID returns this tiger.
+	Aliases []*TigerAlias `json:"aliases"`
+	// This is the family of the tiger, with ancestors and descendants up to depth generations away (1 to 5). A depth out of range returns error code `ErrInvalidFamilyDepth`.
+	Family *Family `json:"family"`
 	// This is the last seen date of the tiger in RFC3339Nano format. It is updated every time a new sighting is added for the tiger.
 	LastSeen time.Time `json:"lastSeen"`
 	// This is the last seen latitude of the tiger. It is updated every time a new sighting is added for the tiger.
@@ -102,6 +292,40 @@ type Tiger struct {
 	LastLongitude float64 `json:"lastLongitude"`
 	// This is a list of sightings associated with the tiger. It is sorted by the date property of the sighting.
 	Sightings []*Sighting `json:"sightings"`
+	// This is the unique identifier of the reserve the tiger lives in. The sighting rule of the reserve applies to the tiger.
+	ReserveID *uint `json:"reserveID,omitempty"`
+	// This overrides the minimum distance in km between adjacent sightings of the tiger. If empty, the reserve or default rule is used.
+	MinDistanceKm *float64 `json:"minDistanceKm,omitempty"`
+	// This overrides the number of hours after which sightings closer than the minimum distance are accepted. If empty, the reserve or default rule is used.
+	NearbyAfterHours *float64 `json:"nearbyAfterHours,omitempty"`
+	// This is the home range of the tiger estimated from its sightings, optionally limited to the sightings between from and to. The 95% kernel density contour is only computed when kde is set. At least 3 sightings that are not on a single line are needed, otherwise it returns error code `ErrNotEnoughSightings`. From after to returns `ErrInvalidDateRange`.
+	HomeRange *HomeRange `json:"homeRange"`
+	// These are the movement statistics of the tiger, computed from its sightings.
+	MovementStats *MovementStats `json:"movementStats"`
+	// Version of the tiger, used to cache values computed from its sightings.
+	Version uint `json:"-"`
+}
+
+// A type that describes a duplicate tiger profile that was merged into another one.
+type TigerAlias struct {
+	// This is the unique identifier the merged profile had.
+	ID uint `json:"id"`
+	// This is the name the merged profile had.
+	Name string `json:"name"`
+	// This is the date of the merge in RFC3339Nano format.
+	MergedAt time.Time `json:"mergedAt"`
+	// This is the unique identifier of the admin who merged the profiles.
+	MergedByID uint `json:"mergedByID"`
+}
+
+// Input type for filtering tigers. Only the provided fields are used to filter.
+type TigerFilter struct {
+	// This limits the tigers to the given sex.
+	Sex *Sex `json:"sex,omitempty"`
+	// This limits the tigers to the given subspecies, ignoring case.
+	Subspecies *string `json:"subspecies,omitempty"`
+	// This limits the tigers to the ones in any of the given statuses.
+	Status []TigerStatus `json:"status,omitempty"`
 }
 
 // This is a pagination object for the Tiger type.
@@ -112,6 +336,100 @@ type TigerPagination struct {
 	Total int `json:"total"`
 }
 
+// A type that describes a tiger found by name.
+type TigerSearchResult struct {
+	// This is the tiger found.
+	Tiger *Tiger `json:"tiger"`
+	// This is the name the query matched, either the name of the tiger or of one of its aliases.
+	MatchedName string `json:"matchedName"`
+	// This is true when the query matched an alias of the tiger rather than its name.
+	Alias bool `json:"alias"`
+}
+
+// A type that describes a change of the status of a tiger.
+type TigerStatusChange struct {
+	// This is the unique identifier for the status change. It is an auto-incrementing integer.
+	ID uint `json:"id"`
+	// This is the status of the tiger before the change.
+	PreviousStatus TigerStatus `json:"previousStatus"`
+	// This is the status of the tiger after the change.
+	Status TigerStatus `json:"status"`
+	// This is the date the change took effect in RFC3339Nano format, e.g. the date of death.
+	Date time.Time `json:"date"`
+	// This is a note on the reason of the change.
+	Note *string `json:"note,omitempty"`
+	// This is the unique identifier of the user who recorded the change.
+	UserID uint `json:"userID"`
+}
+
+// Input type for changing the status of a tiger.
+type TigerStatusInput struct {
+	// This is the new status of the tiger. It is a required field.
+	Status TigerStatus `json:"status"`
+	// This is the date the change took effect in RFC3339Nano format. It is an optional field, the current time if empty.
+	Date *time.Time `json:"date,omitempty"`
+	// This is a note on the reason of the change. It is an optional field.
+	Note *string `json:"note,omitempty"`
+}
+
+// A type that describes a tiger that may be the one in a photo.
+type TigerSuggestion struct {
+	// This is the suggested tiger.
+	Tiger *Tiger `json:"tiger"`
+	// This is how similar the stripe pattern of the photo is to the most similar photo of the tiger, from 0 to 1.
+	Similarity float64 `json:"similarity"`
+	// This is the ID of the sighting of the most similar photo.
+	SightingID uint `json:"sightingID"`
+	// This is the URL of the most similar photo.
+	ImageURL string `json:"imageURL"`
+}
+
+// Input type for updating an existing reserve. Only the provided fields will be updated.
+type UpdateReserve struct {
+	// This is the new name of the reserve. It is an optional field.
+	Name *string `json:"name,omitempty"`
+	// This is the new minimum distance in km. It is an optional field.
+	MinDistanceKm *float64 `json:"minDistanceKm,omitempty"`
+	// This is the new nearby sighting window in hours. It is an optional field.
+	NearbyAfterHours *float64 `json:"nearbyAfterHours,omitempty"`
+}
+
+// Input type for updating an existing sighting. Only the provided fields will be updated.
+type UpdateSighting struct {
+	// This is the new date of the sighting in RFC3339Nano format. It is an optional field.
+	Date *time.Time `json:"date,omitempty"`
+	// This is the new latitude of the sighting. It is an optional field.
+	Latitude *float64 `json:"latitude,omitempty"`
+	// This is the new longitude of the sighting. It is an optional field.
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// Input type for updating an existing tiger profile. Only the provided fields will be updated.
+type UpdateTiger struct {
+	// This is the new name of the tiger. It is an optional field.
+	Name *string `json:"name,omitempty"`
+	// This is the new date of birth of the tiger in RFC3339Nano format. It is an optional field.
+	DateOfBirth *time.Time `json:"dateOfBirth,omitempty"`
+	// This is the new sex of the tiger. It is an optional field.
+	Sex *Sex `json:"sex,omitempty"`
+	// This is the new subspecies of the tiger. It is an optional field, an empty string clears it.
+	Subspecies *string `json:"subspecies,omitempty"`
+	// These are the new notes on the marks of the tiger. It is an optional field, an empty string clears them.
+	Marks *string `json:"marks,omitempty"`
+	// This is the new mother of the tiger. It is an optional field, 0 clears it.
+	MotherID *uint `json:"motherID,omitempty"`
+	// This is the new father of the tiger. It is an optional field, 0 clears it.
+	FatherID *uint `json:"fatherID,omitempty"`
+	// This is the new reserve of the tiger. It is an optional field.
+	ReserveID *uint `json:"reserveID,omitempty"`
+	// This is the new minimum distance override in km for the tiger. It is an optional field.
+	MinDistanceKm *float64 `json:"minDistanceKm,omitempty"`
+	// This is the new nearby sighting window override in hours for the tiger. It is an optional field.
+	NearbyAfterHours *float64 `json:"nearbyAfterHours,omitempty"`
+	// This removes the minimum distance and nearby sighting window overrides of the tiger, so the rule of its reserve or the default rule applies again. Overrides given in the same input are set afterwards.
+	ClearSightingRule bool `json:"clearSightingRule"`
+}
+
 // User type that describes a user profile.
 type User struct {
 	// This is the unique identifier for the user. It is an auto-incrementing integer.
@@ -121,3 +439,140 @@ type User struct {
 	// This is the email of the user. It should be a valid email address and unique in the database.
 	Email string `json:"email"`
 }
+
+// The sizes images are stored in.
+type ImageSize string
+
+const (
+	// A thumbnail that fits in 250x200 pixels, for lists and map markers.
+	ImageSizeThumb ImageSize = "THUMB"
+	// A rendition that fits in 1280x1024 pixels, large enough to tell tigers apart by their stripes.
+	ImageSizeMedium ImageSize = "MEDIUM"
+	// The image as it was uploaded, in full resolution.
+	ImageSizeOriginal ImageSize = "ORIGINAL"
+)
+
+var AllImageSize = []ImageSize{
+	ImageSizeThumb,
+	ImageSizeMedium,
+	ImageSizeOriginal,
+}
+
+func (e ImageSize) IsValid() bool {
+	switch e {
+	case ImageSizeThumb, ImageSizeMedium, ImageSizeOriginal:
+		return true
+	}
+	return false
+}
+
+func (e ImageSize) String() string {
+	return string(e)
+}
+
+func (e *ImageSize) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImageSize(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImageSize", str)
+	}
+	return nil
+}
+
+func (e ImageSize) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// The sex of a tiger.
+type Sex string
+
+const (
+	SexMale    Sex = "MALE"
+	SexFemale  Sex = "FEMALE"
+	SexUnknown Sex = "UNKNOWN"
+)
+
+var AllSex = []Sex{
+	SexMale,
+	SexFemale,
+	SexUnknown,
+}
+
+func (e Sex) IsValid() bool {
+	switch e {
+	case SexMale, SexFemale, SexUnknown:
+		return true
+	}
+	return false
+}
+
+func (e Sex) String() string {
+	return string(e)
+}
+
+func (e *Sex) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Sex(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Sex", str)
+	}
+	return nil
+}
+
+func (e Sex) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// The status of a tiger. A tiger starts as ACTIVE and can move between ACTIVE, MISSING and RELOCATED. DECEASED is final, sightings of a deceased tiger are no longer accepted.
+type TigerStatus string
+
+const (
+	TigerStatusActive    TigerStatus = "ACTIVE"
+	TigerStatusMissing   TigerStatus = "MISSING"
+	TigerStatusDeceased  TigerStatus = "DECEASED"
+	TigerStatusRelocated TigerStatus = "RELOCATED"
+)
+
+var AllTigerStatus = []TigerStatus{
+	TigerStatusActive,
+	TigerStatusMissing,
+	TigerStatusDeceased,
+	TigerStatusRelocated,
+}
+
+func (e TigerStatus) IsValid() bool {
+	switch e {
+	case TigerStatusActive, TigerStatusMissing, TigerStatusDeceased, TigerStatusRelocated:
+		return true
+	}
+	return false
+}
+
+func (e TigerStatus) String() string {
+	return string(e)
+}
+
+func (e *TigerStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TigerStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TigerStatus", str)
+	}
+	return nil
+}
+
+func (e TigerStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
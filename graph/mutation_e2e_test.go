@@ -2,11 +2,15 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/dgrijalva/jwt-go"
+	geo "github.com/kellydunn/golang-geo"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/user"
@@ -157,6 +161,8 @@ func TestMutation_CreateUser(t *testing.T) {
 
 func TestMutation_CreateSighting(t *testing.T) {
 	now := time.Now()
+	// far enough from the seeded sighting to be plausible for a tiger
+	later := now.Add(3 * time.Hour)
 	testCases := []struct {
 		name  string
 		input model.NewSighting
@@ -168,13 +174,8 @@ func TestMutation_CreateSighting(t *testing.T) {
 		wantQueue       *email.SightingEmail
 	}{
 		{
-			name: "should return sighting with id 2, tiger id 1 and user id 1 and nil error",
-			input: model.NewSighting{
-				TigerID:   1,
-				Date:      now,
-				Latitude:  -7.250676,
-				Longitude: 111.828316,
-			},
+			name:  "should return sighting with id 2, tiger id 1 and user id 1 and nil error",
+			input: newSighting(1, later, -7.250676, 111.828316),
 			ctx: context.WithValue(context.Background(), user.KeyUser, &entities.User{
 				Model: gorm.Model{
 					ID: 1,
@@ -187,7 +188,7 @@ func TestMutation_CreateSighting(t *testing.T) {
 				ID:        2,
 				UserID:    1,
 				TigerID:   1,
-				Date:      now,
+				Date:      later,
 				Latitude:  -7.250676,
 				Longitude: 111.828316,
 			},
@@ -195,32 +196,22 @@ func TestMutation_CreateSighting(t *testing.T) {
 			wantQueue: &email.SightingEmail{
 				DestinationEmail:  "email-1@example.com",
 				TigerName:         "tiger-1",
-				SightingDate:      now.Format("2006-01-02 15:04:05"),
+				SightingDate:      later.Format("2006-01-02 15:04:05"),
 				SightingLatitude:  "-7.250676",
 				SightingLongitude: "111.828316",
 				ImageURL:          "",
 			},
 		},
 		{
-			name: "should return nil and error given user not found",
-			input: model.NewSighting{
-				TigerID:   1,
-				Date:      now,
-				Latitude:  -7.250676,
-				Longitude: 111.828316,
-			},
+			name:    "should return nil and error given user not found",
+			input:   newSighting(1, later, -7.250676, 111.828316),
 			ctx:     context.WithValue(context.Background(), user.KeyUser, nil),
 			want:    nil,
 			wantErr: errs.RespError(entities.ErrUserByCtxNotFound),
 		},
 		{
-			name: "should return nil and error given random db error",
-			input: model.NewSighting{
-				TigerID:   1,
-				Date:      now,
-				Latitude:  -7.250676,
-				Longitude: 111.828316,
-			},
+			name:  "should return nil and error given random db error",
+			input: newSighting(1, later, -7.250676, 111.828316),
 			ctx: context.WithValue(context.Background(), user.KeyUser, &entities.User{
 				Model: gorm.Model{
 					ID: 1,
@@ -246,7 +237,6 @@ func TestMutation_CreateSighting(t *testing.T) {
 					mock.Anything,
 					"filename.jpeg",
 					"image/jpeg",
-					int64(1000),
 				).
 				Return("https://example.com/image.jpeg", nil).
 				Maybe()
@@ -295,6 +285,8 @@ func TestMutation_CreateTiger(t *testing.T) {
 				ID:            2,
 				Name:          "tiger-2",
 				DateOfBirth:   now,
+				Sex:           model.SexUnknown,
+				Status:        model.TigerStatusActive,
 				LastSeen:      now,
 				LastLatitude:  -7.250676,
 				LastLongitude: 111.828316,
@@ -348,7 +340,6 @@ func TestMutation_CreateTiger(t *testing.T) {
 					mock.Anything,
 					"filename.jpeg",
 					"image/jpeg",
-					int64(1000),
 				).
 				Return("https://example.com/image.jpeg", nil).
 				Maybe()
@@ -360,3 +351,499 @@ func TestMutation_CreateTiger(t *testing.T) {
 		})
 	}
 }
+
+func TestMutation_UpdateTiger(t *testing.T) {
+	now := time.Now()
+	name := "tiger-1-updated"
+
+	testCases := []struct {
+		name  string
+		id    uint
+		input model.UpdateTiger
+
+		ctx     context.Context
+		want    *model.Tiger
+		wantErr error
+	}{
+		{
+			name:  "should return updated tiger and nil error",
+			id:    1,
+			input: model.UpdateTiger{Name: &name},
+			ctx: context.WithValue(context.Background(), user.KeyUser, &entities.User{
+				Model: gorm.Model{ID: 1},
+			}),
+			want: &model.Tiger{
+				ID:            1,
+				Name:          "tiger-1-updated",
+				DateOfBirth:   now,
+				Sex:           model.SexUnknown,
+				Status:        model.TigerStatusActive,
+				LastSeen:      now,
+				LastLatitude:  -7.550676,
+				LastLongitude: 110.828316,
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "should return nil and error given tiger not found",
+			id:    2,
+			input: model.UpdateTiger{Name: &name},
+			ctx: context.WithValue(context.Background(), user.KeyUser, &entities.User{
+				Model: gorm.Model{ID: 1},
+			}),
+			want:    nil,
+			wantErr: errs.RespError(entities.ErrTigerNotFound),
+		},
+		{
+			name:    "should return nil and error given user not found",
+			id:      1,
+			input:   model.UpdateTiger{Name: &name},
+			ctx:     context.WithValue(context.Background(), user.KeyUser, nil),
+			want:    nil,
+			wantErr: errs.RespError(entities.ErrUserByCtxNotFound),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _, _ := Setup(t, now, false)
+
+			res, err := r.Mutation().UpdateTiger(tc.ctx, tc.id, tc.input)
+
+			wantJS, _ := json.Marshal(tc.want)
+			resJS, _ := json.Marshal(res)
+
+			assert.Equal(t, string(wantJS), string(resJS))
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestMutation_DeleteAndRestoreTiger(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+
+	r, _, _ := Setup(t, now, false)
+
+	ok, err := r.Mutation().DeleteTiger(ctx, 1)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	tigers, _ := r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Equal(t, 0, tigers.Total)
+
+	sightings, _ := r.Query().SightingByTiger(ctx, 1, 1, 10)
+	assert.Equal(t, 0, sightings.Total)
+
+	ok, err = r.Mutation().DeleteTiger(ctx, 1)
+	assert.False(t, ok)
+	assert.Equal(t, errs.RespError(entities.ErrTigerNotFound), err)
+
+	res, err := r.Mutation().RestoreTiger(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), res.ID)
+
+	tigers, _ = r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Equal(t, 1, tigers.Total)
+
+	sightings, _ = r.Query().SightingByTiger(ctx, 1, 1, 10)
+	assert.Equal(t, 1, sightings.Total)
+
+	_, err = r.Mutation().RestoreTiger(ctx, 1)
+	assert.Equal(t, errs.RespError(entities.ErrTigerNotDeleted), err)
+}
+
+func TestMutation_DeleteAndRestoreTiger_Alias(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Role:  entities.RoleAdmin,
+	})
+
+	r, _, _ := Setup(t, now, false)
+
+	duplicate, err := r.Mutation().CreateTiger(ctx, model.NewTiger{
+		Name:          "tiger-1-duplicate",
+		DateOfBirth:   now,
+		LastSeen:      now.Add(time.Hour),
+		LastLatitude:  -7.250676,
+		LastLongitude: 110.828316,
+	})
+	assert.Nil(t, err)
+
+	_, err = r.Mutation().MergeTigers(ctx, duplicate.ID, 1)
+	assert.Nil(t, err)
+
+	// an alias id acts on the tiger it was merged into, like updateTiger does
+	ok, err := r.Mutation().DeleteTiger(ctx, duplicate.ID)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	tigers, _ := r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Equal(t, 0, tigers.Total)
+
+	res, err := r.Mutation().RestoreTiger(ctx, duplicate.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), res.ID)
+
+	tigers, _ = r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Equal(t, 1, tigers.Total)
+
+	_, err = r.Mutation().RestoreTiger(ctx, duplicate.ID)
+	assert.Equal(t, errs.RespError(entities.ErrTigerNotDeleted), err)
+
+	_, err = r.Mutation().RestoreTiger(ctx, 99)
+	assert.Equal(t, errs.RespError(entities.ErrTigerNotFound), err)
+}
+
+func TestMutation_UpdateTigerStatus(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+	note := "found dead near the river"
+
+	r, _, _ := Setup(t, now, false)
+
+	_, err := r.Mutation().UpdateTigerStatus(context.Background(), 1, model.TigerStatusInput{Status: model.TigerStatusMissing})
+	assert.Equal(t, errs.RespError(entities.ErrUserByCtxNotFound), err)
+
+	res, err := r.Mutation().UpdateTigerStatus(ctx, 1, model.TigerStatusInput{Status: model.TigerStatusMissing})
+	assert.Nil(t, err)
+	assert.Equal(t, model.TigerStatusMissing, res.Status)
+
+	res, err = r.Mutation().UpdateTigerStatus(ctx, 1, model.TigerStatusInput{Status: model.TigerStatusDeceased, Note: &note})
+	assert.Nil(t, err)
+	assert.Equal(t, model.TigerStatusDeceased, res.Status)
+
+	history, err := r.Tiger().StatusHistory(ctx, res)
+	assert.Nil(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, model.TigerStatusActive, history[0].PreviousStatus)
+	assert.Equal(t, model.TigerStatusDeceased, history[1].Status)
+	assert.Equal(t, &note, history[1].Note)
+	assert.Equal(t, uint(1), history[1].UserID)
+
+	_, err = r.Mutation().UpdateTigerStatus(ctx, 1, model.TigerStatusInput{Status: model.TigerStatusActive})
+	assert.Equal(t, errs.RespError(entities.ErrInvalidStatusTransition), err)
+
+	_, err = r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(time.Hour), -7.250676, 110.828316))
+	assert.Equal(t, errs.RespError(entities.ErrTigerDeceased), err)
+
+	deceased, _ := r.Query().Tigers(ctx, 1, 10, &model.TigerFilter{Status: []model.TigerStatus{model.TigerStatusDeceased}})
+	assert.Equal(t, 1, deceased.Total)
+
+	active, _ := r.Query().Tigers(ctx, 1, 10, &model.TigerFilter{Status: []model.TigerStatus{model.TigerStatusActive}})
+	assert.Equal(t, 0, active.Total)
+}
+
+func TestMutation_UpdateAndDeleteSighting(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+	otherCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 2},
+	})
+
+	r, _, queue := Setup(t, now, false)
+
+	s, err := r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(3*time.Hour), -7.250676, 111.828316))
+	assert.Nil(t, err)
+	<-queue
+
+	lat := -7.150676
+	_, err = r.Mutation().UpdateSighting(otherCtx, s.ID, model.UpdateSighting{Latitude: &lat})
+	assert.Equal(t, errs.RespError(entities.ErrSightingNotOwned), err)
+
+	res, err := r.Mutation().UpdateSighting(ctx, s.ID, model.UpdateSighting{Latitude: &lat})
+	assert.Nil(t, err)
+	assert.Equal(t, lat, res.Latitude)
+
+	tg, _ := r.Sighting().Tiger(ctx, res)
+	assert.Equal(t, lat, tg.LastLatitude)
+
+	ok, err := r.Mutation().DeleteSighting(ctx, s.ID)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	tg, _ = r.Sighting().Tiger(ctx, res)
+	assert.Equal(t, -7.550676, tg.LastLatitude)
+	assert.Equal(t, 110.828316, tg.LastLongitude)
+	assert.Equal(t, now.Format(time.RFC3339), tg.LastSeen.Format(time.RFC3339))
+
+	ok, err = r.Mutation().DeleteSighting(ctx, 1)
+	assert.False(t, ok)
+	assert.Equal(t, errs.RespError(entities.ErrLastSighting), err)
+}
+
+func TestMutation_CreateBackdatedSighting(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+
+	r, _, queue := Setup(t, now, false)
+
+	_, err := r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(-24*time.Hour), -7.540676, 110.828316))
+	d := geo.NewPoint(-7.540676, 110.828316).GreatCircleDistance(geo.NewPoint(-7.550676, 110.828316))
+	assert.Equal(t, errs.RespError(entities.NewErrTigerTooClose(d, 5)), err)
+
+	s, err := r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(-24*time.Hour), -7.250676, 111.828316))
+	assert.Nil(t, err)
+	<-queue
+
+	tg, _ := r.Sighting().Tiger(ctx, s)
+	assert.Equal(t, -7.550676, tg.LastLatitude)
+	assert.Equal(t, 110.828316, tg.LastLongitude)
+	assert.Equal(t, now.Format(time.RFC3339), tg.LastSeen.Format(time.RFC3339))
+}
+
+func TestMutation_ReserveSightingRule(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+
+	adminCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Role:  entities.RoleAdmin,
+	})
+
+	r, _, queue := Setup(t, now, false)
+
+	_, err := r.Mutation().CreateReserve(adminCtx, model.NewReserve{Name: "reserve-1", MinDistanceKm: -1})
+	assert.Equal(t, errs.RespError(entities.ErrInvalidSightingRule), err)
+
+	rs, err := r.Mutation().CreateReserve(adminCtx, model.NewReserve{Name: "reserve-1", MinDistanceKm: 1})
+	assert.Nil(t, err)
+
+	missing := uint(99)
+	_, err = r.Mutation().UpdateTiger(ctx, 1, model.UpdateTiger{ReserveID: &missing})
+	assert.Equal(t, errs.RespError(entities.ErrReserveNotFound), err)
+
+	tg, err := r.Mutation().UpdateTiger(ctx, 1, model.UpdateTiger{ReserveID: &rs.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, rs.ID, *tg.ReserveID)
+
+	// 1.11 km away, allowed by the 1 km rule of the reserve
+	_, err = r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(time.Hour), -7.540676, 110.828316))
+	assert.Nil(t, err)
+	<-queue
+
+	// the tiger override wins over the reserve
+	minDistance := 2.0
+	_, err = r.Mutation().UpdateTiger(ctx, 1, model.UpdateTiger{MinDistanceKm: &minDistance})
+	assert.Nil(t, err)
+
+	_, err = r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(2*time.Hour), -7.530676, 110.828316))
+	d := geo.NewPoint(-7.530676, 110.828316).GreatCircleDistance(geo.NewPoint(-7.540676, 110.828316))
+	assert.Equal(t, errs.RespError(entities.NewErrTigerTooClose(d, 2)), err)
+
+	// without the override the rule of the reserve applies again
+	tg, err = r.Mutation().UpdateTiger(ctx, 1, model.UpdateTiger{ClearSightingRule: true})
+	assert.Nil(t, err)
+	assert.Nil(t, tg.MinDistanceKm)
+
+	_, err = r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(2*time.Hour), -7.530676, 110.828316))
+	assert.Nil(t, err)
+	<-queue
+
+	reserves, err := r.Query().Reserves(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []*model.Reserve{rs}, reserves)
+}
+
+func TestMutation_Reserve_Admin(t *testing.T) {
+	now := time.Now()
+	minDistance := 3.0
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+
+		wantErr error
+	}{
+		{
+			name:    "should return ErrUserByCtxNotFound given no user",
+			ctx:     context.Background(),
+			wantErr: errs.RespError(entities.ErrUserByCtxNotFound),
+		},
+		{
+			name: "should return ErrForbidden given a user who is not an admin",
+			ctx: context.WithValue(context.Background(), user.KeyUser, &entities.User{
+				Model: gorm.Model{ID: 2},
+				Role:  entities.RoleUser,
+			}),
+			wantErr: errs.RespError(entities.ErrForbidden),
+		},
+		{
+			name: "should create and update the reserve given an admin",
+			ctx: context.WithValue(context.Background(), user.KeyUser, &entities.User{
+				Model: gorm.Model{ID: 1},
+				Role:  entities.RoleAdmin,
+			}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _, _ := Setup(t, now, false)
+
+			created, err := r.Mutation().CreateReserve(tc.ctx, model.NewReserve{Name: "reserve-1", MinDistanceKm: 1})
+			assert.Equal(t, tc.wantErr, err)
+
+			updated, err := r.Mutation().UpdateReserve(tc.ctx, 1, model.UpdateReserve{MinDistanceKm: &minDistance})
+			assert.Equal(t, tc.wantErr, err)
+
+			reserves, err := r.Query().Reserves(tc.ctx)
+			assert.Nil(t, err)
+
+			if tc.wantErr != nil {
+				assert.Nil(t, created)
+				assert.Nil(t, updated)
+				assert.Empty(t, reserves)
+				return
+			}
+
+			assert.Equal(t, minDistance, updated.MinDistanceKm)
+			assert.Equal(t, []*model.Reserve{updated}, reserves)
+		})
+	}
+}
+
+func TestMutation_CreateImplausibleSighting(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+
+	r, _, _ := Setup(t, now, false)
+
+	_, err := r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(time.Hour), -7.250676, 111.828316))
+	d := geo.NewPoint(-7.250676, 111.828316).GreatCircleDistance(geo.NewPoint(-7.550676, 110.828316))
+	assert.Equal(t, errs.RespError(entities.NewErrImplausibleMovement(d, time.Hour, 60)), err)
+
+	sightings, _ := r.Query().SightingByTiger(ctx, 1, 1, 10)
+	assert.Equal(t, 1, sightings.Total)
+}
+
+func TestMutation_ImportCSV(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+	adminCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Role:  entities.RoleAdmin,
+	})
+
+	r, _, _ := Setup(t, now, false)
+
+	tigers := func() *graphql.Upload {
+		return &graphql.Upload{File: strings.NewReader("name,date_of_birth,last_seen,last_latitude,last_longitude\n" +
+			"tiger-2,2015-03-01,2020-02-01,-7.25,110.53\n")}
+	}
+	sightings := func() *graphql.Upload {
+		return &graphql.Upload{File: strings.NewReader("tiger_id,tiger_name,date,latitude,longitude\n" +
+			"1,,2019-06-01,-7.45,110.73\n" +
+			",tiger-2,2019-06-01,-7.45,110.73\n" +
+			",tiger-2,2019-06-02,-7.449,110.73\n")}
+	}
+
+	_, err := r.Mutation().ImportCSV(ctx, tigers(), sightings(), false)
+	assert.Equal(t, errs.RespError(entities.ErrForbidden), err)
+
+	wantErrors := []*model.ImportRowError{
+		{
+			File:    "sightings",
+			Row:     4,
+			Code:    "ErrTigerTooClose",
+			Message: entities.NewErrTigerTooClose(geo.NewPoint(-7.449, 110.73).GreatCircleDistance(geo.NewPoint(-7.45, 110.73)), 5).Error(),
+		},
+	}
+
+	report, err := r.Mutation().ImportCSV(adminCtx, tigers(), sightings(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, &model.ImportReport{
+		DryRun:            true,
+		TigersImported:    1,
+		SightingsImported: 2,
+		Errors:            wantErrors,
+	}, report)
+
+	res, err := r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, res.Total)
+
+	report, err = r.Mutation().ImportCSV(adminCtx, tigers(), sightings(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, &model.ImportReport{
+		TigersImported:    1,
+		SightingsImported: 2,
+		Errors:            wantErrors,
+	}, report)
+
+	res, err = r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, res.Total)
+
+	ss, err := r.Query().SightingByTiger(ctx, 1, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, ss.Total)
+}
+
+func TestMutation_MergeTigers(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+	adminCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Role:  entities.RoleAdmin,
+	})
+
+	r, _, queue := Setup(t, now, false)
+
+	duplicate, err := r.Mutation().CreateTiger(ctx, model.NewTiger{
+		Name:          "tiger-1-duplicate",
+		DateOfBirth:   now,
+		LastSeen:      now.Add(time.Hour),
+		LastLatitude:  -7.250676,
+		LastLongitude: 110.828316,
+	})
+	assert.Nil(t, err)
+
+	_, err = r.Mutation().MergeTigers(ctx, duplicate.ID, 1)
+	assert.Equal(t, errs.RespError(entities.ErrForbidden), err)
+
+	res, err := r.Mutation().MergeTigers(adminCtx, duplicate.ID, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), res.ID)
+	assert.Equal(t, -7.250676, res.LastLatitude)
+
+	sightings, _ := r.Query().SightingByTiger(ctx, 1, 1, 10)
+	assert.Equal(t, 2, sightings.Total)
+
+	tigers, _ := r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Equal(t, 1, tigers.Total)
+
+	aliases, err := r.Tiger().Aliases(ctx, res)
+	assert.Nil(t, err)
+	assert.Len(t, aliases, 1)
+	assert.Equal(t, "tiger-1-duplicate", aliases[0].Name)
+	assert.Equal(t, uint(1), aliases[0].MergedByID)
+
+	sighting, err := r.Mutation().CreateSighting(ctx, newSighting(duplicate.ID, now.Add(2*time.Hour), -7.050676, 110.828316))
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), sighting.TigerID)
+	<-queue
+
+	_, err = r.Mutation().MergeTigers(adminCtx, 1, duplicate.ID)
+	assert.Equal(t, errs.RespError(entities.ErrInvalidMerge), err)
+
+	_, err = r.Mutation().MergeTigers(adminCtx, 99, 1)
+	assert.Equal(t, errs.RespError(entities.ErrTigerNotFound), err)
+}
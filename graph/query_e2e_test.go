@@ -1,13 +1,24 @@
 package graph
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"image"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	geo "github.com/kellydunn/golang-geo"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/user"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/errs"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestQuery_Tigers(t *testing.T) {
@@ -27,6 +38,8 @@ func TestQuery_Tigers(t *testing.T) {
 						ID:            1,
 						Name:          "tiger-1",
 						DateOfBirth:   now,
+						Sex:           model.SexUnknown,
+						Status:        model.TigerStatusActive,
 						LastSeen:      now,
 						LastLatitude:  -7.550676,
 						LastLongitude: 110.828316,
@@ -42,7 +55,74 @@ func TestQuery_Tigers(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			r, _, _ := Setup(t, now, false)
 
-			res, err := r.Query().Tigers(context.Background(), 1, 10)
+			res, err := r.Query().Tigers(context.Background(), 1, 10, nil)
+
+			wantJS, _ := json.Marshal(tc.want)
+			resJS, _ := json.Marshal(res)
+
+			assert.Equal(t, string(wantJS), string(resJS))
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestQuery_TigersNear(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		latitude float64
+		radiusKm float64
+
+		want    *model.NearbyTigerPagination
+		wantErr error
+	}{
+		{
+			name:     "should return nearby tigers and nil error",
+			latitude: -7.540676,
+			radiusKm: 5,
+			want: &model.NearbyTigerPagination{
+				Tigers: []*model.NearbyTiger{
+					{
+						Tiger: &model.Tiger{
+							ID:            1,
+							Name:          "tiger-1",
+							DateOfBirth:   now,
+							Sex:           model.SexUnknown,
+							Status:        model.TigerStatusActive,
+							LastSeen:      now,
+							LastLatitude:  -7.550676,
+							LastLongitude: 110.828316,
+						},
+						DistanceKm: geo.NewPoint(-7.540676, 110.828316).GreatCircleDistance(geo.NewPoint(-7.550676, 110.828316)),
+					},
+				},
+				Total: 1,
+			},
+		},
+		{
+			name:     "should return no tigers given none within radius",
+			latitude: -7.540676,
+			radiusKm: 1,
+			want: &model.NearbyTigerPagination{
+				Tigers: []*model.NearbyTiger{},
+				Total:  0,
+			},
+		},
+		{
+			name:     "should return ErrInvalidRadius given negative radius",
+			latitude: -7.540676,
+			radiusKm: -1,
+			wantErr:  errs.RespError(entities.ErrInvalidRadius),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _, _ := Setup(t, now, false)
+
+			res, err := r.Query().TigersNear(context.Background(), tc.latitude, 110.828316, tc.radiusKm, 1, 10)
 
 			wantJS, _ := json.Marshal(tc.want)
 			resJS, _ := json.Marshal(res)
@@ -97,6 +177,118 @@ func TestQuery_SightingByTiger(t *testing.T) {
 	}
 }
 
+func TestQuery_SightingsInBounds(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		minLng float64
+		maxLng float64
+
+		want    []*model.Sighting
+		wantErr error
+	}{
+		{
+			name:   "should return sightings inside bounds and nil error",
+			minLng: 110,
+			maxLng: 111,
+			want: []*model.Sighting{
+				{
+					ID:        1,
+					Date:      now,
+					Latitude:  -7.550676,
+					Longitude: 110.828316,
+					TigerID:   1,
+					UserID:    1,
+					ImageURL:  new(string),
+				},
+			},
+		},
+		{
+			name:   "should return no sightings given bounds across the antimeridian",
+			minLng: 111,
+			maxLng: 110,
+			want:   nil,
+		},
+		{
+			name:    "should return ErrInvalidBounds given invalid longitude",
+			minLng:  110,
+			maxLng:  200,
+			wantErr: errs.RespError(entities.ErrInvalidBounds),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _, _ := Setup(t, now, false)
+
+			res, err := r.Query().SightingsInBounds(context.Background(), -8, tc.minLng, -7, tc.maxLng, nil, nil)
+
+			wantJS, _ := json.Marshal(tc.want)
+			resJS, _ := json.Marshal(res)
+
+			assert.Equal(t, string(wantJS), string(resJS))
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestQuery_SightingClusters(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		bounds model.Bounds
+		zoom   int
+
+		want    []*model.SightingCluster
+		wantErr error
+	}{
+		{
+			name:   "should return sighting clusters and nil error",
+			bounds: model.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111},
+			zoom:   5,
+			want: []*model.SightingCluster{
+				{
+					Count:     1,
+					Latitude:  -7.550676,
+					Longitude: 110.828316,
+					LastSeen:  now,
+					TigerID:   1,
+				},
+			},
+		},
+		{
+			name:    "should return ErrInvalidZoom given negative zoom",
+			bounds:  model.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111},
+			zoom:    -1,
+			wantErr: errs.RespError(entities.ErrInvalidZoom),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _, _ := Setup(t, now, false)
+
+			res, err := r.Query().SightingClusters(context.Background(), tc.bounds, tc.zoom)
+
+			wantJS, _ := json.Marshal(tc.want)
+			resJS, _ := json.Marshal(res)
+
+			assert.Equal(t, string(wantJS), string(resJS))
+			assert.Equal(t, tc.wantErr, err)
+
+			for _, c := range res {
+				tg, err := r.SightingCluster().Tiger(context.Background(), c)
+				assert.Nil(t, err)
+				assert.Equal(t, "tiger-1", tg.Name)
+			}
+		})
+	}
+}
+
 func TestSighting_Tiger(t *testing.T) {
 	now := time.Now()
 
@@ -112,6 +304,8 @@ func TestSighting_Tiger(t *testing.T) {
 				ID:            1,
 				Name:          "tiger-1",
 				DateOfBirth:   now,
+				Sex:           model.SexUnknown,
+				Status:        model.TigerStatusActive,
 				LastSeen:      now,
 				LastLatitude:  -7.550676,
 				LastLongitude: 110.828316,
@@ -216,3 +410,432 @@ func TestTiger_Sightings(t *testing.T) {
 		})
 	}
 }
+
+func TestTiger_HomeRange(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+
+	r, _, queue := Setup(t, now, false)
+
+	_, err := r.Tiger().HomeRange(ctx, &model.Tiger{ID: 1}, nil, nil, false)
+	assert.Equal(t, errs.RespError(entities.ErrNotEnoughSightings), err)
+
+	for i, pos := range [][2]float64{{-7.550676, 110.928316}, {-7.450676, 110.878316}} {
+		_, err := r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(time.Duration(i+1)*3*time.Hour), pos[0], pos[1]))
+		assert.Nil(t, err)
+		<-queue
+	}
+
+	res, err := r.Tiger().HomeRange(ctx, &model.Tiger{ID: 1}, nil, nil, true)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, res.Sightings)
+	assert.Equal(t, "Polygon", res.Mcp.Type)
+	assert.Greater(t, res.McpAreaKm2, 0.0)
+	assert.Equal(t, "MultiPolygon", res.Kde.Type)
+	assert.Greater(t, *res.KdeAreaKm2, res.McpAreaKm2)
+
+	from := now.Add(time.Hour)
+	_, err = r.Tiger().HomeRange(ctx, &model.Tiger{ID: 1}, &from, nil, false)
+	assert.Equal(t, errs.RespError(entities.ErrNotEnoughSightings), err)
+}
+
+func TestTiger_MovementStats(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+
+	r, _, queue := Setup(t, now, false)
+
+	_, err := r.Mutation().CreateSighting(ctx, newSighting(1, now.Add(time.Hour), -7.450676, 110.828316))
+	assert.Nil(t, err)
+	<-queue
+
+	tigers, err := r.Query().Tigers(ctx, 1, 10, nil)
+	assert.Nil(t, err)
+
+	res, err := r.Tiger().MovementStats(ctx, tigers.Tigers[0])
+	assert.Nil(t, err)
+
+	jump := geo.NewPoint(-7.550676, 110.828316).GreatCircleDistance(geo.NewPoint(-7.450676, 110.828316))
+	assert.Equal(t, &model.MovementStats{
+		TotalDistanceKm:            jump,
+		AverageDailyDisplacementKm: jump,
+		MaxJumpKm:                  jump,
+		DaysSinceLastSeen:          0,
+		DistinctReporters:          1,
+	}, res)
+}
+
+func TestTiger_Family(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+
+	r, _, _ := Setup(t, now, false)
+
+	born := now.AddDate(-5, 0, 0)
+	_, err := r.Mutation().UpdateTiger(ctx, 1, model.UpdateTiger{DateOfBirth: &born})
+	assert.Nil(t, err)
+
+	newTiger := func(name string, dateOfBirth time.Time, motherID uint) (*model.Tiger, error) {
+		return r.Mutation().CreateTiger(ctx, model.NewTiger{
+			Name:          name,
+			DateOfBirth:   dateOfBirth,
+			LastSeen:      now,
+			LastLatitude:  -7.550676,
+			LastLongitude: 110.828316,
+			MotherID:      &motherID,
+		})
+	}
+
+	cub1, err := newTiger("cub-1", now.AddDate(-2, 0, 0), 1)
+	assert.Nil(t, err)
+	cub2, err := newTiger("cub-2", now.AddDate(-1, 0, 0), 1)
+	assert.Nil(t, err)
+	grandcub, err := newTiger("grandcub", now, cub1.ID)
+	assert.Nil(t, err)
+
+	_, err = newTiger("too-old", now.AddDate(-3, 0, 0), cub1.ID)
+	assert.Equal(t, errs.RespError(entities.ErrParentNotOlder), err)
+
+	_, err = newTiger("orphan", now, 99)
+	assert.Equal(t, errs.RespError(entities.ErrParentNotFound), err)
+
+	family, err := r.Tiger().Family(ctx, &model.Tiger{ID: 1}, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, family.Ancestors)
+	assert.Empty(t, family.Siblings)
+	assert.Len(t, family.Descendants, 3)
+	assert.Equal(t, cub1.ID, family.Descendants[0].Tiger.ID)
+	assert.Equal(t, cub2.ID, family.Descendants[1].Tiger.ID)
+	assert.Equal(t, grandcub.ID, family.Descendants[2].Tiger.ID)
+	assert.Equal(t, 2, family.Descendants[2].Generation)
+
+	family, err = r.Tiger().Family(ctx, cub2, 1)
+	assert.Nil(t, err)
+	assert.Len(t, family.Ancestors, 1)
+	assert.Equal(t, uint(1), family.Ancestors[0].Tiger.ID)
+	assert.Len(t, family.Siblings, 1)
+	assert.Equal(t, cub1.ID, family.Siblings[0].ID)
+	assert.Empty(t, family.Descendants)
+
+	_, err = r.Tiger().Family(ctx, cub2, 6)
+	assert.Equal(t, errs.RespError(entities.ErrInvalidFamilyDepth), err)
+}
+
+func TestTiger_Family_Alias(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Role:  entities.RoleAdmin,
+	})
+
+	r, _, _ := Setup(t, now, false)
+
+	born := now.AddDate(-5, 0, 0)
+	_, err := r.Mutation().UpdateTiger(ctx, 1, model.UpdateTiger{DateOfBirth: &born})
+	assert.Nil(t, err)
+
+	duplicate, err := r.Mutation().CreateTiger(ctx, model.NewTiger{
+		Name:          "tiger-1-duplicate",
+		DateOfBirth:   born,
+		LastSeen:      now,
+		LastLatitude:  -7.550676,
+		LastLongitude: 110.828316,
+	})
+	assert.Nil(t, err)
+
+	_, err = r.Mutation().MergeTigers(ctx, duplicate.ID, 1)
+	assert.Nil(t, err)
+
+	// the cub is linked after the merge, through the alias id
+	cub, err := r.Mutation().CreateTiger(ctx, model.NewTiger{
+		Name:          "cub",
+		DateOfBirth:   now.AddDate(-1, 0, 0),
+		LastSeen:      now,
+		LastLatitude:  -7.550676,
+		LastLongitude: 110.828316,
+		MotherID:      &duplicate.ID,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, uint(1), *cub.MotherID)
+
+	family, err := r.Tiger().Family(ctx, &model.Tiger{ID: 1}, 1)
+	assert.Nil(t, err)
+	assert.Len(t, family.Descendants, 1)
+	assert.Equal(t, cub.ID, family.Descendants[0].Tiger.ID)
+}
+
+func TestQuery_SearchTigers(t *testing.T) {
+	now := time.Now()
+	ctx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+	})
+	adminCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Role:  entities.RoleAdmin,
+	})
+
+	r, _, _ := Setup(t, now, false)
+
+	for _, name := range []string{"Shere Khan", "Machali"} {
+		_, err := r.Mutation().CreateTiger(ctx, model.NewTiger{
+			Name:          name,
+			DateOfBirth:   now,
+			LastSeen:      now,
+			LastLatitude:  -7.550676,
+			LastLongitude: 110.828316,
+		})
+		assert.Nil(t, err)
+	}
+
+	_, err := r.Mutation().MergeTigers(adminCtx, 3, 2)
+	assert.Nil(t, err)
+
+	res, err := r.Query().SearchTigers(ctx, "sheer", 10)
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, uint(2), res[0].Tiger.ID)
+	assert.False(t, res[0].Alias)
+
+	res, err = r.Query().SearchTigers(ctx, "MACH", 10)
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, uint(2), res[0].Tiger.ID)
+	assert.Equal(t, "Machali", res[0].MatchedName)
+	assert.True(t, res[0].Alias)
+
+	res, err = r.Query().SearchTigers(ctx, "tiger", 10)
+	assert.Nil(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, uint(1), res[0].Tiger.ID)
+
+	_, err = r.Query().SearchTigers(ctx, "", 10)
+	assert.Equal(t, errs.RespError(entities.ErrInvalidSearchQuery), err)
+}
+
+func TestSighting_Exif(t *testing.T) {
+	now := time.Now()
+
+	// the photo was taken at 7° 33' 2.4" S, 110° 49' 41.94" E on 2024-03-01 06:30:00 +07:00
+	photo, err := os.ReadFile("../pkg/modules/sighting/testdata/camera-trap.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lat := -(7.0 + 33.0/60 + 2.4/3600)
+	lng := 110.0 + 49.0/60 + 41.94/3600
+	date := time.Date(2024, 3, 1, 6, 30, 0, 0, time.FixedZone("", 7*3600))
+
+	userCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Name:  "user-1",
+		Email: "email-1@example.com",
+	})
+	adminCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 2},
+		Role:  entities.RoleAdmin,
+	})
+
+	testCases := []struct {
+		name string
+
+		ctx        context.Context
+		sightingID uint
+		want       *model.SightingExif
+		wantErr    error
+	}{
+		{
+			name:       "should return exif data given admin",
+			ctx:        adminCtx,
+			sightingID: 2,
+			want:       &model.SightingExif{Latitude: &lat, Longitude: &lng, Date: &date},
+		},
+		{
+			name:       "should return nil given user who is not an admin",
+			ctx:        userCtx,
+			sightingID: 2,
+		},
+		{
+			name:       "should return nil given no user",
+			ctx:        context.Background(),
+			sightingID: 2,
+		},
+		{
+			name:       "should return nil given sighting without exif data",
+			ctx:        adminCtx,
+			sightingID: 1,
+		},
+		{
+			name:       "should return ErrSightingNotFound given sighting that does not exist",
+			ctx:        adminCtx,
+			sightingID: 99,
+			wantErr:    errs.RespError(entities.ErrSightingNotFound),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, mockS3, _ := Setup(t, now, false)
+
+			mockS3.
+				On("UploadImage", mock.Anything, mock.Anything, mock.Anything, "image/jpeg").
+				Return("https://example.com/camera-trap.jpg", nil).
+				Twice()
+
+			mockS3.
+				On("UploadPrivateImage", mock.Anything, mock.Anything, "camera-trap.jpg", "image/jpeg").
+				Return("camera-trap.jpg", nil).
+				Once()
+
+			// the sighting is reported away from the photo, which only flags it for review
+			input := newSighting(1, date, -7.25, 110.83)
+			input.Image = &graphql.Upload{
+				File:        bytes.NewReader(photo),
+				Filename:    "camera-trap.jpg",
+				Size:        int64(len(photo)),
+				ContentType: "image/jpeg",
+			}
+
+			_, err := r.Mutation().CreateSighting(userCtx, input)
+			assert.NoError(t, err)
+
+			res, err := r.Sighting().Exif(tc.ctx, &model.Sighting{ID: tc.sightingID})
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.want == nil {
+				assert.Nil(t, res)
+				return
+			}
+
+			if assert.NotNil(t, res) {
+				assert.InDelta(t, *tc.want.Latitude, *res.Latitude, 1e-9)
+				assert.InDelta(t, *tc.want.Longitude, *res.Longitude, 1e-9)
+				assert.True(t, tc.want.Date.Equal(*res.Date), "got %v", res.Date)
+			}
+		})
+	}
+}
+
+func TestSighting_ImageURL(t *testing.T) {
+	now := time.Now()
+
+	thumbURL := "https://example.com/thumb/tiger.png"
+	mediumURL := "https://example.com/medium/tiger.png"
+	originalURL := "https://originals.example.com/tiger.png?X-Amz-Signature=abc"
+
+	reporterCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 1},
+		Name:  "user-1",
+		Email: "email-1@example.com",
+	})
+	otherCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 2},
+		Role:  entities.RoleUser,
+	})
+	adminCtx := context.WithValue(context.Background(), user.KeyUser, &entities.User{
+		Model: gorm.Model{ID: 3},
+		Role:  entities.RoleAdmin,
+	})
+
+	testCases := []struct {
+		name string
+
+		ctx  context.Context
+		size model.ImageSize
+
+		wantPresign bool
+		want        *string
+	}{
+		{
+			name: "should return thumbnail url given THUMB",
+			ctx:  otherCtx,
+			size: model.ImageSizeThumb,
+			want: &thumbURL,
+		},
+		{
+			name: "should return medium url given MEDIUM",
+			ctx:  otherCtx,
+			size: model.ImageSizeMedium,
+			want: &mediumURL,
+		},
+		{
+			name:        "should return presigned url given ORIGINAL and user who reported the sighting",
+			ctx:         reporterCtx,
+			size:        model.ImageSizeOriginal,
+			wantPresign: true,
+			want:        &originalURL,
+		},
+		{
+			name:        "should return presigned url given ORIGINAL and admin",
+			ctx:         adminCtx,
+			size:        model.ImageSizeOriginal,
+			wantPresign: true,
+			want:        &originalURL,
+		},
+		{
+			name: "should return nil given ORIGINAL and user who did not report the sighting",
+			ctx:  otherCtx,
+			size: model.ImageSizeOriginal,
+		},
+		{
+			name: "should return nil given ORIGINAL and no user",
+			ctx:  context.Background(),
+			size: model.ImageSizeOriginal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, mockS3, _ := Setup(t, now, false)
+
+			mockS3.
+				On("UploadImage", mock.Anything, mock.Anything, "thumb/tiger.png", "image/jpeg").
+				Return(thumbURL, nil).
+				Once()
+
+			mockS3.
+				On("UploadImage", mock.Anything, mock.Anything, "medium/tiger.png", "image/jpeg").
+				Return(mediumURL, nil).
+				Once()
+
+			mockS3.
+				On("UploadPrivateImage", mock.Anything, mock.Anything, "tiger.png", "image/png").
+				Return("tiger.png", nil).
+				Once()
+
+			if tc.wantPresign {
+				mockS3.
+					On("PresignPrivateImage", mock.Anything, "tiger.png").
+					Return(originalURL, nil).
+					Once()
+			}
+
+			input := newSighting(1, now.Add(-24*time.Hour), -7.25, 110.83)
+			input.Image = testutil.PNGUpload(t, image.NewGray(image.Rect(0, 0, 64, 48)))
+
+			s, err := r.Mutation().CreateSighting(reporterCtx, input)
+			assert.NoError(t, err)
+
+			res, err := r.Sighting().ImageURL(tc.ctx, s, tc.size)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestSighting_ImageURL_ThumbnailOnly(t *testing.T) {
+	url := "https://example.com/tiger.png"
+	r, _, _ := Setup(t, time.Now(), false)
+
+	// sightings reported before medium renditions were stored only have a thumbnail
+	res, err := r.Sighting().ImageURL(context.Background(), &model.Sighting{ID: 1, ImageURL: &url}, model.ImageSizeMedium)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &url, res)
+}
@@ -10,16 +10,22 @@ type Resolver struct {
 	userUsecase     entities.UserUsecase
 	tigerUsecase    entities.TigerUsecase
 	sightingUsecase entities.SightingUsecase
+	reserveUsecase  entities.ReserveUsecase
+	importUsecase   entities.ImportUsecase
 }
 
 func NewResolver(
 	userUsecase entities.UserUsecase,
 	tigerUsecase entities.TigerUsecase,
 	sightingUsecase entities.SightingUsecase,
+	reserveUsecase entities.ReserveUsecase,
+	importUsecase entities.ImportUsecase,
 ) *Resolver {
 	return &Resolver{
 		userUsecase:     userUsecase,
 		tigerUsecase:    tigerUsecase,
 		sightingUsecase: sightingUsecase,
+		reserveUsecase:  reserveUsecase,
+		importUsecase:   importUsecase,
 	}
 }
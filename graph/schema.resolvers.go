@@ -6,7 +6,10 @@ package graph
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/user"
@@ -32,6 +35,93 @@ func (r *mutationResolver) CreateTiger(ctx context.Context, input model.NewTiger
 	return t, nil
 }
 
+// UpdateTiger is the resolver for the updateTiger field.
+func (r *mutationResolver) UpdateTiger(ctx context.Context, id uint, input model.UpdateTiger) (*model.Tiger, error) {
+	u, err := user.UserByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+	if u.ID == 0 {
+		return nil, errs.RespError(entities.ErrUserByCtxNotFound)
+	}
+
+	t, err := r.tigerUsecase.UpdateTiger(ctx, id, &input)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return t, nil
+}
+
+// DeleteTiger is the resolver for the deleteTiger field.
+func (r *mutationResolver) DeleteTiger(ctx context.Context, id uint) (bool, error) {
+	u, err := user.UserByCtx(ctx)
+	if err != nil {
+		return false, errs.RespError(err)
+	}
+	if u.ID == 0 {
+		return false, errs.RespError(entities.ErrUserByCtxNotFound)
+	}
+
+	err = r.tigerUsecase.DeleteTiger(ctx, id)
+	if err != nil {
+		return false, errs.RespError(err)
+	}
+
+	return true, nil
+}
+
+// RestoreTiger is the resolver for the restoreTiger field.
+func (r *mutationResolver) RestoreTiger(ctx context.Context, id uint) (*model.Tiger, error) {
+	u, err := user.UserByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+	if u.ID == 0 {
+		return nil, errs.RespError(entities.ErrUserByCtxNotFound)
+	}
+
+	t, err := r.tigerUsecase.RestoreTiger(ctx, id)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return t, nil
+}
+
+// UpdateTigerStatus is the resolver for the updateTigerStatus field.
+func (r *mutationResolver) UpdateTigerStatus(ctx context.Context, id uint, input model.TigerStatusInput) (*model.Tiger, error) {
+	u, err := user.UserByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+	if u.ID == 0 {
+		return nil, errs.RespError(entities.ErrUserByCtxNotFound)
+	}
+
+	t, err := r.tigerUsecase.UpdateTigerStatus(ctx, id, &input, u.ID)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return t, nil
+}
+
+// MergeTigers is the resolver for the mergeTigers field.
+func (r *mutationResolver) MergeTigers(ctx context.Context, sourceID uint, targetID uint) (*model.Tiger, error) {
+	u, err := user.AdminByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	t, err := r.tigerUsecase.MergeTigers(ctx, sourceID, targetID, u.ID)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return t, nil
+}
+
 // CreateSighting is the resolver for the createSighting field.
 func (r *mutationResolver) CreateSighting(ctx context.Context, input model.NewSighting) (*model.Sighting, error) {
 	u, err := user.UserByCtx(ctx)
@@ -50,6 +140,95 @@ func (r *mutationResolver) CreateSighting(ctx context.Context, input model.NewSi
 	return s, nil
 }
 
+// UpdateSighting is the resolver for the updateSighting field.
+func (r *mutationResolver) UpdateSighting(ctx context.Context, id uint, input model.UpdateSighting) (*model.Sighting, error) {
+	u, err := user.UserByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+	if u.ID == 0 {
+		return nil, errs.RespError(entities.ErrUserByCtxNotFound)
+	}
+
+	s, err := r.sightingUsecase.UpdateSighting(ctx, id, &input, u.ID)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return s, nil
+}
+
+// DeleteSighting is the resolver for the deleteSighting field.
+func (r *mutationResolver) DeleteSighting(ctx context.Context, id uint) (bool, error) {
+	u, err := user.UserByCtx(ctx)
+	if err != nil {
+		return false, errs.RespError(err)
+	}
+	if u.ID == 0 {
+		return false, errs.RespError(entities.ErrUserByCtxNotFound)
+	}
+
+	err = r.sightingUsecase.DeleteSighting(ctx, id, u.ID)
+	if err != nil {
+		return false, errs.RespError(err)
+	}
+
+	return true, nil
+}
+
+// CreateReserve is the resolver for the createReserve field.
+func (r *mutationResolver) CreateReserve(ctx context.Context, input model.NewReserve) (*model.Reserve, error) {
+	_, err := user.AdminByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	res, err := r.reserveUsecase.CreateReserve(ctx, &input)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
+// UpdateReserve is the resolver for the updateReserve field.
+func (r *mutationResolver) UpdateReserve(ctx context.Context, id uint, input model.UpdateReserve) (*model.Reserve, error) {
+	_, err := user.AdminByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	res, err := r.reserveUsecase.UpdateReserve(ctx, id, &input)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
+// ImportCSV is the resolver for the importCsv field.
+func (r *mutationResolver) ImportCSV(ctx context.Context, tigers *graphql.Upload, sightings *graphql.Upload, dryRun bool) (*model.ImportReport, error) {
+	u, err := user.AdminByCtx(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	var tigersFile, sightingsFile io.Reader
+	if tigers != nil {
+		tigersFile = tigers.File
+	}
+	if sightings != nil {
+		sightingsFile = sightings.File
+	}
+
+	res, err := r.importUsecase.Import(ctx, tigersFile, sightingsFile, u.ID, dryRun)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
 // CreateUser is the resolver for the createUser field.
 func (r *mutationResolver) CreateUser(ctx context.Context, input model.NewUser) (string, error) {
 	token, err := r.userUsecase.CreateUser(ctx, &input)
@@ -81,8 +260,8 @@ func (r *mutationResolver) RefreshToken(ctx context.Context, token string) (stri
 }
 
 // Tigers is the resolver for the tigers field.
-func (r *queryResolver) Tigers(ctx context.Context, page int, pageSize int) (*model.TigerPagination, error) {
-	tigers, count, err := r.tigerUsecase.GetTigers(ctx, page, pageSize)
+func (r *queryResolver) Tigers(ctx context.Context, page int, pageSize int, filter *model.TigerFilter) (*model.TigerPagination, error) {
+	tigers, count, err := r.tigerUsecase.GetTigers(ctx, filter, page, pageSize)
 	if err != nil {
 		return nil, errs.RespError(err)
 	}
@@ -93,6 +272,39 @@ func (r *queryResolver) Tigers(ctx context.Context, page int, pageSize int) (*mo
 	}, nil
 }
 
+// SearchTigers is the resolver for the searchTigers field.
+func (r *queryResolver) SearchTigers(ctx context.Context, query string, limit int) ([]*model.TigerSearchResult, error) {
+	res, err := r.tigerUsecase.SearchTigers(ctx, query, limit)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
+// SuggestTigers is the resolver for the suggestTigers field.
+func (r *queryResolver) SuggestTigers(ctx context.Context, image graphql.Upload, limit int) ([]*model.TigerSuggestion, error) {
+	res, err := r.tigerUsecase.SuggestTigers(ctx, &image, limit)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
+// TigersNear is the resolver for the tigersNear field.
+func (r *queryResolver) TigersNear(ctx context.Context, latitude float64, longitude float64, radiusKm float64, page int, pageSize int) (*model.NearbyTigerPagination, error) {
+	tigers, count, err := r.tigerUsecase.GetTigersNear(ctx, latitude, longitude, radiusKm, page, pageSize)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return &model.NearbyTigerPagination{
+		Tigers: tigers,
+		Total:  count,
+	}, nil
+}
+
 // SightingByTiger is the resolver for the sightingByTiger field.
 func (r *queryResolver) SightingByTiger(ctx context.Context, tigerID uint, page int, pageSize int) (*model.SightingsPagination, error) {
 	sightings, count, err := r.sightingUsecase.GetSightingsByTigerID(ctx, tigerID, page, pageSize)
@@ -106,6 +318,48 @@ func (r *queryResolver) SightingByTiger(ctx context.Context, tigerID uint, page
 	}, nil
 }
 
+// SightingsInBounds is the resolver for the sightingsInBounds field.
+func (r *queryResolver) SightingsInBounds(ctx context.Context, minLat float64, minLng float64, maxLat float64, maxLng float64, from *time.Time, to *time.Time) ([]*model.Sighting, error) {
+	bounds := entities.Bounds{
+		MinLat: minLat,
+		MinLng: minLng,
+		MaxLat: maxLat,
+		MaxLng: maxLng,
+	}
+
+	sightings, err := r.sightingUsecase.GetSightingsInBounds(ctx, bounds, from, to)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return sightings, nil
+}
+
+// SightingClusters is the resolver for the sightingClusters field.
+func (r *queryResolver) SightingClusters(ctx context.Context, bounds model.Bounds, zoom int) ([]*model.SightingCluster, error) {
+	clusters, err := r.sightingUsecase.GetSightingClusters(ctx, entities.Bounds{
+		MinLat: bounds.MinLat,
+		MinLng: bounds.MinLng,
+		MaxLat: bounds.MaxLat,
+		MaxLng: bounds.MaxLng,
+	}, zoom)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return clusters, nil
+}
+
+// Reserves is the resolver for the reserves field.
+func (r *queryResolver) Reserves(ctx context.Context) ([]*model.Reserve, error) {
+	reserves, err := r.reserveUsecase.GetReserves(ctx)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return reserves, nil
+}
+
 // Tiger is the resolver for the tiger field.
 func (r *sightingResolver) Tiger(ctx context.Context, obj *model.Sighting) (*model.Tiger, error) {
 	if obj == nil || obj.TigerID == 0 {
@@ -134,6 +388,105 @@ func (r *sightingResolver) User(ctx context.Context, obj *model.Sighting) (*mode
 	return u, nil
 }
 
+// ImageURL is the resolver for the imageURL field.
+func (r *sightingResolver) ImageURL(ctx context.Context, obj *model.Sighting, size model.ImageSize) (*string, error) {
+	if obj == nil {
+		return nil, nil
+	}
+
+	switch size {
+	case model.ImageSizeMedium:
+		if obj.MediumImageURL != "" {
+			return &obj.MediumImageURL, nil
+		}
+	case model.ImageSizeOriginal:
+		u, err := user.UserByCtx(ctx)
+		if err != nil || obj.ID == 0 {
+			return nil, nil
+		}
+
+		url, err := r.sightingUsecase.GetOriginalImageURL(ctx, obj.ID, u)
+		if err != nil {
+			return nil, errs.RespError(err)
+		}
+
+		return url, nil
+	}
+
+	return obj.ImageURL, nil
+}
+
+// Exif is the resolver for the exif field.
+func (r *sightingResolver) Exif(ctx context.Context, obj *model.Sighting) (*model.SightingExif, error) {
+	if obj == nil || obj.ID == 0 {
+		return nil, nil
+	}
+
+	// the EXIF data can reveal where a tiger lives, so other users do not get it at all
+	if _, err := user.AdminByCtx(ctx); err != nil {
+		return nil, nil
+	}
+
+	e, err := r.sightingUsecase.GetSightingExif(ctx, obj.ID)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return e, nil
+}
+
+// Tiger is the resolver for the tiger field.
+func (r *sightingClusterResolver) Tiger(ctx context.Context, obj *model.SightingCluster) (*model.Tiger, error) {
+	if obj == nil || obj.TigerID == 0 {
+		return nil, nil
+	}
+
+	t, err := r.tigerUsecase.GetTigerByID(ctx, obj.TigerID)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return t, nil
+}
+
+// StatusHistory is the resolver for the statusHistory field.
+func (r *tigerResolver) StatusHistory(ctx context.Context, obj *model.Tiger) ([]*model.TigerStatusChange, error) {
+	if obj == nil || obj.ID == 0 {
+		return nil, nil
+	}
+
+	res, err := r.tigerUsecase.GetStatusHistory(ctx, obj.ID)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
+// Aliases is the resolver for the aliases field.
+func (r *tigerResolver) Aliases(ctx context.Context, obj *model.Tiger) ([]*model.TigerAlias, error) {
+	if obj == nil || obj.ID == 0 {
+		return nil, nil
+	}
+
+	res, err := r.tigerUsecase.GetAliases(ctx, obj.ID)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
+// Family is the resolver for the family field.
+func (r *tigerResolver) Family(ctx context.Context, obj *model.Tiger, depth int) (*model.Family, error) {
+	res, err := r.tigerUsecase.GetFamily(ctx, obj.ID, depth)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
 // Sightings is the resolver for the sightings field.
 func (r *tigerResolver) Sightings(ctx context.Context, obj *model.Tiger) ([]*model.Sighting, error) {
 	if obj == nil || obj.ID == 0 {
@@ -148,6 +501,26 @@ func (r *tigerResolver) Sightings(ctx context.Context, obj *model.Tiger) ([]*mod
 	return sightings, nil
 }
 
+// HomeRange is the resolver for the homeRange field.
+func (r *tigerResolver) HomeRange(ctx context.Context, obj *model.Tiger, from *time.Time, to *time.Time, kde bool) (*model.HomeRange, error) {
+	res, err := r.tigerUsecase.GetHomeRange(ctx, obj.ID, from, to, kde)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return res, nil
+}
+
+// MovementStats is the resolver for the movementStats field.
+func (r *tigerResolver) MovementStats(ctx context.Context, obj *model.Tiger) (*model.MovementStats, error) {
+	stats, err := r.tigerUsecase.GetMovementStats(ctx, obj)
+	if err != nil {
+		return nil, errs.RespError(err)
+	}
+
+	return stats, nil
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
@@ -157,12 +530,16 @@ func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 // Sighting returns SightingResolver implementation.
 func (r *Resolver) Sighting() SightingResolver { return &sightingResolver{r} }
 
+// SightingCluster returns SightingClusterResolver implementation.
+func (r *Resolver) SightingCluster() SightingClusterResolver { return &sightingClusterResolver{r} }
+
 // Tiger returns TigerResolver implementation.
 func (r *Resolver) Tiger() TigerResolver { return &tigerResolver{r} }
 
 type (
-	mutationResolver struct{ *Resolver }
-	queryResolver    struct{ *Resolver }
-	sightingResolver struct{ *Resolver }
-	tigerResolver    struct{ *Resolver }
+	mutationResolver        struct{ *Resolver }
+	queryResolver           struct{ *Resolver }
+	sightingResolver        struct{ *Resolver }
+	sightingClusterResolver struct{ *Resolver }
+	tigerResolver           struct{ *Resolver }
 )
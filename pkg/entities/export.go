@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// SightingFilter limits the sightings of an export, nil fields match every sighting.
+type SightingFilter struct {
+	TigerID *uint
+	UserID  *uint
+	From    *time.Time
+	To      *time.Time
+	Bounds  *Bounds
+}
+
+// Validate checks the date range and the bounds of the filter.
+func (f SightingFilter) Validate() error {
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return ErrInvalidDateRange
+	}
+
+	if f.Bounds != nil {
+		return f.Bounds.Validate()
+	}
+
+	return nil
+}
+
+// SightingRecord is a sighting flattened together with the names of its tiger and
+// reporter, as a row of a tabular export.
+type SightingRecord struct {
+	ID          uint
+	Date        time.Time
+	Latitude    float64
+	Longitude   float64
+	TigerID     uint
+	TigerName   string
+	UserID      uint
+	UserName    string
+	ImageURL    string
+	NeedsReview bool
+}
+
+type ExportUsecase interface {
+	GetLastPositions(ctx context.Context) ([]Tiger, error)
+	GetTrack(ctx context.Context, tigerID uint, from, to *time.Time) (*Tiger, []Sighting, error)
+	// EachSighting calls fn for every sighting matching filter in chronological order,
+	// without loading them all into memory. It stops at the first error fn returns.
+	EachSighting(ctx context.Context, filter SightingFilter, fn func(*SightingRecord) error) error
+}
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/errs"
+)
+
+var (
+	ErrInvalidCSVHeader = errs.ServiceError{
+		ErrorCode: "ErrInvalidCSVHeader",
+		Err:       errors.New("ErrInvalidCSVHeader: CSV file is missing required columns"),
+	}
+	ErrInvalidImportRow = errs.ServiceError{
+		ErrorCode: "ErrInvalidImportRow",
+		Err:       errors.New("ErrInvalidImportRow: row has invalid values"),
+	}
+)
+
+// NewErrInvalidCSVHeader returns ErrInvalidCSVHeader naming the file and the columns it misses.
+func NewErrInvalidCSVHeader(file string, missing []string) error {
+	return ErrInvalidCSVHeader.WithDetails(
+		fmt.Errorf("ErrInvalidCSVHeader: %s file is missing columns %v", file, missing),
+		map[string]interface{}{"file": file, "missingColumns": missing},
+	)
+}
+
+// NewErrInvalidImportRow returns ErrInvalidImportRow with the reason the row is invalid.
+func NewErrInvalidImportRow(reason string) error {
+	return ErrInvalidImportRow.WithDetails(fmt.Errorf("ErrInvalidImportRow: %s", reason), nil)
+}
+
+type ImportUsecase interface {
+	// Import stores the tigers and then the sightings of the given CSV files, either of
+	// which can be nil. Rows that fail validation are skipped and listed in the report.
+	// With dryRun set, the files are validated the same way but nothing is stored.
+	Import(ctx context.Context, tigers, sightings io.Reader, userID uint, dryRun bool) (*model.ImportReport, error)
+}
@@ -0,0 +1,106 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ExportUsecase is an autogenerated mock type for the ExportUsecase type
+type ExportUsecase struct {
+	mock.Mock
+}
+
+// EachSighting provides a mock function with given fields: ctx, filter, fn
+func (_m *ExportUsecase) EachSighting(ctx context.Context, filter entities.SightingFilter, fn func(*entities.SightingRecord) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.SightingFilter, func(*entities.SightingRecord) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLastPositions provides a mock function with given fields: ctx
+func (_m *ExportUsecase) GetLastPositions(ctx context.Context) ([]entities.Tiger, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entities.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.Tiger, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Tiger); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrack provides a mock function with given fields: ctx, tigerID, from, to
+func (_m *ExportUsecase) GetTrack(ctx context.Context, tigerID uint, from *time.Time, to *time.Time) (*entities.Tiger, []entities.Sighting, error) {
+	ret := _m.Called(ctx, tigerID, from, to)
+
+	var r0 *entities.Tiger
+	var r1 []entities.Sighting
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *time.Time, *time.Time) (*entities.Tiger, []entities.Sighting, error)); ok {
+		return rf(ctx, tigerID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *time.Time, *time.Time) *entities.Tiger); ok {
+		r0 = rf(ctx, tigerID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *time.Time, *time.Time) []entities.Sighting); ok {
+		r1 = rf(ctx, tigerID, from, to)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]entities.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint, *time.Time, *time.Time) error); ok {
+		r2 = rf(ctx, tigerID, from, to)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewExportUsecase creates a new instance of ExportUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExportUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExportUsecase {
+	mock := &ExportUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
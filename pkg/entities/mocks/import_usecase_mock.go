@@ -0,0 +1,58 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+)
+
+// ImportUsecase is an autogenerated mock type for the ImportUsecase type
+type ImportUsecase struct {
+	mock.Mock
+}
+
+// Import provides a mock function with given fields: ctx, tigers, sightings, userID, dryRun
+func (_m *ImportUsecase) Import(ctx context.Context, tigers io.Reader, sightings io.Reader, userID uint, dryRun bool) (*model.ImportReport, error) {
+	ret := _m.Called(ctx, tigers, sightings, userID, dryRun)
+
+	var r0 *model.ImportReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, io.Reader, uint, bool) (*model.ImportReport, error)); ok {
+		return rf(ctx, tigers, sightings, userID, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, io.Reader, uint, bool) *model.ImportReport); ok {
+		r0 = rf(ctx, tigers, sightings, userID, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ImportReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, io.Reader, uint, bool) error); ok {
+		r1 = rf(ctx, tigers, sightings, userID, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewImportUsecase creates a new instance of ImportUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewImportUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ImportUsecase {
+	mock := &ImportUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReserveRepository is an autogenerated mock type for the ReserveRepository type
+type ReserveRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, reserve
+func (_m *ReserveRepository) Create(ctx context.Context, reserve *entities.Reserve) error {
+	ret := _m.Called(ctx, reserve)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Reserve) error); ok {
+		r0 = rf(ctx, reserve)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAll provides a mock function with given fields: ctx
+func (_m *ReserveRepository) FindAll(ctx context.Context) ([]entities.Reserve, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entities.Reserve
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.Reserve, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Reserve); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Reserve)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *ReserveRepository) FindByID(ctx context.Context, id uint) (*entities.Reserve, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entities.Reserve
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Reserve, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Reserve); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Reserve)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, reserve, id
+func (_m *ReserveRepository) Update(ctx context.Context, reserve *entities.Reserve, id uint) error {
+	ret := _m.Called(ctx, reserve, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Reserve, uint) error); ok {
+		r0 = rf(ctx, reserve, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReserveRepository creates a new instance of ReserveRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReserveRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReserveRepository {
+	mock := &ReserveRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
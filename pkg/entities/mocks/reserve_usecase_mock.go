@@ -0,0 +1,108 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+)
+
+// ReserveUsecase is an autogenerated mock type for the ReserveUsecase type
+type ReserveUsecase struct {
+	mock.Mock
+}
+
+// CreateReserve provides a mock function with given fields: ctx, reserve
+func (_m *ReserveUsecase) CreateReserve(ctx context.Context, reserve *model.NewReserve) (*model.Reserve, error) {
+	ret := _m.Called(ctx, reserve)
+
+	var r0 *model.Reserve
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.NewReserve) (*model.Reserve, error)); ok {
+		return rf(ctx, reserve)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.NewReserve) *model.Reserve); ok {
+		r0 = rf(ctx, reserve)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Reserve)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.NewReserve) error); ok {
+		r1 = rf(ctx, reserve)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReserves provides a mock function with given fields: ctx
+func (_m *ReserveUsecase) GetReserves(ctx context.Context) ([]*model.Reserve, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*model.Reserve
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.Reserve, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.Reserve); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Reserve)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateReserve provides a mock function with given fields: ctx, id, reserve
+func (_m *ReserveUsecase) UpdateReserve(ctx context.Context, id uint, reserve *model.UpdateReserve) (*model.Reserve, error) {
+	ret := _m.Called(ctx, id, reserve)
+
+	var r0 *model.Reserve
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateReserve) (*model.Reserve, error)); ok {
+		return rf(ctx, id, reserve)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateReserve) *model.Reserve); ok {
+		r0 = rf(ctx, id, reserve)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Reserve)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.UpdateReserve) error); ok {
+		r1 = rf(ctx, id, reserve)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReserveUsecase creates a new instance of ReserveUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReserveUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReserveUsecase {
+	mock := &ReserveUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
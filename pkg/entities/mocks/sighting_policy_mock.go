@@ -0,0 +1,53 @@
+// Code generated by mockery v2.32.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entities "github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SightingPolicy is an autogenerated mock type for the SightingPolicy type
+type SightingPolicy struct {
+	mock.Mock
+}
+
+// RuleFor provides a mock function with given fields: ctx, tiger
+func (_m *SightingPolicy) RuleFor(ctx context.Context, tiger *entities.Tiger) (entities.SightingRule, error) {
+	ret := _m.Called(ctx, tiger)
+
+	var r0 entities.SightingRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Tiger) (entities.SightingRule, error)); ok {
+		return rf(ctx, tiger)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Tiger) entities.SightingRule); ok {
+		r0 = rf(ctx, tiger)
+	} else {
+		r0 = ret.Get(0).(entities.SightingRule)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.Tiger) error); ok {
+		r1 = rf(ctx, tiger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSightingPolicy creates a new instance of SightingPolicy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSightingPolicy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SightingPolicy {
+	mock := &SightingPolicy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -6,9 +6,13 @@ import (
 	context "context"
 
 	entities "github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	imageproc "github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
+
 	mock "github.com/stretchr/testify/mock"
 
 	scopes "github.com/muhwyndhamhp/tigerhall-kittens/utils/scopes"
+
+	time "time"
 )
 
 // SightingRepository is an autogenerated mock type for the SightingRepository type
@@ -16,6 +20,32 @@ type SightingRepository struct {
 	mock.Mock
 }
 
+// ClusterInBounds provides a mock function with given fields: ctx, bounds, cellSize
+func (_m *SightingRepository) ClusterInBounds(ctx context.Context, bounds entities.Bounds, cellSize float64) ([]entities.SightingCluster, error) {
+	ret := _m.Called(ctx, bounds, cellSize)
+
+	var r0 []entities.SightingCluster
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, float64) ([]entities.SightingCluster, error)); ok {
+		return rf(ctx, bounds, cellSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, float64) []entities.SightingCluster); ok {
+		r0 = rf(ctx, bounds, cellSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.SightingCluster)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Bounds, float64) error); ok {
+		r1 = rf(ctx, bounds, cellSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, sighting
 func (_m *SightingRepository) Create(ctx context.Context, sighting *entities.Sighting) error {
 	ret := _m.Called(ctx, sighting)
@@ -30,6 +60,81 @@ func (_m *SightingRepository) Create(ctx context.Context, sighting *entities.Sig
 	return r0
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *SightingRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAdjacent provides a mock function with given fields: ctx, tigerID, date, excludeID
+func (_m *SightingRepository) FindAdjacent(ctx context.Context, tigerID uint, date time.Time, excludeID uint) (*entities.Sighting, *entities.Sighting, error) {
+	ret := _m.Called(ctx, tigerID, date, excludeID)
+
+	var r0 *entities.Sighting
+	var r1 *entities.Sighting
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time, uint) (*entities.Sighting, *entities.Sighting, error)); ok {
+		return rf(ctx, tigerID, date, excludeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time, uint) *entities.Sighting); ok {
+		r0 = rf(ctx, tigerID, date, excludeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, time.Time, uint) *entities.Sighting); ok {
+		r1 = rf(ctx, tigerID, date, excludeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*entities.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint, time.Time, uint) error); ok {
+		r2 = rf(ctx, tigerID, date, excludeID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindByID provides a mock function with given fields: ctx, id
+func (_m *SightingRepository) FindByID(ctx context.Context, id uint) (*entities.Sighting, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entities.Sighting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*entities.Sighting, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *entities.Sighting); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByTigerID provides a mock function with given fields: ctx, tigerID, preloads, page, pageSize
 func (_m *SightingRepository) FindByTigerID(ctx context.Context, tigerID uint, preloads []scopes.Preload, page int, pageSize int) ([]entities.Sighting, int, error) {
 	ret := _m.Called(ctx, tigerID, preloads, page, pageSize)
@@ -63,6 +168,150 @@ func (_m *SightingRepository) FindByTigerID(ctx context.Context, tigerID uint, p
 	return r0, r1, r2
 }
 
+// FindDuplicateImage provides a mock function with given fields: ctx, hash, beforeID
+func (_m *SightingRepository) FindDuplicateImage(ctx context.Context, hash imageproc.Hash, beforeID uint) (uint, error) {
+	ret := _m.Called(ctx, hash, beforeID)
+
+	var r0 uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, imageproc.Hash, uint) (uint, error)); ok {
+		return rf(ctx, hash, beforeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, imageproc.Hash, uint) uint); ok {
+		r0 = rf(ctx, hash, beforeID)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, imageproc.Hash, uint) error); ok {
+		r1 = rf(ctx, hash, beforeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEach provides a mock function with given fields: ctx, filter, fn
+func (_m *SightingRepository) FindEach(ctx context.Context, filter entities.SightingFilter, fn func(*entities.SightingRecord) error) error {
+	ret := _m.Called(ctx, filter, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.SightingFilter, func(*entities.SightingRecord) error) error); ok {
+		r0 = rf(ctx, filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindInBounds provides a mock function with given fields: ctx, bounds, from, to
+func (_m *SightingRepository) FindInBounds(ctx context.Context, bounds entities.Bounds, from *time.Time, to *time.Time) ([]entities.Sighting, error) {
+	ret := _m.Called(ctx, bounds, from, to)
+
+	var r0 []entities.Sighting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, *time.Time, *time.Time) ([]entities.Sighting, error)); ok {
+		return rf(ctx, bounds, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, *time.Time, *time.Time) []entities.Sighting); ok {
+		r0 = rf(ctx, bounds, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Bounds, *time.Time, *time.Time) error); ok {
+		r1 = rf(ctx, bounds, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSignatures provides a mock function with given fields: ctx
+func (_m *SightingRepository) FindSignatures(ctx context.Context) ([]entities.SightingSignature, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entities.SightingSignature
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.SightingSignature, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.SightingSignature); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.SightingSignature)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTrack provides a mock function with given fields: ctx, tigerID, from, to
+func (_m *SightingRepository) FindTrack(ctx context.Context, tigerID uint, from *time.Time, to *time.Time) ([]entities.Sighting, error) {
+	ret := _m.Called(ctx, tigerID, from, to)
+
+	var r0 []entities.Sighting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *time.Time, *time.Time) ([]entities.Sighting, error)); ok {
+		return rf(ctx, tigerID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *time.Time, *time.Time) []entities.Sighting); ok {
+		r0 = rf(ctx, tigerID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *time.Time, *time.Time) error); ok {
+		r1 = rf(ctx, tigerID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReassignTiger provides a mock function with given fields: ctx, fromID, toID
+func (_m *SightingRepository) ReassignTiger(ctx context.Context, fromID uint, toID uint) error {
+	ret := _m.Called(ctx, fromID, toID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, fromID, toID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, sighting, id
+func (_m *SightingRepository) Update(ctx context.Context, sighting *entities.Sighting, id uint) error {
+	ret := _m.Called(ctx, sighting, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Sighting, uint) error); ok {
+		r0 = rf(ctx, sighting, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewSightingRepository creates a new instance of SightingRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSightingRepository(t interface {
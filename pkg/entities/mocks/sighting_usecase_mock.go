@@ -5,9 +5,12 @@ package mocks
 import (
 	context "context"
 
+	entities "github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+
+	time "time"
 )
 
 // SightingUsecase is an autogenerated mock type for the SightingUsecase type
@@ -41,6 +44,98 @@ func (_m *SightingUsecase) CreateSighting(ctx context.Context, sighting *model.N
 	return r0, r1
 }
 
+// DeleteSighting provides a mock function with given fields: ctx, id, userID
+func (_m *SightingUsecase) DeleteSighting(ctx context.Context, id uint, userID uint) error {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) error); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetOriginalImageURL provides a mock function with given fields: ctx, id, u
+func (_m *SightingUsecase) GetOriginalImageURL(ctx context.Context, id uint, u *entities.User) (*string, error) {
+	ret := _m.Called(ctx, id, u)
+
+	var r0 *string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *entities.User) (*string, error)); ok {
+		return rf(ctx, id, u)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *entities.User) *string); ok {
+		r0 = rf(ctx, id, u)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *entities.User) error); ok {
+		r1 = rf(ctx, id, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSightingClusters provides a mock function with given fields: ctx, bounds, zoom
+func (_m *SightingUsecase) GetSightingClusters(ctx context.Context, bounds entities.Bounds, zoom int) ([]*model.SightingCluster, error) {
+	ret := _m.Called(ctx, bounds, zoom)
+
+	var r0 []*model.SightingCluster
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, int) ([]*model.SightingCluster, error)); ok {
+		return rf(ctx, bounds, zoom)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, int) []*model.SightingCluster); ok {
+		r0 = rf(ctx, bounds, zoom)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SightingCluster)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Bounds, int) error); ok {
+		r1 = rf(ctx, bounds, zoom)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSightingExif provides a mock function with given fields: ctx, id
+func (_m *SightingUsecase) GetSightingExif(ctx context.Context, id uint) (*model.SightingExif, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.SightingExif
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.SightingExif, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.SightingExif); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SightingExif)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSightingsByTigerID provides a mock function with given fields: ctx, tigerID, page, pageSize
 func (_m *SightingUsecase) GetSightingsByTigerID(ctx context.Context, tigerID uint, page int, pageSize int) ([]*model.Sighting, int, error) {
 	ret := _m.Called(ctx, tigerID, page, pageSize)
@@ -74,6 +169,84 @@ func (_m *SightingUsecase) GetSightingsByTigerID(ctx context.Context, tigerID ui
 	return r0, r1, r2
 }
 
+// GetSightingsInBounds provides a mock function with given fields: ctx, bounds, from, to
+func (_m *SightingUsecase) GetSightingsInBounds(ctx context.Context, bounds entities.Bounds, from *time.Time, to *time.Time) ([]*model.Sighting, error) {
+	ret := _m.Called(ctx, bounds, from, to)
+
+	var r0 []*model.Sighting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, *time.Time, *time.Time) ([]*model.Sighting, error)); ok {
+		return rf(ctx, bounds, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entities.Bounds, *time.Time, *time.Time) []*model.Sighting); ok {
+		r0 = rf(ctx, bounds, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entities.Bounds, *time.Time, *time.Time) error); ok {
+		r1 = rf(ctx, bounds, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportSighting provides a mock function with given fields: ctx, sighting, userID
+func (_m *SightingUsecase) ImportSighting(ctx context.Context, sighting *model.NewSighting, userID uint) (*model.Sighting, error) {
+	ret := _m.Called(ctx, sighting, userID)
+
+	var r0 *model.Sighting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.NewSighting, uint) (*model.Sighting, error)); ok {
+		return rf(ctx, sighting, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.NewSighting, uint) *model.Sighting); ok {
+		r0 = rf(ctx, sighting, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.NewSighting, uint) error); ok {
+		r1 = rf(ctx, sighting, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSighting provides a mock function with given fields: ctx, id, sighting, userID
+func (_m *SightingUsecase) UpdateSighting(ctx context.Context, id uint, sighting *model.UpdateSighting, userID uint) (*model.Sighting, error) {
+	ret := _m.Called(ctx, id, sighting, userID)
+
+	var r0 *model.Sighting
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateSighting, uint) (*model.Sighting, error)); ok {
+		return rf(ctx, id, sighting, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateSighting, uint) *model.Sighting); ok {
+		r0 = rf(ctx, id, sighting, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Sighting)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.UpdateSighting, uint) error); ok {
+		r1 = rf(ctx, id, sighting, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewSightingUsecase creates a new instance of SightingUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewSightingUsecase(t interface {
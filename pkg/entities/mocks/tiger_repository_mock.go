@@ -7,6 +7,8 @@ import (
 
 	entities "github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 )
 
 // TigerRepository is an autogenerated mock type for the TigerRepository type
@@ -28,32 +30,86 @@ func (_m *TigerRepository) Create(ctx context.Context, tiger *entities.Tiger) er
 	return r0
 }
 
-// FindAll provides a mock function with given fields: ctx, page, pageSize
-func (_m *TigerRepository) FindAll(ctx context.Context, page int, pageSize int) ([]entities.Tiger, int, error) {
-	ret := _m.Called(ctx, page, pageSize)
+// CreateStatusChange provides a mock function with given fields: ctx, change
+func (_m *TigerRepository) CreateStatusChange(ctx context.Context, change *entities.TigerStatusChange) error {
+	ret := _m.Called(ctx, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.TigerStatusChange) error); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *TigerRepository) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAliases provides a mock function with given fields: ctx, tigerID
+func (_m *TigerRepository) FindAliases(ctx context.Context, tigerID uint) ([]entities.Tiger, error) {
+	ret := _m.Called(ctx, tigerID)
+
+	var r0 []entities.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]entities.Tiger, error)); ok {
+		return rf(ctx, tigerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []entities.Tiger); ok {
+		r0 = rf(ctx, tigerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, tigerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAll provides a mock function with given fields: ctx, filter, page, pageSize
+func (_m *TigerRepository) FindAll(ctx context.Context, filter *model.TigerFilter, page int, pageSize int) ([]entities.Tiger, int, error) {
+	ret := _m.Called(ctx, filter, page, pageSize)
 
 	var r0 []entities.Tiger
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]entities.Tiger, int, error)); ok {
-		return rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TigerFilter, int, int) ([]entities.Tiger, int, error)); ok {
+		return rf(ctx, filter, page, pageSize)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []entities.Tiger); ok {
-		r0 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TigerFilter, int, int) []entities.Tiger); ok {
+		r0 = rf(ctx, filter, page, pageSize)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]entities.Tiger)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.TigerFilter, int, int) int); ok {
+		r1 = rf(ctx, filter, page, pageSize)
 	} else {
 		r1 = ret.Get(1).(int)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(2).(func(context.Context, *model.TigerFilter, int, int) error); ok {
+		r2 = rf(ctx, filter, page, pageSize)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -87,6 +143,209 @@ func (_m *TigerRepository) FindByID(ctx context.Context, id uint) (*entities.Tig
 	return r0, r1
 }
 
+// FindByIDs provides a mock function with given fields: ctx, ids
+func (_m *TigerRepository) FindByIDs(ctx context.Context, ids []uint) ([]entities.Tiger, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 []entities.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) ([]entities.Tiger, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) []entities.Tiger); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindChildren provides a mock function with given fields: ctx, parentIDs
+func (_m *TigerRepository) FindChildren(ctx context.Context, parentIDs []uint) ([]entities.Tiger, error) {
+	ret := _m.Called(ctx, parentIDs)
+
+	var r0 []entities.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) ([]entities.Tiger, error)); ok {
+		return rf(ctx, parentIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint) []entities.Tiger); ok {
+		r0 = rf(ctx, parentIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint) error); ok {
+		r1 = rf(ctx, parentIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindNames provides a mock function with given fields: ctx
+func (_m *TigerRepository) FindNames(ctx context.Context) ([]entities.TigerName, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []entities.TigerName
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.TigerName, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.TigerName); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.TigerName)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindNear provides a mock function with given fields: ctx, latitude, longitude, radiusKm, page, pageSize
+func (_m *TigerRepository) FindNear(ctx context.Context, latitude float64, longitude float64, radiusKm float64, page int, pageSize int) ([]entities.NearbyTiger, int, error) {
+	ret := _m.Called(ctx, latitude, longitude, radiusKm, page, pageSize)
+
+	var r0 []entities.NearbyTiger
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, float64, float64, float64, int, int) ([]entities.NearbyTiger, int, error)); ok {
+		return rf(ctx, latitude, longitude, radiusKm, page, pageSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, float64, float64, float64, int, int) []entities.NearbyTiger); ok {
+		r0 = rf(ctx, latitude, longitude, radiusKm, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.NearbyTiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, float64, float64, float64, int, int) int); ok {
+		r1 = rf(ctx, latitude, longitude, radiusKm, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, float64, float64, float64, int, int) error); ok {
+		r2 = rf(ctx, latitude, longitude, radiusKm, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindStatusHistory provides a mock function with given fields: ctx, tigerID
+func (_m *TigerRepository) FindStatusHistory(ctx context.Context, tigerID uint) ([]entities.TigerStatusChange, error) {
+	ret := _m.Called(ctx, tigerID)
+
+	var r0 []entities.TigerStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]entities.TigerStatusChange, error)); ok {
+		return rf(ctx, tigerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []entities.TigerStatusChange); ok {
+		r0 = rf(ctx, tigerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.TigerStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, tigerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Merge provides a mock function with given fields: ctx, sourceID, targetID, userID
+func (_m *TigerRepository) Merge(ctx context.Context, sourceID uint, targetID uint, userID uint) error {
+	ret := _m.Called(ctx, sourceID, targetID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, uint) error); ok {
+		r0 = rf(ctx, sourceID, targetID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshLastSeen provides a mock function with given fields: ctx, tiger
+func (_m *TigerRepository) RefreshLastSeen(ctx context.Context, tiger *entities.Tiger) error {
+	ret := _m.Called(ctx, tiger)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Tiger) error); ok {
+		r0 = rf(ctx, tiger)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveID provides a mock function with given fields: ctx, id
+func (_m *TigerRepository) ResolveID(ctx context.Context, id uint) (uint, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (uint, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) uint); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *TigerRepository) Restore(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, tiger, id
 func (_m *TigerRepository) Update(ctx context.Context, tiger *entities.Tiger, id uint) error {
 	ret := _m.Called(ctx, tiger, id)
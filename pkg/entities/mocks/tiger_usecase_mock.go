@@ -5,9 +5,13 @@ package mocks
 import (
 	context "context"
 
+	graphql "github.com/99designs/gqlgen/graphql"
+
 	mock "github.com/stretchr/testify/mock"
 
 	model "github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+
+	time "time"
 )
 
 // TigerUsecase is an autogenerated mock type for the TigerUsecase type
@@ -41,6 +45,150 @@ func (_m *TigerUsecase) CreateTiger(ctx context.Context, tiger *model.NewTiger,
 	return r0, r1
 }
 
+// DeleteTiger provides a mock function with given fields: ctx, id
+func (_m *TigerUsecase) DeleteTiger(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAliases provides a mock function with given fields: ctx, id
+func (_m *TigerUsecase) GetAliases(ctx context.Context, id uint) ([]*model.TigerAlias, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*model.TigerAlias
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*model.TigerAlias, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*model.TigerAlias); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TigerAlias)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFamily provides a mock function with given fields: ctx, id, depth
+func (_m *TigerUsecase) GetFamily(ctx context.Context, id uint, depth int) (*model.Family, error) {
+	ret := _m.Called(ctx, id, depth)
+
+	var r0 *model.Family
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) (*model.Family, error)); ok {
+		return rf(ctx, id, depth)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) *model.Family); ok {
+		r0 = rf(ctx, id, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Family)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int) error); ok {
+		r1 = rf(ctx, id, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHomeRange provides a mock function with given fields: ctx, id, from, to, withKDE
+func (_m *TigerUsecase) GetHomeRange(ctx context.Context, id uint, from *time.Time, to *time.Time, withKDE bool) (*model.HomeRange, error) {
+	ret := _m.Called(ctx, id, from, to, withKDE)
+
+	var r0 *model.HomeRange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *time.Time, *time.Time, bool) (*model.HomeRange, error)); ok {
+		return rf(ctx, id, from, to, withKDE)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *time.Time, *time.Time, bool) *model.HomeRange); ok {
+		r0 = rf(ctx, id, from, to, withKDE)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.HomeRange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *time.Time, *time.Time, bool) error); ok {
+		r1 = rf(ctx, id, from, to, withKDE)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMovementStats provides a mock function with given fields: ctx, tiger
+func (_m *TigerUsecase) GetMovementStats(ctx context.Context, tiger *model.Tiger) (*model.MovementStats, error) {
+	ret := _m.Called(ctx, tiger)
+
+	var r0 *model.MovementStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Tiger) (*model.MovementStats, error)); ok {
+		return rf(ctx, tiger)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Tiger) *model.MovementStats); ok {
+		r0 = rf(ctx, tiger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MovementStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Tiger) error); ok {
+		r1 = rf(ctx, tiger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatusHistory provides a mock function with given fields: ctx, id
+func (_m *TigerUsecase) GetStatusHistory(ctx context.Context, id uint) ([]*model.TigerStatusChange, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*model.TigerStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*model.TigerStatusChange, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*model.TigerStatusChange); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TigerStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTigerByID provides a mock function with given fields: ctx, id
 func (_m *TigerUsecase) GetTigerByID(ctx context.Context, id uint) (*model.Tiger, error) {
 	ret := _m.Called(ctx, id)
@@ -67,32 +215,65 @@ func (_m *TigerUsecase) GetTigerByID(ctx context.Context, id uint) (*model.Tiger
 	return r0, r1
 }
 
-// GetTigers provides a mock function with given fields: ctx, page, pageSize
-func (_m *TigerUsecase) GetTigers(ctx context.Context, page int, pageSize int) ([]*model.Tiger, int, error) {
-	ret := _m.Called(ctx, page, pageSize)
+// GetTigers provides a mock function with given fields: ctx, filter, page, pageSize
+func (_m *TigerUsecase) GetTigers(ctx context.Context, filter *model.TigerFilter, page int, pageSize int) ([]*model.Tiger, int, error) {
+	ret := _m.Called(ctx, filter, page, pageSize)
 
 	var r0 []*model.Tiger
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*model.Tiger, int, error)); ok {
-		return rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TigerFilter, int, int) ([]*model.Tiger, int, error)); ok {
+		return rf(ctx, filter, page, pageSize)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*model.Tiger); ok {
-		r0 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TigerFilter, int, int) []*model.Tiger); ok {
+		r0 = rf(ctx, filter, page, pageSize)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.Tiger)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, int) int); ok {
-		r1 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.TigerFilter, int, int) int); ok {
+		r1 = rf(ctx, filter, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *model.TigerFilter, int, int) error); ok {
+		r2 = rf(ctx, filter, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTigersNear provides a mock function with given fields: ctx, latitude, longitude, radiusKm, page, pageSize
+func (_m *TigerUsecase) GetTigersNear(ctx context.Context, latitude float64, longitude float64, radiusKm float64, page int, pageSize int) ([]*model.NearbyTiger, int, error) {
+	ret := _m.Called(ctx, latitude, longitude, radiusKm, page, pageSize)
+
+	var r0 []*model.NearbyTiger
+	var r1 int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, float64, float64, float64, int, int) ([]*model.NearbyTiger, int, error)); ok {
+		return rf(ctx, latitude, longitude, radiusKm, page, pageSize)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, float64, float64, float64, int, int) []*model.NearbyTiger); ok {
+		r0 = rf(ctx, latitude, longitude, radiusKm, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.NearbyTiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, float64, float64, float64, int, int) int); ok {
+		r1 = rf(ctx, latitude, longitude, radiusKm, page, pageSize)
 	} else {
 		r1 = ret.Get(1).(int)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
-		r2 = rf(ctx, page, pageSize)
+	if rf, ok := ret.Get(2).(func(context.Context, float64, float64, float64, int, int) error); ok {
+		r2 = rf(ctx, latitude, longitude, radiusKm, page, pageSize)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -100,6 +281,162 @@ func (_m *TigerUsecase) GetTigers(ctx context.Context, page int, pageSize int) (
 	return r0, r1, r2
 }
 
+// MergeTigers provides a mock function with given fields: ctx, sourceID, targetID, userID
+func (_m *TigerUsecase) MergeTigers(ctx context.Context, sourceID uint, targetID uint, userID uint) (*model.Tiger, error) {
+	ret := _m.Called(ctx, sourceID, targetID, userID)
+
+	var r0 *model.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, uint) (*model.Tiger, error)); ok {
+		return rf(ctx, sourceID, targetID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, uint) *model.Tiger); ok {
+		r0 = rf(ctx, sourceID, targetID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, uint) error); ok {
+		r1 = rf(ctx, sourceID, targetID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreTiger provides a mock function with given fields: ctx, id
+func (_m *TigerUsecase) RestoreTiger(ctx context.Context, id uint) (*model.Tiger, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *model.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.Tiger, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.Tiger); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTigers provides a mock function with given fields: ctx, query, limit
+func (_m *TigerUsecase) SearchTigers(ctx context.Context, query string, limit int) ([]*model.TigerSearchResult, error) {
+	ret := _m.Called(ctx, query, limit)
+
+	var r0 []*model.TigerSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*model.TigerSearchResult, error)); ok {
+		return rf(ctx, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*model.TigerSearchResult); ok {
+		r0 = rf(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TigerSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestTigers provides a mock function with given fields: ctx, img, limit
+func (_m *TigerUsecase) SuggestTigers(ctx context.Context, img *graphql.Upload, limit int) ([]*model.TigerSuggestion, error) {
+	ret := _m.Called(ctx, img, limit)
+
+	var r0 []*model.TigerSuggestion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *graphql.Upload, int) ([]*model.TigerSuggestion, error)); ok {
+		return rf(ctx, img, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *graphql.Upload, int) []*model.TigerSuggestion); ok {
+		r0 = rf(ctx, img, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TigerSuggestion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *graphql.Upload, int) error); ok {
+		r1 = rf(ctx, img, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTiger provides a mock function with given fields: ctx, id, tiger
+func (_m *TigerUsecase) UpdateTiger(ctx context.Context, id uint, tiger *model.UpdateTiger) (*model.Tiger, error) {
+	ret := _m.Called(ctx, id, tiger)
+
+	var r0 *model.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateTiger) (*model.Tiger, error)); ok {
+		return rf(ctx, id, tiger)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateTiger) *model.Tiger); ok {
+		r0 = rf(ctx, id, tiger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.UpdateTiger) error); ok {
+		r1 = rf(ctx, id, tiger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTigerStatus provides a mock function with given fields: ctx, id, input, userID
+func (_m *TigerUsecase) UpdateTigerStatus(ctx context.Context, id uint, input *model.TigerStatusInput, userID uint) (*model.Tiger, error) {
+	ret := _m.Called(ctx, id, input, userID)
+
+	var r0 *model.Tiger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.TigerStatusInput, uint) (*model.Tiger, error)); ok {
+		return rf(ctx, id, input, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.TigerStatusInput, uint) *model.Tiger); ok {
+		r0 = rf(ctx, id, input, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Tiger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.TigerStatusInput, uint) error); ok {
+		r1 = rf(ctx, id, input, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTigerUsecase creates a new instance of TigerUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTigerUsecase(t interface {
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// NewInlineUnitOfWork creates a mocked UnitOfWork that runs the given function straight
+// away, without a transaction.
+func NewInlineUnitOfWork(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UnitOfWork {
+	uow := NewUnitOfWork(t)
+	uow.
+		On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Maybe()
+
+	return uow
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"context"
+	"errors"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/errs"
+	"gorm.io/gorm"
+)
+
+// Reserve is a protected area that tigers can be assigned to. It holds the sighting
+// rule for the tigers living in it.
+type Reserve struct {
+	gorm.Model
+	Name             string  `json:"name"`
+	MinDistanceKm    float64 `json:"min_distance_km"`
+	NearbyAfterHours float64 `json:"nearby_after_hours"`
+}
+
+var (
+	ErrReserveNotFound = errs.ServiceError{
+		ErrorCode: "ErrReserveNotFound",
+		Err:       errors.New("ErrReserveNotFound: reserve not found"),
+	}
+	ErrInvalidSightingRule = errs.ServiceError{
+		ErrorCode: "ErrInvalidSightingRule",
+		Err:       errors.New("ErrInvalidSightingRule: minimum distance and nearby window should not be negative"),
+	}
+)
+
+type ReserveUsecase interface {
+	CreateReserve(ctx context.Context, reserve *model.NewReserve) (*model.Reserve, error)
+	UpdateReserve(ctx context.Context, id uint, reserve *model.UpdateReserve) (*model.Reserve, error)
+	GetReserves(ctx context.Context) ([]*model.Reserve, error)
+}
+
+type ReserveRepository interface {
+	Create(ctx context.Context, reserve *Reserve) error
+	FindAll(ctx context.Context) ([]Reserve, error)
+	FindByID(ctx context.Context, id uint) (*Reserve, error)
+	Update(ctx context.Context, reserve *Reserve, id uint) error
+}
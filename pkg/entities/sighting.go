@@ -3,42 +3,351 @@ package entities
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	geo "github.com/kellydunn/golang-geo"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/errs"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/scopes"
 	"gorm.io/gorm"
 )
 
 type Sighting struct {
 	gorm.Model
-	Date      time.Time `json:"date"`
-	Latitude  float64   `json:"latitude"`
-	Longitude float64   `json:"longitude"`
+	Date      time.Time `json:"date" gorm:"index:idx_sightings_date"`
+	Latitude  float64   `json:"latitude" gorm:"index:idx_sightings_position"`
+	Longitude float64   `json:"longitude" gorm:"index:idx_sightings_position"`
 	TigerID   uint      `json:"tiger_id"`
 	UserID    uint      `json:"user_id"`
 	User      *User     `gorm:"foreignKey:UserID"`
-	ImageURL  string    `json:"image_url"`
+	// ImageURL is the URL of the thumbnail of the image.
+	ImageURL       string `json:"image_url"`
+	MediumImageURL string `json:"medium_image_url"`
+	// OriginalImageKey is the key of the private full resolution image, which is only
+	// handed out through presigned URLs.
+	OriginalImageKey string `json:"original_image_key"`
+	// ImageSignature is the encoded imageproc.Signature of the image, used to suggest
+	// which tiger a photo shows.
+	ImageSignature string `json:"image_signature"`
+	// ImageHash is the encoded imageproc.Hash of the image, used to detect photos that
+	// were uploaded before.
+	ImageHash string `json:"image_hash"`
+	// ImageHashBand0 to ImageHashBand3 are the bands of ImageHash, indexed to look up
+	// similar images. They are nil for images hashed before the bands were stored.
+	ImageHashBand0 *int `json:"image_hash_band0" gorm:"index"`
+	ImageHashBand1 *int `json:"image_hash_band1" gorm:"index"`
+	ImageHashBand2 *int `json:"image_hash_band2" gorm:"index"`
+	ImageHashBand3 *int `json:"image_hash_band3" gorm:"index"`
+	// ExifLatitude, ExifLongitude and ExifDate are where and when the image was taken
+	// according to its EXIF data, nil when it does not tell.
+	ExifLatitude  *float64   `json:"exif_latitude"`
+	ExifLongitude *float64   `json:"exif_longitude"`
+	ExifDate      *time.Time `json:"exif_date"`
+	// NeedsReview marks a sighting that was accepted although it implies an implausible
+	// movement of the tiger, its image duplicates the image of another sighting, or its
+	// position or date disagrees with the EXIF data of its image.
+	NeedsReview bool `json:"needs_review" gorm:"not null;default:false"`
+}
+
+// SetImageHash sets ImageHash and its bands to h.
+func (s *Sighting) SetImageHash(h imageproc.Hash) {
+	bands := h.Bands()
+	values := make([]int, len(bands))
+	for i, b := range bands {
+		values[i] = int(b)
+	}
+
+	s.ImageHash = h.String()
+	s.ImageHashBand0 = &values[0]
+	s.ImageHashBand1 = &values[1]
+	s.ImageHashBand2 = &values[2]
+	s.ImageHashBand3 = &values[3]
+}
+
+// SetExif keeps where and when the image of s was taken according to e.
+func (s *Sighting) SetExif(e *imageproc.Exif) {
+	if e.HasPosition() {
+		s.ExifLatitude = e.Latitude
+		s.ExifLongitude = e.Longitude
+	}
+	s.ExifDate = e.Date
 }
 
 var (
 	ErrTigerTooClose = errs.ServiceError{
 		ErrorCode: "ErrTigerTooClose",
-		Err:       errors.New("ErrTigerTooClose: tiger is too close, new sightings should be at least more than 5km away from the last sighting"),
+		Err:       errors.New("ErrTigerTooClose: tiger is too close to the adjacent sightings"),
+	}
+	ErrImplausibleMovement = errs.ServiceError{
+		ErrorCode: "ErrImplausibleMovement",
+		Err:       errors.New("ErrImplausibleMovement: tiger could not have moved this fast between sightings"),
+	}
+	ErrDuplicateImage = errs.ServiceError{
+		ErrorCode: "ErrDuplicateImage",
+		Err:       errors.New("ErrDuplicateImage: the same image was already submitted with another sighting"),
+	}
+	ErrIncompleteSighting = errs.ServiceError{
+		ErrorCode: "ErrIncompleteSighting",
+		Err:       errors.New("ErrIncompleteSighting: date, latitude and longitude are required unless the image has them in its EXIF data, and latitude and longitude should be given together"),
 	}
 	ErrInvalidImageType = errs.ServiceError{
 		ErrorCode: "ErrInvalidImageType",
 		Err:       errors.New("ErrInvalidImageType: invalid image type, only jpeg, jpg, and png are allowed"),
 	}
+	ErrSightingNotFound = errs.ServiceError{
+		ErrorCode: "ErrSightingNotFound",
+		Err:       errors.New("ErrSightingNotFound: sighting not found"),
+	}
+	ErrSightingNotOwned = errs.ServiceError{
+		ErrorCode: "ErrSightingNotOwned",
+		Err:       errors.New("ErrSightingNotOwned: only the user who reported the sighting can modify it"),
+	}
+	ErrInvalidBounds = errs.ServiceError{
+		ErrorCode: "ErrInvalidBounds",
+		Err:       errors.New("ErrInvalidBounds: bounds should be valid coordinates and minLat should not be greater than maxLat"),
+	}
+	ErrInvalidDateRange = errs.ServiceError{
+		ErrorCode: "ErrInvalidDateRange",
+		Err:       errors.New("ErrInvalidDateRange: from should not be after to"),
+	}
+	ErrInvalidZoom = errs.ServiceError{
+		ErrorCode: "ErrInvalidZoom",
+		Err:       errors.New("ErrInvalidZoom: zoom should be between 0 and 22"),
+	}
+	ErrLastSighting = errs.ServiceError{
+		ErrorCode: "ErrLastSighting",
+		Err:       errors.New("ErrLastSighting: the last remaining sighting of a tiger cannot be deleted"),
+	}
 )
 
+// NewErrTigerTooClose returns ErrTigerTooClose with the actual and required distance.
+func NewErrTigerTooClose(distanceKm, minDistanceKm float64) error {
+	return ErrTigerTooClose.WithDetails(
+		fmt.Errorf(
+			"ErrTigerTooClose: tiger is too close (%.2f km), new sightings should be more than %.2f km away from the adjacent sightings",
+			distanceKm,
+			minDistanceKm,
+		),
+		map[string]interface{}{
+			"distanceKm":    distanceKm,
+			"minDistanceKm": minDistanceKm,
+		},
+	)
+}
+
+// NewErrImplausibleMovement returns ErrImplausibleMovement with the movement implied by the sighting.
+func NewErrImplausibleMovement(distanceKm float64, gap time.Duration, maxSpeedKmh float64) error {
+	return ErrImplausibleMovement.WithDetails(
+		fmt.Errorf(
+			"ErrImplausibleMovement: tiger would have moved %.2f km in %.2f hours, faster than %.2f km/h",
+			distanceKm,
+			gap.Hours(),
+			maxSpeedKmh,
+		),
+		map[string]interface{}{
+			"distanceKm":  distanceKm,
+			"hours":       gap.Hours(),
+			"maxSpeedKmh": maxSpeedKmh,
+		},
+	)
+}
+
+// NewErrDuplicateImage returns ErrDuplicateImage with the sighting the image was
+// first submitted with.
+func NewErrDuplicateImage(sightingID uint) error {
+	return ErrDuplicateImage.WithDetails(
+		fmt.Errorf(
+			"ErrDuplicateImage: the same image was already submitted with sighting %d",
+			sightingID,
+		),
+		map[string]interface{}{
+			"sightingID": sightingID,
+		},
+	)
+}
+
+// Bounds is a rectangular area on the map. MinLng greater than MaxLng means the area
+// crosses the antimeridian.
+type Bounds struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// Validate checks the bounds are valid coordinates with MinLat not above MaxLat.
+func (b Bounds) Validate() error {
+	for _, lat := range []float64{b.MinLat, b.MaxLat} {
+		if lat < -90 || lat > 90 {
+			return ErrInvalidBounds
+		}
+	}
+
+	for _, lng := range []float64{b.MinLng, b.MaxLng} {
+		if lng < -180 || lng > 180 {
+			return ErrInvalidBounds
+		}
+	}
+
+	if b.MinLat > b.MaxLat {
+		return ErrInvalidBounds
+	}
+
+	return nil
+}
+
+// CrossesAntimeridian reports whether the bounds wrap around longitude 180.
+func (b Bounds) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// SightingCluster is a group of sightings that fall into the same cell of a grid.
+type SightingCluster struct {
+	Count int
+	// Latitude and Longitude are the centroid of the sightings in the cell.
+	Latitude  float64
+	Longitude float64
+	// Latest is the most recent sighting in the cell.
+	Latest Sighting
+}
+
+// SightingRule describes how close a sighting may be to its adjacent sightings.
+type SightingRule struct {
+	// MinDistanceKm is the distance a sighting should exceed from its adjacent sightings.
+	MinDistanceKm float64
+	// NearbyAfter allows sightings closer than MinDistanceKm when they are at least this
+	// far apart in time. Zero disables the exception.
+	NearbyAfter time.Duration
+	// MaxSpeedKmh is the fastest a tiger is expected to travel between two sightings.
+	// Zero disables the check.
+	MaxSpeedKmh float64
+	// FlagImplausible accepts sightings faster than MaxSpeedKmh flagged for review
+	// instead of rejecting them.
+	FlagImplausible bool
+	// FlagDuplicateImage accepts sightings whose image was already submitted flagged
+	// for review instead of rejecting them.
+	FlagDuplicateImage bool
+	// ExifMaxDistanceKm is how far the position of a sighting may be from the position
+	// in the EXIF data of its image before the sighting is flagged for review. Zero
+	// disables the check.
+	ExifMaxDistanceKm float64
+	// ExifMaxTimeGap is how far the date of a sighting may be from the date in the EXIF
+	// data of its image before the sighting is flagged for review. Zero disables the
+	// check.
+	ExifMaxTimeGap time.Duration
+}
+
+// Check validates a sighting against an adjacent one, given the distance and the
+// time between them.
+func (r SightingRule) Check(distanceKm float64, gap time.Duration) error {
+	if distanceKm > r.MinDistanceKm {
+		return nil
+	}
+
+	if gap < 0 {
+		gap = -gap
+	}
+
+	if r.NearbyAfter > 0 && gap >= r.NearbyAfter {
+		return nil
+	}
+
+	return NewErrTigerTooClose(distanceKm, r.MinDistanceKm)
+}
+
+// CheckSpeed validates the speed a tiger would need to travel distanceKm in the time
+// between two sightings.
+func (r SightingRule) CheckSpeed(distanceKm float64, gap time.Duration) error {
+	if r.MaxSpeedKmh <= 0 || distanceKm == 0 {
+		return nil
+	}
+
+	if gap < 0 {
+		gap = -gap
+	}
+
+	if gap > 0 && distanceKm/gap.Hours() <= r.MaxSpeedKmh {
+		return nil
+	}
+
+	return NewErrImplausibleMovement(distanceKm, gap, r.MaxSpeedKmh)
+}
+
+// MatchesExif reports whether the position and date of s are within the rule's limits
+// of the EXIF data of its image. Sightings without EXIF data always match.
+func (r SightingRule) MatchesExif(s *Sighting) bool {
+	if r.ExifMaxDistanceKm > 0 && s.ExifLatitude != nil && s.ExifLongitude != nil {
+		p := geo.NewPoint(s.Latitude, s.Longitude)
+		if p.GreatCircleDistance(geo.NewPoint(*s.ExifLatitude, *s.ExifLongitude)) > r.ExifMaxDistanceKm {
+			return false
+		}
+	}
+
+	if r.ExifMaxTimeGap > 0 && s.ExifDate != nil {
+		gap := s.Date.Sub(*s.ExifDate)
+		if gap < 0 {
+			gap = -gap
+		}
+
+		if gap > r.ExifMaxTimeGap {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SightingSignature is the image signature of a sighting together with its tiger.
+type SightingSignature struct {
+	SightingID uint
+	TigerID    uint
+	ImageURL   string
+	Signature  string
+}
+
+// SightingPolicy decides which SightingRule applies to the sightings of a tiger.
+type SightingPolicy interface {
+	RuleFor(ctx context.Context, tiger *Tiger) (SightingRule, error)
+}
+
 type SightingUsecase interface {
 	CreateSighting(ctx context.Context, sighting *model.NewSighting, userID uint) (*model.Sighting, error)
+	// ImportSighting validates and stores a sighting like CreateSighting, without
+	// notifying anyone. It is used to import historical sightings.
+	ImportSighting(ctx context.Context, sighting *model.NewSighting, userID uint) (*model.Sighting, error)
 	GetSightingsByTigerID(ctx context.Context, tigerID uint, page, pageSize int) ([]*model.Sighting, int, error)
+	UpdateSighting(ctx context.Context, id uint, sighting *model.UpdateSighting, userID uint) (*model.Sighting, error)
+	DeleteSighting(ctx context.Context, id uint, userID uint) error
+	GetSightingsInBounds(ctx context.Context, bounds Bounds, from, to *time.Time) ([]*model.Sighting, error)
+	GetSightingClusters(ctx context.Context, bounds Bounds, zoom int) ([]*model.SightingCluster, error)
+	// GetSightingExif returns the position and date read from the EXIF data of the image
+	// of a sighting, or nil when it had none. It is only meant for admins.
+	GetSightingExif(ctx context.Context, id uint) (*model.SightingExif, error)
+	// GetOriginalImageURL returns a short-lived URL of the original image of a sighting,
+	// or nil when it has none or u is neither an admin nor the user who reported it.
+	GetOriginalImageURL(ctx context.Context, id uint, u *User) (*string, error)
 }
 
 type SightingRepository interface {
 	Create(ctx context.Context, sighting *Sighting) error
+	FindByID(ctx context.Context, id uint) (*Sighting, error)
 	FindByTigerID(ctx context.Context, tigerID uint, preloads []scopes.Preload, page, pageSize int) ([]Sighting, int, error)
+	FindAdjacent(ctx context.Context, tigerID uint, date time.Time, excludeID uint) (prev *Sighting, next *Sighting, err error)
+	FindInBounds(ctx context.Context, bounds Bounds, from, to *time.Time) ([]Sighting, error)
+	FindTrack(ctx context.Context, tigerID uint, from, to *time.Time) ([]Sighting, error)
+	FindEach(ctx context.Context, filter SightingFilter, fn func(*SightingRecord) error) error
+	ClusterInBounds(ctx context.Context, bounds Bounds, cellSize float64) ([]SightingCluster, error)
+	Update(ctx context.Context, sighting *Sighting, id uint) error
+	Delete(ctx context.Context, id uint) error
+	// FindSignatures returns the image signatures of all sightings of tigers that are
+	// not deleted.
+	FindSignatures(ctx context.Context) ([]SightingSignature, error)
+	// FindDuplicateImage returns the ID of the earliest sighting whose image hash is
+	// close enough to hash for the images to be the same photo, or 0 when there is
+	// none. Unless beforeID is 0, only sightings stored before it are considered.
+	FindDuplicateImage(ctx context.Context, hash imageproc.Hash, beforeID uint) (uint, error)
+	// ReassignTiger moves all sightings of a tiger, deleted ones included, to another tiger.
+	ReassignTiger(ctx context.Context, fromID, toID uint) error
 }
@@ -2,31 +2,187 @@ package entities
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/errs"
 	"gorm.io/gorm"
 )
 
 type Tiger struct {
 	gorm.Model
-	Name          string      `json:"name"`
-	DateOfBirth   time.Time   `json:"date_of_birth"`
-	LastSeen      time.Time   `json:"last_seen"`
-	LastLatitude  float64     `json:"last_latitude"`
-	LastLongitude float64     `json:"last_longitude"`
-	Sightings     []*Sighting `json:"sightings"`
+	Name          string            `json:"name"`
+	DateOfBirth   time.Time         `json:"date_of_birth"`
+	Sex           model.Sex         `json:"sex" gorm:"not null;default:UNKNOWN"`
+	Subspecies    string            `json:"subspecies"`
+	Marks         string            `json:"marks"`
+	Status        model.TigerStatus `json:"status" gorm:"not null;default:ACTIVE;index"`
+	LastSeen      time.Time         `json:"last_seen"`
+	LastLatitude  float64           `json:"last_latitude" gorm:"index:idx_tigers_last_position"`
+	LastLongitude float64           `json:"last_longitude" gorm:"index:idx_tigers_last_position"`
+	Sightings     []*Sighting       `json:"sightings"`
+	// Version is bumped on every update and used for optimistic locking.
+	Version uint `json:"version" gorm:"not null;default:0"`
+	// ReserveID is the reserve the tiger lives in, its sighting rule applies to the tiger.
+	ReserveID *uint `json:"reserve_id"`
+	// MinDistanceKm and NearbyAfterHours override the sighting rule of the reserve.
+	MinDistanceKm    *float64 `json:"min_distance_km"`
+	NearbyAfterHours *float64 `json:"nearby_after_hours"`
+	// MotherID and FatherID are the parents of the tiger, both born before it.
+	MotherID *uint `json:"mother_id" gorm:"index"`
+	FatherID *uint `json:"father_id" gorm:"index"`
+	// MergedIntoID is set once the tiger was merged into another one by MergedByID.
+	// The tiger is then an alias, looking it up returns the tiger it was merged into.
+	MergedIntoID *uint      `json:"merged_into_id" gorm:"index"`
+	MergedAt     *time.Time `json:"merged_at"`
+	MergedByID   *uint      `json:"merged_by_id"`
 }
 
+// TigerStatusChange records a change of the status of a tiger, Date being when
+// the change took effect rather than when it was recorded.
+type TigerStatusChange struct {
+	gorm.Model
+	TigerID        uint              `json:"tiger_id" gorm:"index"`
+	PreviousStatus model.TigerStatus `json:"previous_status"`
+	Status         model.TigerStatus `json:"status"`
+	Date           time.Time         `json:"date"`
+	Note           string            `json:"note"`
+	UserID         uint              `json:"user_id"`
+}
+
+// TigerName is a name a tiger can be found by, either its own or the name of a
+// profile that was merged into it.
+type TigerName struct {
+	TigerID uint
+	Name    string
+	Alias   bool
+}
+
+// NearbyTiger is a tiger together with the distance between its last known position
+// and a searched point.
+type NearbyTiger struct {
+	Tiger
+	DistanceKm float64
+}
+
+var (
+	ErrTigerNotFound = errs.ServiceError{
+		ErrorCode: "ErrTigerNotFound",
+		Err:       errors.New("ErrTigerNotFound: tiger not found"),
+	}
+	ErrInvalidCoordinates = errs.ServiceError{
+		ErrorCode: "ErrInvalidCoordinates",
+		Err:       errors.New("ErrInvalidCoordinates: latitude should be between -90 and 90 and longitude between -180 and 180"),
+	}
+	ErrInvalidRadius = errs.ServiceError{
+		ErrorCode: "ErrInvalidRadius",
+		Err:       errors.New("ErrInvalidRadius: radius should be greater than 0"),
+	}
+	ErrTigerNotDeleted = errs.ServiceError{
+		ErrorCode: "ErrTigerNotDeleted",
+		Err:       errors.New("ErrTigerNotDeleted: tiger is not deleted, only deleted tigers can be restored"),
+	}
+	ErrNotEnoughSightings = errs.ServiceError{
+		ErrorCode: "ErrNotEnoughSightings",
+		Err:       errors.New("ErrNotEnoughSightings: at least 3 sightings that are not on a single line are needed to estimate a home range"),
+	}
+	ErrParentNotFound = errs.ServiceError{
+		ErrorCode: "ErrParentNotFound",
+		Err:       errors.New("ErrParentNotFound: mother or father not found"),
+	}
+	ErrInvalidParent = errs.ServiceError{
+		ErrorCode: "ErrInvalidParent",
+		Err:       errors.New("ErrInvalidParent: mother should not be male, father should not be female and a tiger cannot be its own parent"),
+	}
+	ErrParentNotOlder = errs.ServiceError{
+		ErrorCode: "ErrParentNotOlder",
+		Err:       errors.New("ErrParentNotOlder: parents should be born before their cubs"),
+	}
+	ErrInvalidFamilyDepth = errs.ServiceError{
+		ErrorCode: "ErrInvalidFamilyDepth",
+		Err:       errors.New("ErrInvalidFamilyDepth: depth should be between 1 and 5"),
+	}
+	ErrInvalidMerge = errs.ServiceError{
+		ErrorCode: "ErrInvalidMerge",
+		Err:       errors.New("ErrInvalidMerge: a tiger cannot be merged into itself or into one of its aliases"),
+	}
+	ErrInvalidSearchQuery = errs.ServiceError{
+		ErrorCode: "ErrInvalidSearchQuery",
+		Err:       errors.New("ErrInvalidSearchQuery: query should not be empty and limit should be between 1 and 50"),
+	}
+	ErrInvalidSuggestionLimit = errs.ServiceError{
+		ErrorCode: "ErrInvalidSuggestionLimit",
+		Err:       errors.New("ErrInvalidSuggestionLimit: limit should be between 1 and 20"),
+	}
+	ErrInvalidStatusTransition = errs.ServiceError{
+		ErrorCode: "ErrInvalidStatusTransition",
+		Err:       errors.New("ErrInvalidStatusTransition: tiger already has this status or is deceased"),
+	}
+	ErrTigerDeceased = errs.ServiceError{
+		ErrorCode: "ErrTigerDeceased",
+		Err:       errors.New("ErrTigerDeceased: sightings of a deceased tiger are not accepted"),
+	}
+	ErrConcurrentModification = errs.ServiceError{
+		ErrorCode: "ErrConcurrentModification",
+		Err:       errors.New("ErrConcurrentModification: tiger was modified by another request, please retry"),
+	}
+)
+
 type TigerUsecase interface {
 	CreateTiger(ctx context.Context, tiger *model.NewTiger, userID uint) (*model.Tiger, error)
-	GetTigers(ctx context.Context, page, pageSize int) ([]*model.Tiger, int, error)
+	GetTigers(ctx context.Context, filter *model.TigerFilter, page, pageSize int) ([]*model.Tiger, int, error)
+	GetTigersNear(ctx context.Context, latitude, longitude, radiusKm float64, page, pageSize int) ([]*model.NearbyTiger, int, error)
 	GetTigerByID(ctx context.Context, id uint) (*model.Tiger, error)
+	// SearchTigers finds tigers whose name or alias matches query, best match first.
+	SearchTigers(ctx context.Context, query string, limit int) ([]*model.TigerSearchResult, error)
+	// SuggestTigers finds the tigers whose sighting photos look most like img, best
+	// match first.
+	SuggestTigers(ctx context.Context, img *graphql.Upload, limit int) ([]*model.TigerSuggestion, error)
+	GetHomeRange(ctx context.Context, id uint, from, to *time.Time, withKDE bool) (*model.HomeRange, error)
+	// GetMovementStats computes the movement statistics of a tiger from its sightings.
+	// They are cached until the version of the tiger changes.
+	GetMovementStats(ctx context.Context, tiger *model.Tiger) (*model.MovementStats, error)
+	UpdateTiger(ctx context.Context, id uint, tiger *model.UpdateTiger) (*model.Tiger, error)
+	DeleteTiger(ctx context.Context, id uint) error
+	RestoreTiger(ctx context.Context, id uint) (*model.Tiger, error)
+	UpdateTigerStatus(ctx context.Context, id uint, input *model.TigerStatusInput, userID uint) (*model.Tiger, error)
+	GetStatusHistory(ctx context.Context, id uint) ([]*model.TigerStatusChange, error)
+	// GetFamily returns the ancestors and descendants of a tiger up to depth generations
+	// away, together with its siblings.
+	GetFamily(ctx context.Context, id uint, depth int) (*model.Family, error)
+	// MergeTigers moves everything of the source tiger to the target and keeps the
+	// source as an alias of the target.
+	MergeTigers(ctx context.Context, sourceID, targetID, userID uint) (*model.Tiger, error)
+	GetAliases(ctx context.Context, id uint) ([]*model.TigerAlias, error)
 }
 
 type TigerRepository interface {
 	Create(ctx context.Context, tiger *Tiger) error
-	FindAll(ctx context.Context, page, pageSize int) ([]Tiger, int, error)
+	FindAll(ctx context.Context, filter *model.TigerFilter, page, pageSize int) ([]Tiger, int, error)
+	FindNear(ctx context.Context, latitude, longitude, radiusKm float64, page, pageSize int) ([]NearbyTiger, int, error)
+	// FindByID returns the tiger with the id, or the tiger it was merged into.
 	FindByID(ctx context.Context, id uint) (*Tiger, error)
+	// ResolveID returns the id of the tiger an alias id was merged into, or id itself
+	// when it is no alias. Deleted tigers are resolved as well.
+	ResolveID(ctx context.Context, id uint) (uint, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]Tiger, error)
+	// FindChildren returns the tigers whose mother or father is one of parentIDs.
+	FindChildren(ctx context.Context, parentIDs []uint) ([]Tiger, error)
 	Update(ctx context.Context, tiger *Tiger, id uint) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+	// Merge marks the source tiger as merged into the target by userID, and moves
+	// its aliases and cubs to the target.
+	Merge(ctx context.Context, sourceID, targetID, userID uint) error
+	// RefreshLastSeen sets the last seen date and position of the tiger to those of its
+	// newest sighting, updating nothing else. It returns ErrConcurrentModification
+	// when the tiger was updated since it was loaded.
+	RefreshLastSeen(ctx context.Context, tiger *Tiger) error
+	FindAliases(ctx context.Context, tigerID uint) ([]Tiger, error)
+	// FindNames returns the names of all tigers and of their aliases.
+	FindNames(ctx context.Context) ([]TigerName, error)
+	CreateStatusChange(ctx context.Context, change *TigerStatusChange) error
+	FindStatusHistory(ctx context.Context, tigerID uint) ([]TigerStatusChange, error)
 }
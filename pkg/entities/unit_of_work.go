@@ -0,0 +1,10 @@
+package entities
+
+import "context"
+
+// UnitOfWork runs a group of repository calls atomically. Repositories called with
+// the context given to fn will join the same transaction, which is committed when
+// fn returns nil and rolled back otherwise.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -19,8 +19,17 @@ type User struct {
 	Name         string `json:"name"`
 	Email        string `json:"email"`
 	PasswordHash string `json:"password_hash"`
+	Role         Role   `json:"role" gorm:"not null;default:user"`
 }
 
+// Role decides which admin operations a user is allowed to perform.
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
 type UserUsecase interface {
 	CreateUser(ctx context.Context, usr *model.NewUser) (string, error)
 	Login(ctx context.Context, email, password string) (string, error)
@@ -54,6 +63,11 @@ var (
 		ErrorCode: "ErrTokenAlreadyInvalidated",
 		Err:       errors.New("ErrTokenAlreadyInvalidated: token already invalidated"),
 	}
+
+	ErrForbidden = errs.ServiceError{
+		ErrorCode: "ErrForbidden",
+		Err:       errors.New("ErrForbidden: only admins can perform this operation"),
+	}
 )
 
 // Password Hashing Implementation
@@ -75,6 +89,10 @@ func (u *User) ValidatePassword(password string) error {
 	return nil
 }
 
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
 func GetSecretKey() []byte {
 	secretStr := config.Get(config.JWT_SECRET)
 	if secretStr == "" {
@@ -0,0 +1,422 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/geojson"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/gpx"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/kml"
+)
+
+const (
+	geoJSONContentType = "application/geo+json"
+	gpxContentType     = "application/gpx+xml"
+	kmlContentType     = "application/vnd.google-earth.kml+xml"
+	csvContentType     = "text/csv; charset=utf-8"
+)
+
+// csvFlushRows is how many rows are buffered before a CSV export is flushed to the client.
+const csvFlushRows = 500
+
+var csvHeader = []string{
+	"id", "date_utc", "tiger_id", "tiger_name", "user_id", "reporter",
+	"latitude", "longitude", "image_url", "needs_review",
+}
+
+// TigersGeoJSON serves the last known position of every tiger as a GeoJSON
+// FeatureCollection of Points.
+func TigersGeoJSON(u entities.ExportUsecase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		tigers, err := u.GetLastPositions(c.Request().Context())
+		if err != nil {
+			return httpError(err)
+		}
+
+		features := make([]*geojson.Feature, len(tigers))
+		for i := range tigers {
+			t := &tigers[i]
+			features[i] = geojson.NewFeature(geojson.NewPoint(t.LastLatitude, t.LastLongitude), tigerProperties(t))
+		}
+
+		return writeGeoJSON(c, "tigers.geojson", geojson.NewFeatureCollection(features...))
+	}
+}
+
+// TrackGeoJSON serves the sightings of a tiger as a GeoJSON FeatureCollection with a
+// LineString of the track in chronological order, followed by a Point per sighting.
+// The track can be limited with the `from` and `to` query params in RFC3339 format.
+func TrackGeoJSON(u entities.ExportUsecase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		t, sightings, err := track(c, u)
+		if err != nil {
+			return err
+		}
+
+		features := []*geojson.Feature{}
+
+		// a LineString needs at least two positions
+		if len(sightings) > 1 {
+			positions := make([][2]float64, len(sightings))
+			for i, s := range sightings {
+				positions[i] = [2]float64{s.Latitude, s.Longitude}
+			}
+
+			props := tigerProperties(t)
+			props["sightings"] = len(sightings)
+			features = append(features, geojson.NewFeature(geojson.NewLineString(positions), props))
+		}
+
+		for i := range sightings {
+			s := &sightings[i]
+			features = append(features, geojson.NewFeature(geojson.NewPoint(s.Latitude, s.Longitude), sightingProperties(s)))
+		}
+
+		return writeGeoJSON(c, fmt.Sprintf("tiger-%d-track.geojson", t.ID), geojson.NewFeatureCollection(features...))
+	}
+}
+
+// TrackGPX serves the sightings of a tiger as a GPX document for handheld GPS units,
+// with a waypoint per sighting linking to its image and a track in chronological order.
+// The track can be limited with the `from` and `to` query params in RFC3339 format.
+func TrackGPX(u entities.ExportUsecase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		t, sightings, err := track(c, u)
+		if err != nil {
+			return err
+		}
+
+		waypoints := make([]gpx.Waypoint, len(sightings))
+		points := make([]gpx.Waypoint, len(sightings))
+		for i, s := range sightings {
+			points[i] = gpx.NewWaypoint(s.Latitude, s.Longitude, s.Date)
+
+			waypoints[i] = gpx.NewWaypoint(s.Latitude, s.Longitude, s.Date)
+			waypoints[i].Name = sightingName(&s)
+			if s.ImageURL != "" {
+				waypoints[i].Desc = s.ImageURL
+				waypoints[i].Links = []gpx.Link{{Href: s.ImageURL, Text: "Sighting image"}}
+			}
+		}
+
+		trk := gpx.Track{Name: t.Name, Segments: []gpx.Segment{{Points: points}}}
+
+		return writeXML(c, gpxContentType, fmt.Sprintf("tiger-%d-track.gpx", t.ID), gpx.New(t.Name, waypoints, trk))
+	}
+}
+
+// TrackKML serves the sightings of a tiger as a KML document for Google Earth, with
+// a placemark per sighting linking to its image and a LineString of the track.
+// The track can be limited with the `from` and `to` query params in RFC3339 format.
+func TrackKML(u entities.ExportUsecase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		t, sightings, err := track(c, u)
+		if err != nil {
+			return err
+		}
+
+		placemarks := []kml.Placemark{}
+
+		// a LineString needs at least two positions
+		if len(sightings) > 1 {
+			positions := make([][2]float64, len(sightings))
+			for i, s := range sightings {
+				positions[i] = [2]float64{s.Latitude, s.Longitude}
+			}
+
+			placemarks = append(placemarks, kml.Placemark{
+				Name:       t.Name,
+				LineString: kml.NewLineString(positions),
+			})
+		}
+
+		for i := range sightings {
+			s := &sightings[i]
+			placemarks = append(placemarks, kml.Placemark{
+				Name:        sightingName(s),
+				Description: s.ImageURL,
+				TimeStamp:   kml.NewTimeStamp(s.Date),
+				Point:       kml.NewPoint(s.Latitude, s.Longitude),
+			})
+		}
+
+		return writeXML(c, kmlContentType, fmt.Sprintf("tiger-%d-track.kml", t.ID), kml.New(t.Name, placemarks...))
+	}
+}
+
+// SightingsCSV streams the sightings matching the query as a CSV table in chronological
+// order. Sightings can be filtered by `tiger_id`, `user_id`, `from` and `to` in RFC3339
+// format, and a bounding box given by all of `min_lat`, `min_lng`, `max_lat` and `max_lng`.
+// The file starts with a byte order mark and dates are written without time zone in UTC,
+// so it opens correctly in Excel.
+func SightingsCSV(u entities.ExportUsecase) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		filter, err := sightingFilter(c)
+		if err != nil {
+			return err
+		}
+
+		var w *csv.Writer
+		rows := 0
+
+		// the header is only written once the filter is known to be valid, so errors
+		// can still be returned with a proper status code
+		start := func() error {
+			attachment(c, csvContentType, "sightings.csv")
+			w = csv.NewWriter(c.Response())
+
+			if _, err := c.Response().Write([]byte("\ufeff")); err != nil {
+				return err
+			}
+
+			return w.Write(csvHeader)
+		}
+
+		err = u.EachSighting(c.Request().Context(), *filter, func(s *entities.SightingRecord) error {
+			if w == nil {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+
+			err := w.Write([]string{
+				strconv.FormatUint(uint64(s.ID), 10),
+				s.Date.UTC().Format(time.DateTime),
+				strconv.FormatUint(uint64(s.TigerID), 10),
+				csvText(s.TigerName),
+				strconv.FormatUint(uint64(s.UserID), 10),
+				csvText(s.UserName),
+				strconv.FormatFloat(s.Latitude, 'f', -1, 64),
+				strconv.FormatFloat(s.Longitude, 'f', -1, 64),
+				csvText(s.ImageURL),
+				strconv.FormatBool(s.NeedsReview),
+			})
+			if err != nil {
+				return err
+			}
+
+			rows++
+			if rows%csvFlushRows == 0 {
+				w.Flush()
+				c.Response().Flush()
+			}
+
+			return w.Error()
+		})
+		if err != nil {
+			if w == nil {
+				return httpError(err)
+			}
+
+			// the response is already committed, the client gets a truncated file
+			return err
+		}
+
+		if w == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		w.Flush()
+		return w.Error()
+	}
+}
+
+// track reads the tiger ID from the path and the optional date range from the query
+// and returns the matching sightings.
+func track(c echo.Context, u entities.ExportUsecase) (*entities.Tiger, []entities.Sighting, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "invalid tiger id")
+	}
+
+	from, err := queryTime(c, "from")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	to, err := queryTime(c, "to")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, sightings, err := u.GetTrack(c.Request().Context(), uint(id), from, to)
+	if err != nil {
+		return nil, nil, httpError(err)
+	}
+
+	return t, sightings, nil
+}
+
+// sightingFilter reads the filter of a sightings export from the query.
+func sightingFilter(c echo.Context) (*entities.SightingFilter, error) {
+	var filter entities.SightingFilter
+	var err error
+
+	if filter.TigerID, err = queryUint(c, "tiger_id"); err != nil {
+		return nil, err
+	}
+
+	if filter.UserID, err = queryUint(c, "user_id"); err != nil {
+		return nil, err
+	}
+
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		return nil, err
+	}
+
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		return nil, err
+	}
+
+	names := []string{"min_lat", "min_lng", "max_lat", "max_lng"}
+	values := make([]float64, len(names))
+	given := 0
+	for i, name := range names {
+		v := c.QueryParam(name)
+		if v == "" {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s should be a number", name))
+		}
+
+		values[i] = f
+		given++
+	}
+
+	switch given {
+	case 0:
+	case len(names):
+		filter.Bounds = &entities.Bounds{MinLat: values[0], MinLng: values[1], MaxLat: values[2], MaxLng: values[3]}
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "min_lat, min_lng, max_lat and max_lng should be given together")
+	}
+
+	return &filter, nil
+}
+
+func queryUint(c echo.Context, name string) (*uint, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseUint(v, 10, 0)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s should be a positive integer", name))
+	}
+
+	res := uint(id)
+	return &res, nil
+}
+
+func queryTime(c echo.Context, name string) (*time.Time, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s should be in RFC3339 format", name))
+	}
+
+	return &t, nil
+}
+
+func httpError(err error) error {
+	switch {
+	case errors.Is(err, entities.ErrTigerNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, entities.ErrInvalidDateRange), errors.Is(err, entities.ErrInvalidBounds):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return err
+	}
+}
+
+func writeGeoJSON(c echo.Context, filename string, fc *geojson.FeatureCollection) error {
+	attachment(c, geoJSONContentType, filename)
+
+	return json.NewEncoder(c.Response()).Encode(fc)
+}
+
+func writeXML(c echo.Context, contentType, filename string, doc interface{}) error {
+	attachment(c, contentType, filename)
+
+	if _, err := c.Response().Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(c.Response())
+	enc.Indent("", "  ")
+
+	return enc.Encode(doc)
+}
+
+func attachment(c echo.Context, contentType, filename string) {
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Response().WriteHeader(http.StatusOK)
+}
+
+// csvText keeps spreadsheets from evaluating user provided text as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+func sightingName(s *entities.Sighting) string {
+	return fmt.Sprintf("Sighting %d (%s)", s.ID, s.Date.UTC().Format(time.DateTime))
+}
+
+func tigerProperties(t *entities.Tiger) map[string]interface{} {
+	props := map[string]interface{}{
+		"id":             t.ID,
+		"name":           t.Name,
+		"date_of_birth":  t.DateOfBirth,
+		"last_seen":      t.LastSeen,
+		"last_latitude":  t.LastLatitude,
+		"last_longitude": t.LastLongitude,
+	}
+
+	if t.ReserveID != nil {
+		props["reserve_id"] = *t.ReserveID
+	}
+
+	return props
+}
+
+func sightingProperties(s *entities.Sighting) map[string]interface{} {
+	props := map[string]interface{}{
+		"id":           s.ID,
+		"tiger_id":     s.TigerID,
+		"user_id":      s.UserID,
+		"date":         s.Date,
+		"latitude":     s.Latitude,
+		"longitude":    s.Longitude,
+		"needs_review": s.NeedsReview,
+	}
+
+	if s.ImageURL != "" {
+		props["image_url"] = s.ImageURL
+	}
+
+	return props
+}
@@ -0,0 +1,297 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/geojson"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/gpx"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/kml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestHandler_TigersGeoJSON(t *testing.T) {
+	now := time.Now()
+
+	u := mocks.NewExportUsecase(t)
+	u.
+		On("GetLastPositions", mock.Anything).
+		Return([]entities.Tiger{
+			{Model: gorm.Model{ID: 1}, Name: "tiger-1", LastSeen: now, LastLatitude: -7.5, LastLongitude: 110.8},
+			{Model: gorm.Model{ID: 2}, Name: "tiger-2", LastSeen: now, LastLatitude: 0.5, LastLongitude: 179.5},
+		}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/export/tigers.geojson", nil)
+	rec := httptest.NewRecorder()
+
+	err := TigersGeoJSON(u)(e.NewContext(req, rec))
+
+	assert.Nil(t, err)
+	assert.Equal(t, geoJSONContentType, rec.Header().Get(echo.HeaderContentType))
+
+	var fc geojson.FeatureCollection
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+	assert.Equal(t, "FeatureCollection", fc.Type)
+	assert.Len(t, fc.Features, 2)
+	assert.Equal(t, "Point", fc.Features[0].Geometry.Type)
+	assert.Equal(t, "tiger-1", fc.Features[0].Properties["name"])
+}
+
+func TestHandler_TrackGeoJSON(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		id    string
+		query string
+
+		sightings []entities.Sighting
+		err       error
+
+		wantGeometries []string
+		wantCode       int
+	}{
+		{
+			name:  "should return track and points given multiple sightings",
+			id:    "1",
+			query: "?from=" + now.Add(-time.Hour).Format(time.RFC3339),
+			sightings: []entities.Sighting{
+				{Model: gorm.Model{ID: 1}, TigerID: 1, Date: now, Latitude: -7.5, Longitude: 110.8},
+				{Model: gorm.Model{ID: 2}, TigerID: 1, Date: now, Latitude: -7.4, Longitude: 110.7},
+			},
+			wantGeometries: []string{"LineString", "Point", "Point"},
+		},
+		{
+			name: "should return only a point given a single sighting",
+			id:   "1",
+			sightings: []entities.Sighting{
+				{Model: gorm.Model{ID: 1}, TigerID: 1, Date: now, Latitude: -7.5, Longitude: 110.8},
+			},
+			wantGeometries: []string{"Point"},
+		},
+		{
+			name:     "should return 400 given invalid tiger id",
+			id:       "tiger",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "should return 400 given invalid date",
+			id:       "1",
+			query:    "?to=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "should return 404 given tiger does not exist",
+			id:       "1",
+			err:      entities.ErrTigerNotFound,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := mocks.NewExportUsecase(t)
+			u.
+				On("GetTrack", mock.Anything, uint(1), mock.Anything, mock.Anything).
+				Return(&entities.Tiger{Model: gorm.Model{ID: 1}, Name: "tiger-1"}, tc.sightings, tc.err).
+				Maybe()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/export/tigers/"+tc.id+"/track.geojson"+tc.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+
+			err := TrackGeoJSON(u)(c)
+
+			if tc.wantCode != 0 {
+				var he *echo.HTTPError
+				assert.ErrorAs(t, err, &he)
+				assert.Equal(t, tc.wantCode, he.Code)
+				return
+			}
+
+			assert.Nil(t, err)
+
+			var fc geojson.FeatureCollection
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &fc))
+
+			geometries := []string{}
+			for _, f := range fc.Features {
+				geometries = append(geometries, f.Geometry.Type)
+			}
+			assert.Equal(t, tc.wantGeometries, geometries)
+		})
+	}
+}
+
+func trackRequest(t *testing.T, h func(entities.ExportUsecase) echo.HandlerFunc, path string) *httptest.ResponseRecorder {
+	now := time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC)
+	from := now.Add(-time.Hour)
+
+	u := mocks.NewExportUsecase(t)
+	u.
+		On("GetTrack", mock.Anything, uint(1), &from, (*time.Time)(nil)).
+		Return(&entities.Tiger{Model: gorm.Model{ID: 1}, Name: "tiger-1"}, []entities.Sighting{
+			{Model: gorm.Model{ID: 1}, TigerID: 1, Date: now, Latitude: -7.5, Longitude: 110.8, ImageURL: "https://cdn.example.com/1.jpg"},
+			{Model: gorm.Model{ID: 2}, TigerID: 1, Date: now.Add(time.Hour), Latitude: -7.4, Longitude: 110.7},
+		}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, path+"?from="+from.Format(time.RFC3339), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := h(u)(c)
+	assert.Nil(t, err)
+
+	return rec
+}
+
+func TestHandler_TrackGPX(t *testing.T) {
+	rec := trackRequest(t, TrackGPX, "/export/tigers/1/track.gpx")
+
+	assert.Equal(t, gpxContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "tiger-1-track.gpx")
+
+	var doc gpx.GPX
+	assert.Nil(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "1.1", doc.Version)
+
+	assert.Len(t, doc.Waypoints, 2)
+	assert.Equal(t, -7.5, doc.Waypoints[0].Lat)
+	assert.Equal(t, 110.8, doc.Waypoints[0].Lon)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", doc.Waypoints[0].Desc)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", doc.Waypoints[0].Links[0].Href)
+	assert.Empty(t, doc.Waypoints[1].Links)
+
+	assert.Len(t, doc.Tracks, 1)
+	assert.Equal(t, "tiger-1", doc.Tracks[0].Name)
+	assert.Len(t, doc.Tracks[0].Segments[0].Points, 2)
+	assert.Equal(t, -7.4, doc.Tracks[0].Segments[0].Points[1].Lat)
+}
+
+func TestHandler_TrackKML(t *testing.T) {
+	rec := trackRequest(t, TrackKML, "/export/tigers/1/track.kml")
+
+	assert.Equal(t, kmlContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "tiger-1-track.kml")
+
+	var doc kml.KML
+	assert.Nil(t, xml.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "tiger-1", doc.Document.Name)
+
+	placemarks := doc.Document.Placemarks
+	assert.Len(t, placemarks, 3)
+	assert.Equal(t, "110.8,-7.5 110.7,-7.4", placemarks[0].LineString.Coordinates)
+	assert.Equal(t, "110.8,-7.5", placemarks[1].Point.Coordinates)
+	assert.Equal(t, "https://cdn.example.com/1.jpg", placemarks[1].Description)
+	assert.Equal(t, "110.7,-7.4", placemarks[2].Point.Coordinates)
+}
+
+func TestHandler_SightingsCSV(t *testing.T) {
+	date := time.Date(2024, 4, 1, 8, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	tigerID := uint(1)
+
+	testCases := []struct {
+		name string
+
+		query   string
+		records []entities.SightingRecord
+		err     error
+
+		wantFilter entities.SightingFilter
+		wantBody   string
+		wantCode   int
+	}{
+		{
+			name:  "should stream sightings as csv",
+			query: "?tiger_id=1&min_lat=-8&min_lng=110&max_lat=-7&max_lng=111",
+			records: []entities.SightingRecord{
+				{ID: 1, Date: date, Latitude: -7.5, Longitude: 110.8, TigerID: 1, TigerName: "tiger-1", UserID: 1, UserName: "=HYPERLINK()", ImageURL: "https://cdn.example.com/1.jpg"},
+				{ID: 2, Date: date.Add(time.Hour), Latitude: -7.4, Longitude: 110.7, TigerID: 1, TigerName: "tiger, the first", UserID: 2, UserName: "user-2", NeedsReview: true},
+			},
+			wantFilter: entities.SightingFilter{
+				TigerID: &tigerID,
+				Bounds:  &entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111},
+			},
+			wantBody: "\ufeffid,date_utc,tiger_id,tiger_name,user_id,reporter,latitude,longitude,image_url,needs_review\n" +
+				"1,2024-04-01 01:00:00,1,tiger-1,1,'=HYPERLINK(),-7.5,110.8,https://cdn.example.com/1.jpg,false\n" +
+				"2,2024-04-01 02:00:00,1,\"tiger, the first\",2,user-2,-7.4,110.7,,true\n",
+		},
+		{
+			name:     "should return only the header given no sightings",
+			wantBody: "\ufeffid,date_utc,tiger_id,tiger_name,user_id,reporter,latitude,longitude,image_url,needs_review\n",
+		},
+		{
+			name:     "should return 400 given partial bounds",
+			query:    "?min_lat=-8",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "should return 400 given invalid user id",
+			query:    "?user_id=-1",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "should return 400 given invalid bounds",
+			query:    "?min_lat=10&min_lng=110&max_lat=-10&max_lng=111",
+			err:      entities.ErrInvalidBounds,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := mocks.NewExportUsecase(t)
+			u.
+				On("EachSighting", mock.Anything, mock.Anything, mock.Anything).
+				Return(func(ctx context.Context, filter entities.SightingFilter, fn func(*entities.SightingRecord) error) error {
+					if tc.err != nil {
+						return tc.err
+					}
+
+					assert.Equal(t, tc.wantFilter, filter)
+					for i := range tc.records {
+						if err := fn(&tc.records[i]); err != nil {
+							return err
+						}
+					}
+					return nil
+				}).
+				Maybe()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/export/sightings.csv"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			err := SightingsCSV(u)(e.NewContext(req, rec))
+
+			if tc.wantCode != 0 {
+				var he *echo.HTTPError
+				assert.ErrorAs(t, err, &he)
+				assert.Equal(t, tc.wantCode, he.Code)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, csvContentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, tc.wantBody, rec.Body.String())
+		})
+	}
+}
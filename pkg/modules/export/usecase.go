@@ -0,0 +1,77 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"gorm.io/gorm"
+)
+
+// pageSize is the largest page the repositories return, exports go through all pages.
+const pageSize = 1000
+
+type usecase struct {
+	tigerRepo    entities.TigerRepository
+	sightingRepo entities.SightingRepository
+}
+
+// GetLastPositions implements entities.ExportUsecase.
+func (u *usecase) GetLastPositions(ctx context.Context) ([]entities.Tiger, error) {
+	var res []entities.Tiger
+	for page := 1; ; page++ {
+		tigers, count, err := u.tigerRepo.FindAll(ctx, nil, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, tigers...)
+		if len(tigers) == 0 || len(res) >= count {
+			return res, nil
+		}
+	}
+}
+
+// GetTrack implements entities.ExportUsecase.
+func (u *usecase) GetTrack(
+	ctx context.Context,
+	tigerID uint,
+	from, to *time.Time,
+) (*entities.Tiger, []entities.Sighting, error) {
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, entities.ErrInvalidDateRange
+	}
+
+	t, err := u.tigerRepo.FindByID(ctx, tigerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, entities.ErrTigerNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sightings, err := u.sightingRepo.FindTrack(ctx, t.ID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, sightings, nil
+}
+
+// EachSighting implements entities.ExportUsecase.
+func (u *usecase) EachSighting(
+	ctx context.Context,
+	filter entities.SightingFilter,
+	fn func(*entities.SightingRecord) error,
+) error {
+	if err := filter.Validate(); err != nil {
+		return err
+	}
+
+	return u.sightingRepo.FindEach(ctx, filter, fn)
+}
+
+func NewExportUsecase(tigerRepo entities.TigerRepository, sightingRepo entities.SightingRepository) entities.ExportUsecase {
+	return &usecase{tigerRepo, sightingRepo}
+}
@@ -0,0 +1,201 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_GetLastPositions(t *testing.T) {
+	tigers := make([]entities.Tiger, pageSize)
+	for i := range tigers {
+		tigers[i] = entities.Tiger{Model: gorm.Model{ID: uint(i + 1)}}
+	}
+
+	testCases := []struct {
+		name string
+
+		pages [][]entities.Tiger
+		count int
+		err   error
+
+		wantLen int
+		wantErr error
+	}{
+		{
+			name:    "should return tigers of a single page",
+			pages:   [][]entities.Tiger{tigers[:2]},
+			count:   2,
+			wantLen: 2,
+		},
+		{
+			name:    "should return tigers of all pages",
+			pages:   [][]entities.Tiger{tigers, tigers[:1]},
+			count:   pageSize + 1,
+			wantLen: pageSize + 1,
+		},
+		{
+			name:    "should return error given repository fails",
+			pages:   [][]entities.Tiger{nil},
+			err:     errors.New("no such table: tigers"),
+			wantErr: errors.New("no such table: tigers"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tigerRepo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+
+			for i, p := range tc.pages {
+				tigerRepo.
+					On("FindAll", mock.Anything, (*model.TigerFilter)(nil), i+1, pageSize).
+					Return(p, tc.count, tc.err).
+					Once()
+			}
+
+			u := NewExportUsecase(tigerRepo, sightingRepo)
+
+			res, err := u.GetLastPositions(context.Background())
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Len(t, res, tc.wantLen)
+		})
+	}
+}
+
+func TestUsecase_GetTrack(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	testCases := []struct {
+		name string
+
+		from        *time.Time
+		to          *time.Time
+		findErr     error
+		sightings   []entities.Sighting
+		sightingErr error
+
+		wantLen int
+		wantErr error
+	}{
+		{
+			name:      "should return tiger and its sightings",
+			sightings: []entities.Sighting{{TigerID: 1}, {TigerID: 1}},
+			wantLen:   2,
+		},
+		{
+			name:      "should return tiger and its sightings in date range",
+			from:      &before,
+			to:        &now,
+			sightings: []entities.Sighting{{TigerID: 1}},
+			wantLen:   1,
+		},
+		{
+			name:    "should return ErrInvalidDateRange given from after to",
+			from:    &now,
+			to:      &before,
+			wantErr: entities.ErrInvalidDateRange,
+		},
+		{
+			name:    "should return ErrTigerNotFound given tiger does not exist",
+			findErr: gorm.ErrRecordNotFound,
+			wantErr: entities.ErrTigerNotFound,
+		},
+		{
+			name:        "should return error given sighting repository fails",
+			sightingErr: errors.New("no such table: sightings"),
+			wantErr:     errors.New("no such table: sightings"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tigerRepo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+
+			tigerRepo.
+				On("FindByID", mock.Anything, uint(1)).
+				Return(&entities.Tiger{Model: gorm.Model{ID: 1}}, tc.findErr).
+				Maybe()
+
+			sightingRepo.
+				On("FindTrack", mock.Anything, uint(1), tc.from, tc.to).
+				Return(tc.sightings, tc.sightingErr).
+				Maybe()
+
+			u := NewExportUsecase(tigerRepo, sightingRepo)
+
+			tiger, res, err := u.GetTrack(context.Background(), 1, tc.from, tc.to)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Len(t, res, tc.wantLen)
+			if tc.wantErr == nil {
+				assert.Equal(t, uint(1), tiger.ID)
+			}
+		})
+	}
+}
+
+func TestUsecase_EachSighting(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	testCases := []struct {
+		name string
+
+		filter  entities.SightingFilter
+		wantErr error
+	}{
+		{
+			name:   "should pass every sighting to fn",
+			filter: entities.SightingFilter{From: &before, To: &now},
+		},
+		{
+			name:    "should return ErrInvalidDateRange given from after to",
+			filter:  entities.SightingFilter{From: &now, To: &before},
+			wantErr: entities.ErrInvalidDateRange,
+		},
+		{
+			name:    "should return ErrInvalidBounds given invalid bounds",
+			filter:  entities.SightingFilter{Bounds: &entities.Bounds{MinLat: 10, MaxLat: -10}},
+			wantErr: entities.ErrInvalidBounds,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tigerRepo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+
+			sightingRepo.
+				On("FindEach", mock.Anything, tc.filter, mock.Anything).
+				Return(func(ctx context.Context, filter entities.SightingFilter, fn func(*entities.SightingRecord) error) error {
+					return fn(&entities.SightingRecord{ID: 1})
+				}).
+				Maybe()
+
+			u := NewExportUsecase(tigerRepo, sightingRepo)
+
+			ids := []uint{}
+			err := u.EachSighting(context.Background(), tc.filter, func(s *entities.SightingRecord) error {
+				ids = append(ids, s.ID)
+				return nil
+			})
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr == nil {
+				assert.Equal(t, []uint{1}, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+)
+
+const (
+	tigersFile    = "tigers"
+	sightingsFile = "sightings"
+)
+
+// tigerColumns and sightingColumns are the columns the CSV files need, alternatives
+// are separated by `|`.
+var (
+	tigerColumns    = []string{"name", "date_of_birth", "last_seen", "last_latitude", "last_longitude"}
+	sightingColumns = []string{"tiger_id|tiger_name", "date", "latitude", "longitude"}
+)
+
+// dateFormats are the date formats accepted in CSV files, spreadsheets tend to export
+// dates without time.
+var dateFormats = []string{time.RFC3339, time.DateTime, time.DateOnly}
+
+// row is a CSV record keyed by its lowercased header.
+type row struct {
+	line   int
+	values map[string]string
+}
+
+// readCSV reads every record of r, failing when the header misses any of the required
+// columns. Records that cannot be parsed are returned as row errors so the rest of
+// the file can still be imported.
+func readCSV(file string, r io.Reader, required []string) ([]row, []*model.ImportRowError, error) {
+	if r == nil {
+		return nil, nil, nil
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		header = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	columns := map[string]int{}
+	for i, h := range header {
+		// spreadsheets exported as UTF-8 often start with a byte order mark
+		h = strings.TrimPrefix(h, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	var missing []string
+	for _, c := range required {
+		if !hasAny(columns, strings.Split(c, "|")) {
+			missing = append(missing, strings.ReplaceAll(c, "|", " or "))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, entities.NewErrInvalidCSVHeader(file, missing)
+	}
+
+	var rows []row
+	var rowErrs []*model.ImportRowError
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rowErrs = append(rowErrs, rowError(file, parseErr.StartLine, entities.NewErrInvalidImportRow(parseErr.Err.Error())))
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		values := map[string]string{}
+		for c, i := range columns {
+			if i < len(record) {
+				values[c] = strings.TrimSpace(record[i])
+			}
+		}
+
+		rows = append(rows, row{line: line, values: values})
+	}
+
+	return rows, rowErrs, nil
+}
+
+func hasAny(columns map[string]int, names []string) bool {
+	for _, n := range names {
+		if _, ok := columns[n]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r row) has(column string) bool {
+	return r.values[column] != ""
+}
+
+func (r row) string(column string) (string, error) {
+	v := r.values[column]
+	if v == "" {
+		return "", entities.NewErrInvalidImportRow(fmt.Sprintf("%s is required", column))
+	}
+
+	return v, nil
+}
+
+func (r row) time(column string) (time.Time, error) {
+	v, err := r.string(column)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, f := range dateFormats {
+		if t, err := time.Parse(f, v); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, entities.NewErrInvalidImportRow(fmt.Sprintf("%s should be in RFC3339 or YYYY-MM-DD format", column))
+}
+
+func (r row) float(column string) (float64, error) {
+	v, err := r.string(column)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, entities.NewErrInvalidImportRow(fmt.Sprintf("%s should be a number", column))
+	}
+
+	return f, nil
+}
+
+func (r row) uint(column string) (uint, error) {
+	v, err := r.string(column)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseUint(v, 10, 0)
+	if err != nil {
+		return 0, entities.NewErrInvalidImportRow(fmt.Sprintf("%s should be a positive integer", column))
+	}
+
+	return uint(id), nil
+}
+
+// coordinates reads a latitude and longitude pair and checks their ranges.
+func (r row) coordinates(latColumn, lngColumn string) (float64, float64, error) {
+	lat, err := r.float(latColumn)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lng, err := r.float(lngColumn)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return 0, 0, entities.ErrInvalidCoordinates
+	}
+
+	return lat, lng, nil
+}
@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/errs"
+	"gorm.io/gorm"
+)
+
+// errDryRun rolls back the import transaction once every row has been validated.
+var errDryRun = errors.New("dry run")
+
+type usecase struct {
+	tigerUsecase    entities.TigerUsecase
+	sightingUsecase entities.SightingUsecase
+	uow             entities.UnitOfWork
+}
+
+// Import implements entities.ImportUsecase.
+//
+// Rows are stored one by one inside a single transaction, so each sighting is
+// validated against the ones imported before it. A dry run goes through exactly the
+// same steps and rolls the transaction back at the end.
+func (u *usecase) Import(
+	ctx context.Context,
+	tigers, sightings io.Reader,
+	userID uint,
+	dryRun bool,
+) (*model.ImportReport, error) {
+	// both files are read upfront so a wrong header fails before anything is stored
+	tigerRows, tigerErrs, err := readCSV(tigersFile, tigers, tigerColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	sightingRows, sightingErrs, err := readCSV(sightingsFile, sightings, sightingColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ImportReport{
+		DryRun: dryRun,
+		Errors: []*model.ImportRowError{},
+	}
+	report.Errors = append(report.Errors, tigerErrs...)
+	report.Errors = append(report.Errors, sightingErrs...)
+
+	err = u.uow.Do(ctx, func(ctx context.Context) error {
+		// IDs of the tigers in this import by name, so sightings can refer to them
+		names := map[string]uint{}
+
+		for _, r := range tigerRows {
+			t, err := u.importTiger(ctx, r, names, userID)
+			if err := addRowError(report, tigersFile, r, err); err != nil {
+				return err
+			}
+			if t != nil {
+				names[t.Name] = t.ID
+				report.TigersImported++
+			}
+		}
+
+		for _, r := range sightingRows {
+			err := u.importSighting(ctx, r, names, userID)
+			if err := addRowError(report, sightingsFile, r, err); err != nil {
+				return err
+			}
+			if err == nil {
+				report.SightingsImported++
+			}
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	// rows that could not be parsed were reported before the others
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		a, b := report.Errors[i], report.Errors[j]
+		if a.File != b.File {
+			return a.File == tigersFile
+		}
+		return a.Row < b.Row
+	})
+
+	return report, nil
+}
+
+func (u *usecase) importTiger(ctx context.Context, r row, names map[string]uint, userID uint) (*model.Tiger, error) {
+	name, err := r.string("name")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := names[name]; ok {
+		return nil, entities.NewErrInvalidImportRow(fmt.Sprintf("tiger %q appears more than once in the tigers file", name))
+	}
+
+	dob, err := r.time("date_of_birth")
+	if err != nil {
+		return nil, err
+	}
+
+	lastSeen, err := r.time("last_seen")
+	if err != nil {
+		return nil, err
+	}
+
+	lat, lng, err := r.coordinates("last_latitude", "last_longitude")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &model.NewTiger{
+		Name:          name,
+		DateOfBirth:   dob,
+		LastSeen:      lastSeen,
+		LastLatitude:  lat,
+		LastLongitude: lng,
+	}
+
+	if r.has("reserve_id") {
+		id, err := r.uint("reserve_id")
+		if err != nil {
+			return nil, err
+		}
+
+		t.ReserveID = &id
+	}
+
+	return u.tigerUsecase.CreateTiger(ctx, t, userID)
+}
+
+func (u *usecase) importSighting(ctx context.Context, r row, names map[string]uint, userID uint) error {
+	var tigerID uint
+	if r.has("tiger_id") {
+		id, err := r.uint("tiger_id")
+		if err != nil {
+			return err
+		}
+
+		tigerID = id
+	} else {
+		name, err := r.string("tiger_name")
+		if err != nil {
+			return entities.NewErrInvalidImportRow("tiger_id or tiger_name is required")
+		}
+
+		id, ok := names[name]
+		if !ok {
+			return entities.NewErrInvalidImportRow(fmt.Sprintf("tiger %q was not imported from the tigers file", name))
+		}
+
+		tigerID = id
+	}
+
+	date, err := r.time("date")
+	if err != nil {
+		return err
+	}
+
+	lat, lng, err := r.coordinates("latitude", "longitude")
+	if err != nil {
+		return err
+	}
+
+	_, err = u.sightingUsecase.ImportSighting(ctx, &model.NewSighting{
+		Date:      &date,
+		Latitude:  &lat,
+		Longitude: &lng,
+		TigerID:   tigerID,
+	}, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrTigerNotFound
+	}
+
+	return err
+}
+
+// addRowError records err in the report when it is a validation error. Other errors
+// are returned so the whole import is aborted.
+func addRowError(report *model.ImportReport, file string, r row, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var serviceErr errs.ServiceError
+	if !errors.As(err, &serviceErr) {
+		return err
+	}
+
+	report.Errors = append(report.Errors, rowError(file, r.line, err))
+	return nil
+}
+
+func rowError(file string, line int, err error) *model.ImportRowError {
+	var serviceErr errs.ServiceError
+	errors.As(err, &serviceErr)
+
+	return &model.ImportRowError{
+		File:    file,
+		Row:     line,
+		Code:    serviceErr.ErrorCode,
+		Message: err.Error(),
+	}
+}
+
+func NewImportUsecase(
+	tigerUsecase entities.TigerUsecase,
+	sightingUsecase entities.SightingUsecase,
+	uow entities.UnitOfWork,
+) entities.ImportUsecase {
+	return &usecase{tigerUsecase, sightingUsecase, uow}
+}
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const tigersCSV = "\ufeffName,Date_Of_Birth,Last_Seen,Last_Latitude,Last_Longitude,Reserve_ID\n" +
+	"tiger-1,2015-03-01,2020-01-01T08:00:00Z,-7.55,110.83,\n" +
+	"tiger-2,2016-04-01,2020-02-01,-7.25,110.53,1\n"
+
+func TestUsecase_Import(t *testing.T) {
+	dbErr := errors.New("database is locked")
+
+	testCases := []struct {
+		name string
+
+		tigers    string
+		sightings string
+		dryRun    bool
+
+		sightingErr error
+
+		want    *model.ImportReport
+		wantErr error
+	}{
+		{
+			name:      "should import tigers and sightings referring to them by name or id",
+			tigers:    tigersCSV,
+			sightings: "tiger_name,tiger_id,date,latitude,longitude\ntiger-1,,2019-06-01,-7.45,110.73\n,7,2019-07-01,-7.35,110.63\n",
+			want: &model.ImportReport{
+				TigersImported:    2,
+				SightingsImported: 2,
+				Errors:            []*model.ImportRowError{},
+			},
+		},
+		{
+			name:      "should validate without storing given dry run",
+			tigers:    tigersCSV,
+			sightings: "tiger_name,date,latitude,longitude\ntiger-2,2019-06-01,-7.45,110.73\n",
+			dryRun:    true,
+			want: &model.ImportReport{
+				DryRun:            true,
+				TigersImported:    2,
+				SightingsImported: 1,
+				Errors:            []*model.ImportRowError{},
+			},
+		},
+		{
+			name: "should skip and report invalid rows",
+			tigers: tigersCSV +
+				"tiger-1,2016-04-01,2020-02-01,-7.25,110.53,\n" +
+				"tiger-3,yesterday,2020-02-01,-7.25,110.53,\n",
+			sightings: "tiger_name,date,latitude,longitude\n" +
+				"tiger-1,2019-06-01,-97.45,110.73\n" +
+				"tiger-4,2019-06-01,-7.45,110.73\n" +
+				"tiger-2,2019-06-01,-7.45,110.73,extra\n" +
+				",2019-06-01,-7.45,110.73\n",
+			sightingErr: entities.ErrTigerTooClose,
+			want: &model.ImportReport{
+				TigersImported: 2,
+				Errors: []*model.ImportRowError{
+					{File: "tigers", Row: 4, Code: "ErrInvalidImportRow", Message: `ErrInvalidImportRow: tiger "tiger-1" appears more than once in the tigers file`},
+					{File: "tigers", Row: 5, Code: "ErrInvalidImportRow", Message: "ErrInvalidImportRow: date_of_birth should be in RFC3339 or YYYY-MM-DD format"},
+					{File: "sightings", Row: 2, Code: "ErrInvalidCoordinates", Message: entities.ErrInvalidCoordinates.Error()},
+					{File: "sightings", Row: 3, Code: "ErrInvalidImportRow", Message: `ErrInvalidImportRow: tiger "tiger-4" was not imported from the tigers file`},
+					{File: "sightings", Row: 4, Code: "ErrTigerTooClose", Message: entities.ErrTigerTooClose.Error()},
+					{File: "sightings", Row: 5, Code: "ErrInvalidImportRow", Message: "ErrInvalidImportRow: tiger_id or tiger_name is required"},
+				},
+			},
+		},
+		{
+			name:        "should report ErrTigerNotFound given unknown tiger id",
+			sightings:   "tiger_id,date,latitude,longitude\n7,2019-06-01,-7.45,110.73\n",
+			sightingErr: gorm.ErrRecordNotFound,
+			want: &model.ImportReport{
+				Errors: []*model.ImportRowError{
+					{File: "sightings", Row: 2, Code: "ErrTigerNotFound", Message: entities.ErrTigerNotFound.Error()},
+				},
+			},
+		},
+		{
+			name:      "should return ErrInvalidCSVHeader given missing columns",
+			sightings: "date,latitude\n2019-06-01,-7.45\n",
+			wantErr:   entities.ErrInvalidCSVHeader,
+		},
+		{
+			name:        "should abort the import given an unexpected error",
+			sightings:   "tiger_id,date,latitude,longitude\n7,2019-06-01,-7.45,110.73\n",
+			sightingErr: dbErr,
+			wantErr:     dbErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tigerUsecase := mocks.NewTigerUsecase(t)
+			sightingUsecase := mocks.NewSightingUsecase(t)
+
+			uow := mocks.NewUnitOfWork(t)
+			uow.
+				On("Do", mock.Anything, mock.Anything).
+				Return(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				}).
+				Maybe()
+
+			tigerUsecase.
+				On("CreateTiger", mock.Anything, mock.Anything, uint(1)).
+				Return(func(ctx context.Context, t *model.NewTiger, userID uint) (*model.Tiger, error) {
+					id := uint(len(t.Name))
+					if t.ReserveID != nil {
+						id += *t.ReserveID
+					}
+					return &model.Tiger{ID: id, Name: t.Name}, nil
+				}).
+				Maybe()
+
+			sightingUsecase.
+				On("ImportSighting", mock.Anything, mock.Anything, uint(1)).
+				Return(func(ctx context.Context, s *model.NewSighting, userID uint) (*model.Sighting, error) {
+					if tc.sightingErr != nil {
+						return nil, tc.sightingErr
+					}
+					return &model.Sighting{TigerID: s.TigerID}, nil
+				}).
+				Maybe()
+
+			var tigers, sightings io.Reader
+			if tc.tigers != "" {
+				tigers = strings.NewReader(tc.tigers)
+			}
+			if tc.sightings != "" {
+				sightings = strings.NewReader(tc.sightings)
+			}
+
+			u := NewImportUsecase(tigerUsecase, sightingUsecase, uow)
+
+			res, err := u.Import(context.Background(), tigers, sightings, 1, tc.dryRun)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Nil(t, res)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package reserve
+
+import (
+	"context"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"gorm.io/gorm"
+)
+
+type repo struct {
+	db *gorm.DB
+}
+
+// Create implements entities.ReserveRepository.
+func (r *repo) Create(ctx context.Context, reserve *entities.Reserve) error {
+	err := db.Conn(ctx, r.db).Create(reserve).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FindAll implements entities.ReserveRepository.
+func (r *repo) FindAll(ctx context.Context) ([]entities.Reserve, error) {
+	var res []entities.Reserve
+	err := db.Conn(ctx, r.db).Order("name ASC").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindByID implements entities.ReserveRepository.
+func (r *repo) FindByID(ctx context.Context, id uint) (*entities.Reserve, error) {
+	var res entities.Reserve
+	err := db.Conn(ctx, r.db).First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// Update implements entities.ReserveRepository.
+func (r *repo) Update(ctx context.Context, reserve *entities.Reserve, id uint) error {
+	if reserve.ID == 0 {
+		reserve.ID = id
+	}
+
+	err := db.Conn(ctx, r.db).Save(reserve).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func NewReserveRepository(db *gorm.DB) entities.ReserveRepository {
+	return &repo{db}
+}
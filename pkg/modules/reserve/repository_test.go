@@ -0,0 +1,94 @@
+package reserve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRepository_FindByID(t *testing.T) {
+	tests := []struct {
+		name string
+
+		id      uint
+		want    *entities.Reserve
+		wantErr error
+	}{
+		{
+			name: "should return reserve and nil error",
+			id:   1,
+			want: &entities.Reserve{
+				Model:            gorm.Model{ID: 1},
+				Name:             "reserve-1",
+				MinDistanceKm:    1,
+				NearbyAfterHours: 24,
+			},
+		},
+		{
+			name:    "should return ErrRecordNotFound given reserve not found",
+			id:      2,
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			repo := NewReserveRepository(d)
+			SeedReserve(d)
+
+			res, err := repo.FindByID(context.Background(), tc.id)
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.want != nil {
+				assert.Equal(t, tc.want.ID, res.ID)
+				assert.Equal(t, tc.want.Name, res.Name)
+				assert.Equal(t, tc.want.MinDistanceKm, res.MinDistanceKm)
+				assert.Equal(t, tc.want.NearbyAfterHours, res.NearbyAfterHours)
+			}
+		})
+	}
+}
+
+func TestRepository_CreateAndUpdate(t *testing.T) {
+	d := db.GetTestDB()
+	repo := NewReserveRepository(d)
+	SeedReserve(d)
+
+	r := &entities.Reserve{Name: "a-reserve", MinDistanceKm: 3}
+	err := repo.Create(context.Background(), r)
+	assert.Nil(t, err)
+	assert.Equal(t, uint(2), r.ID)
+
+	r.MinDistanceKm = 0.5
+	err = repo.Update(context.Background(), r, r.ID)
+	assert.Nil(t, err)
+
+	res, err := repo.FindAll(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, res, 2)
+	// ordered by name
+	assert.Equal(t, "a-reserve", res[0].Name)
+	assert.Equal(t, 0.5, res[0].MinDistanceKm)
+	assert.Equal(t, "reserve-1", res[1].Name)
+}
+
+func SeedReserve(d *gorm.DB) {
+	err := d.AutoMigrate(&entities.Reserve{})
+	if err != nil {
+		panic(err)
+	}
+
+	err = d.Create(&entities.Reserve{
+		Name:             "reserve-1",
+		MinDistanceKm:    1,
+		NearbyAfterHours: 24,
+	}).Error
+	if err != nil {
+		panic(err)
+	}
+}
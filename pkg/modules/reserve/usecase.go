@@ -0,0 +1,105 @@
+package reserve
+
+import (
+	"context"
+	"errors"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"gorm.io/gorm"
+)
+
+type usecase struct {
+	repo entities.ReserveRepository
+}
+
+// CreateReserve implements entities.ReserveUsecase.
+func (u *usecase) CreateReserve(ctx context.Context, reserve *model.NewReserve) (*model.Reserve, error) {
+	r := entities.Reserve{
+		Name:          reserve.Name,
+		MinDistanceKm: reserve.MinDistanceKm,
+	}
+
+	if reserve.NearbyAfterHours != nil {
+		r.NearbyAfterHours = *reserve.NearbyAfterHours
+	}
+
+	if r.MinDistanceKm < 0 || r.NearbyAfterHours < 0 {
+		return nil, entities.ErrInvalidSightingRule
+	}
+
+	err := u.repo.Create(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Reserve{
+		ID:               r.ID,
+		Name:             r.Name,
+		MinDistanceKm:    r.MinDistanceKm,
+		NearbyAfterHours: r.NearbyAfterHours,
+	}, nil
+}
+
+// UpdateReserve implements entities.ReserveUsecase.
+func (u *usecase) UpdateReserve(ctx context.Context, id uint, reserve *model.UpdateReserve) (*model.Reserve, error) {
+	r, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrReserveNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if reserve.Name != nil {
+		r.Name = *reserve.Name
+	}
+
+	if reserve.MinDistanceKm != nil {
+		r.MinDistanceKm = *reserve.MinDistanceKm
+	}
+
+	if reserve.NearbyAfterHours != nil {
+		r.NearbyAfterHours = *reserve.NearbyAfterHours
+	}
+
+	if r.MinDistanceKm < 0 || r.NearbyAfterHours < 0 {
+		return nil, entities.ErrInvalidSightingRule
+	}
+
+	err = u.repo.Update(ctx, r, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Reserve{
+		ID:               r.ID,
+		Name:             r.Name,
+		MinDistanceKm:    r.MinDistanceKm,
+		NearbyAfterHours: r.NearbyAfterHours,
+	}, nil
+}
+
+// GetReserves implements entities.ReserveUsecase.
+func (u *usecase) GetReserves(ctx context.Context) ([]*model.Reserve, error) {
+	reserves, err := u.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.Reserve, len(reserves))
+	for i, r := range reserves {
+		res[i] = &model.Reserve{
+			ID:               r.ID,
+			Name:             r.Name,
+			MinDistanceKm:    r.MinDistanceKm,
+			NearbyAfterHours: r.NearbyAfterHours,
+		}
+	}
+
+	return res, nil
+}
+
+func NewReserveUsecase(repo entities.ReserveRepository) entities.ReserveUsecase {
+	return &usecase{repo}
+}
@@ -0,0 +1,141 @@
+package reserve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_CreateReserve(t *testing.T) {
+	nearby := 12.0
+	negative := -1.0
+
+	testCases := []struct {
+		name string
+
+		input     *model.NewReserve
+		createErr error
+		wantSaved bool
+
+		want    *model.Reserve
+		wantErr error
+	}{
+		{
+			name:      "should return reserve given valid input",
+			input:     &model.NewReserve{Name: "reserve-1", MinDistanceKm: 2, NearbyAfterHours: &nearby},
+			wantSaved: true,
+			want:      &model.Reserve{Name: "reserve-1", MinDistanceKm: 2, NearbyAfterHours: 12},
+		},
+		{
+			name:    "should return ErrInvalidSightingRule given negative distance",
+			input:   &model.NewReserve{Name: "reserve-1", MinDistanceKm: -2},
+			wantErr: entities.ErrInvalidSightingRule,
+		},
+		{
+			name:    "should return ErrInvalidSightingRule given negative nearby window",
+			input:   &model.NewReserve{Name: "reserve-1", MinDistanceKm: 2, NearbyAfterHours: &negative},
+			wantErr: entities.ErrInvalidSightingRule,
+		},
+		{
+			name:      "should return err given failed to create reserve",
+			input:     &model.NewReserve{Name: "reserve-1", MinDistanceKm: 2},
+			createErr: errors.New("db error"),
+			wantSaved: true,
+			wantErr:   errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewReserveRepository(t)
+			uc := NewReserveUsecase(repo)
+
+			if tc.wantSaved {
+				repo.
+					On("Create", mock.Anything, mock.Anything).
+					Return(tc.createErr).
+					Once()
+			}
+
+			res, err := uc.CreateReserve(context.Background(), tc.input)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_UpdateReserve(t *testing.T) {
+	distance := 0.5
+	negative := -1.0
+
+	testCases := []struct {
+		name string
+
+		input   *model.UpdateReserve
+		findErr error
+		saved   bool
+
+		want    *model.Reserve
+		wantErr error
+	}{
+		{
+			name:  "should return updated reserve given valid input",
+			input: &model.UpdateReserve{MinDistanceKm: &distance},
+			saved: true,
+			want:  &model.Reserve{ID: 1, Name: "reserve-1", MinDistanceKm: 0.5, NearbyAfterHours: 24},
+		},
+		{
+			name:    "should return ErrReserveNotFound given reserve not found",
+			input:   &model.UpdateReserve{MinDistanceKm: &distance},
+			findErr: gorm.ErrRecordNotFound,
+			wantErr: entities.ErrReserveNotFound,
+		},
+		{
+			name:    "should return ErrInvalidSightingRule given negative distance",
+			input:   &model.UpdateReserve{MinDistanceKm: &negative},
+			wantErr: entities.ErrInvalidSightingRule,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewReserveRepository(t)
+			uc := NewReserveUsecase(repo)
+
+			var found *entities.Reserve
+			if tc.findErr == nil {
+				found = &entities.Reserve{
+					Model:            gorm.Model{ID: 1},
+					Name:             "reserve-1",
+					MinDistanceKm:    1,
+					NearbyAfterHours: 24,
+				}
+			}
+
+			repo.
+				On("FindByID", mock.Anything, uint(1)).
+				Return(found, tc.findErr).
+				Once()
+
+			if tc.saved {
+				repo.
+					On("Update", mock.Anything, mock.Anything, uint(1)).
+					Return(nil).
+					Once()
+			}
+
+			res, err := uc.UpdateReserve(context.Background(), 1, tc.input)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
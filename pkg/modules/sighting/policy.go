@@ -0,0 +1,106 @@
+package sighting
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/config"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMinDistanceKm     = 5.0
+	defaultMaxSpeedKmh       = 60.0
+	defaultExifMaxDistanceKm = 1.0
+	defaultExifMaxHours      = 1.0
+)
+
+type policy struct {
+	reserveRepo entities.ReserveRepository
+	def         entities.SightingRule
+}
+
+// RuleFor implements entities.SightingPolicy.
+//
+// The most specific setting wins: the tiger's own overrides, then the rule of its
+// reserve, then the default rule.
+func (p *policy) RuleFor(ctx context.Context, tiger *entities.Tiger) (entities.SightingRule, error) {
+	rule := p.def
+
+	if tiger.ReserveID != nil {
+		r, err := p.reserveRepo.FindByID(ctx, *tiger.ReserveID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return rule, err
+		}
+
+		if r != nil {
+			rule.MinDistanceKm = r.MinDistanceKm
+			rule.NearbyAfter = hours(r.NearbyAfterHours)
+		}
+	}
+
+	if tiger.MinDistanceKm != nil {
+		rule.MinDistanceKm = *tiger.MinDistanceKm
+	}
+
+	if tiger.NearbyAfterHours != nil {
+		rule.NearbyAfter = hours(*tiger.NearbyAfterHours)
+	}
+
+	return rule, nil
+}
+
+func NewSightingPolicy(reserveRepo entities.ReserveRepository, def entities.SightingRule) entities.SightingPolicy {
+	return &policy{reserveRepo, def}
+}
+
+// DefaultSightingRule reads the default rule from the environment. Without any
+// configuration, sightings should be more than 5 km apart, no nearby window applies,
+// sightings implying a tiger moved faster than 60 km/h or with an image that was
+// submitted before are rejected, and sightings more than 1 km or 1 hour off the EXIF
+// data of their image are flagged for review.
+func DefaultSightingRule() entities.SightingRule {
+	rule := entities.SightingRule{
+		MinDistanceKm:     defaultMinDistanceKm,
+		MaxSpeedKmh:       defaultMaxSpeedKmh,
+		ExifMaxDistanceKm: defaultExifMaxDistanceKm,
+		ExifMaxTimeGap:    hours(defaultExifMaxHours),
+	}
+
+	if v, err := strconv.ParseFloat(config.Get(config.SIGHTING_MIN_DISTANCE_KM), 64); err == nil && v >= 0 {
+		rule.MinDistanceKm = v
+	}
+
+	if v, err := strconv.ParseFloat(config.Get(config.SIGHTING_NEARBY_AFTER_HOURS), 64); err == nil && v >= 0 {
+		rule.NearbyAfter = hours(v)
+	}
+
+	if v, err := strconv.ParseFloat(config.Get(config.SIGHTING_MAX_SPEED_KMH), 64); err == nil && v >= 0 {
+		rule.MaxSpeedKmh = v
+	}
+
+	if v, err := strconv.ParseBool(config.Get(config.SIGHTING_FLAG_IMPLAUSIBLE)); err == nil {
+		rule.FlagImplausible = v
+	}
+
+	if v, err := strconv.ParseBool(config.Get(config.SIGHTING_FLAG_DUPLICATE_IMAGE)); err == nil {
+		rule.FlagDuplicateImage = v
+	}
+
+	if v, err := strconv.ParseFloat(config.Get(config.SIGHTING_EXIF_MAX_DISTANCE_KM), 64); err == nil && v >= 0 {
+		rule.ExifMaxDistanceKm = v
+	}
+
+	if v, err := strconv.ParseFloat(config.Get(config.SIGHTING_EXIF_MAX_HOURS), 64); err == nil && v >= 0 {
+		rule.ExifMaxTimeGap = hours(v)
+	}
+
+	return rule
+}
+
+func hours(h float64) time.Duration {
+	return time.Duration(h * float64(time.Hour))
+}
@@ -0,0 +1,179 @@
+package sighting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestPolicy_RuleFor(t *testing.T) {
+	reserveID := uint(7)
+	override := 0.2
+	overrideHours := 2.0
+
+	def := entities.SightingRule{MinDistanceKm: 5}
+
+	testCases := []struct {
+		name string
+
+		tiger      *entities.Tiger
+		reserve    *entities.Reserve
+		reserveErr error
+
+		want    entities.SightingRule
+		wantErr error
+	}{
+		{
+			name:  "should return default rule given tiger without reserve and overrides",
+			tiger: &entities.Tiger{},
+			want:  def,
+		},
+		{
+			name:    "should return reserve rule given tiger in reserve",
+			tiger:   &entities.Tiger{ReserveID: &reserveID},
+			reserve: &entities.Reserve{MinDistanceKm: 1, NearbyAfterHours: 24},
+			want:    entities.SightingRule{MinDistanceKm: 1, NearbyAfter: 24 * time.Hour},
+		},
+		{
+			name: "should return tiger overrides given tiger in reserve with overrides",
+			tiger: &entities.Tiger{
+				ReserveID:        &reserveID,
+				MinDistanceKm:    &override,
+				NearbyAfterHours: &overrideHours,
+			},
+			reserve: &entities.Reserve{MinDistanceKm: 1, NearbyAfterHours: 24},
+			want:    entities.SightingRule{MinDistanceKm: 0.2, NearbyAfter: 2 * time.Hour},
+		},
+		{
+			name:       "should return default rule given reserve no longer exists",
+			tiger:      &entities.Tiger{ReserveID: &reserveID},
+			reserveErr: gorm.ErrRecordNotFound,
+			want:       def,
+		},
+		{
+			name:       "should return err given failed to fetch reserve",
+			tiger:      &entities.Tiger{ReserveID: &reserveID},
+			reserveErr: errors.New("db error"),
+			want:       def,
+			wantErr:    errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reserveRepo := mocks.NewReserveRepository(t)
+			p := NewSightingPolicy(reserveRepo, def)
+
+			if tc.tiger.ReserveID != nil {
+				reserveRepo.
+					On("FindByID", mock.Anything, reserveID).
+					Return(tc.reserve, tc.reserveErr).
+					Once()
+			}
+
+			res, err := p.RuleFor(context.Background(), tc.tiger)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestSightingRule_Check(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		rule     entities.SightingRule
+		distance float64
+		gap      time.Duration
+
+		wantErr error
+	}{
+		{
+			name:     "should return nil given distance beyond minimum",
+			rule:     entities.SightingRule{MinDistanceKm: 5},
+			distance: 5.1,
+		},
+		{
+			name:     "should return ErrTigerTooClose given distance within minimum",
+			rule:     entities.SightingRule{MinDistanceKm: 5},
+			distance: 5,
+			gap:      48 * time.Hour,
+			wantErr:  entities.NewErrTigerTooClose(5, 5),
+		},
+		{
+			name:     "should return nil given sightings far enough apart in time",
+			rule:     entities.SightingRule{MinDistanceKm: 5, NearbyAfter: 24 * time.Hour},
+			distance: 1,
+			gap:      -24 * time.Hour,
+		},
+		{
+			name:     "should return ErrTigerTooClose given sightings too close in time",
+			rule:     entities.SightingRule{MinDistanceKm: 5, NearbyAfter: 24 * time.Hour},
+			distance: 1,
+			gap:      23 * time.Hour,
+			wantErr:  entities.NewErrTigerTooClose(1, 5),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Check(tc.distance, tc.gap)
+
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestSightingRule_CheckSpeed(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		rule     entities.SightingRule
+		distance float64
+		gap      time.Duration
+
+		wantErr error
+	}{
+		{
+			name:     "should return nil given speed check is disabled",
+			rule:     entities.SightingRule{},
+			distance: 300,
+			gap:      time.Hour,
+		},
+		{
+			name:     "should return nil given speed within maximum",
+			rule:     entities.SightingRule{MaxSpeedKmh: 60},
+			distance: 120,
+			gap:      -2 * time.Hour,
+		},
+		{
+			name:     "should return ErrImplausibleMovement given speed beyond maximum",
+			rule:     entities.SightingRule{MaxSpeedKmh: 60},
+			distance: 300,
+			gap:      time.Hour,
+			wantErr:  entities.NewErrImplausibleMovement(300, time.Hour, 60),
+		},
+		{
+			name:     "should return ErrImplausibleMovement given tiger seen at two places at the same time",
+			rule:     entities.SightingRule{MaxSpeedKmh: 60},
+			distance: 6,
+			wantErr:  entities.NewErrImplausibleMovement(6, 0, 60),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.CheckSpeed(tc.distance, tc.gap)
+
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
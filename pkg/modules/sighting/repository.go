@@ -2,8 +2,13 @@ package sighting
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/scopes"
 	"gorm.io/gorm"
 )
@@ -13,7 +18,7 @@ type repo struct {
 }
 
 func (r *repo) Create(ctx context.Context, sighting *entities.Sighting) error {
-	err := r.db.WithContext(ctx).Create(sighting).Error
+	err := db.Conn(ctx, r.db).Create(sighting).Error
 	if err != nil {
 		return err
 	}
@@ -29,7 +34,7 @@ func (r *repo) FindByTigerID(
 ) ([]entities.Sighting, int, error) {
 	var res []entities.Sighting
 
-	q := r.db.WithContext(ctx).
+	q := db.Conn(ctx, r.db).
 		Model(&entities.Sighting{}).
 		Where("tiger_id = ?", tigerID)
 
@@ -53,6 +58,345 @@ func (r *repo) FindByTigerID(
 	return res, int(count), nil
 }
 
+func (r *repo) FindByID(ctx context.Context, id uint) (*entities.Sighting, error) {
+	var res entities.Sighting
+	err := db.Conn(ctx, r.db).First(&res, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// FindAdjacent returns the sightings of a tiger that come chronologically right
+// before (or at) and right after the given date. Either of them is nil when there
+// is no such sighting. excludeID can be used to skip the sighting being edited.
+func (r *repo) FindAdjacent(
+	ctx context.Context,
+	tigerID uint,
+	date time.Time,
+	excludeID uint,
+) (*entities.Sighting, *entities.Sighting, error) {
+	q := db.Conn(ctx, r.db).
+		Model(&entities.Sighting{}).
+		Where("tiger_id = ? AND id <> ?", tigerID, excludeID)
+
+	var prev entities.Sighting
+	err := q.Session(&gorm.Session{}).
+		Where("date <= ?", date).
+		Order("date DESC, id DESC").
+		First(&prev).
+		Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	var next entities.Sighting
+	err = q.Session(&gorm.Session{}).
+		Where("date > ?", date).
+		Order("date ASC, id ASC").
+		First(&next).
+		Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	var p, n *entities.Sighting
+	if prev.ID != 0 {
+		p = &prev
+	}
+	if next.ID != 0 {
+		n = &next
+	}
+
+	return p, n, nil
+}
+
+func (r *repo) Update(ctx context.Context, sighting *entities.Sighting, id uint) error {
+	if sighting.ID == 0 {
+		sighting.ID = id
+	}
+
+	err := db.Conn(ctx, r.db).Save(sighting).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, id uint) error {
+	res := db.Conn(ctx, r.db).Delete(&entities.Sighting{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// FindInBounds returns the sightings inside bounds, newest first, optionally limited
+// to the sightings between from and to.
+func (r *repo) FindInBounds(
+	ctx context.Context,
+	bounds entities.Bounds,
+	from, to *time.Time,
+) ([]entities.Sighting, error) {
+	var res []entities.Sighting
+
+	q := inBounds(db.Conn(ctx, r.db).Model(&entities.Sighting{}), bounds)
+
+	if from != nil {
+		q = q.Where("date >= ?", *from)
+	}
+
+	if to != nil {
+		q = q.Where("date <= ?", *to)
+	}
+
+	err := q.Order("date DESC").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindTrack returns the sightings of a tiger in chronological order, optionally
+// limited to the sightings between from and to.
+func (r *repo) FindTrack(
+	ctx context.Context,
+	tigerID uint,
+	from, to *time.Time,
+) ([]entities.Sighting, error) {
+	var res []entities.Sighting
+
+	q := db.Conn(ctx, r.db).
+		Model(&entities.Sighting{}).
+		Where("tiger_id = ?", tigerID)
+
+	if from != nil {
+		q = q.Where("date >= ?", *from)
+	}
+
+	if to != nil {
+		q = q.Where("date <= ?", *to)
+	}
+
+	err := q.Order("date ASC, id ASC").Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindEach calls fn with every sighting matching filter in chronological order,
+// reading them from the database one row at a time. Sightings of deleted tigers are
+// left out.
+func (r *repo) FindEach(
+	ctx context.Context,
+	filter entities.SightingFilter,
+	fn func(*entities.SightingRecord) error,
+) error {
+	q := db.Conn(ctx, r.db).
+		Model(&entities.Sighting{}).
+		Select(`sightings.id, sightings.date, sightings.latitude, sightings.longitude,
+			sightings.image_url, sightings.needs_review, sightings.tiger_id, tigers.name AS tiger_name,
+			sightings.user_id, users.name AS user_name`).
+		Joins("JOIN tigers ON tigers.id = sightings.tiger_id AND tigers.deleted_at IS NULL").
+		Joins("LEFT JOIN users ON users.id = sightings.user_id")
+
+	if filter.TigerID != nil {
+		q = q.Where("sightings.tiger_id = ?", *filter.TigerID)
+	}
+
+	if filter.UserID != nil {
+		q = q.Where("sightings.user_id = ?", *filter.UserID)
+	}
+
+	if filter.From != nil {
+		q = q.Where("sightings.date >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		q = q.Where("sightings.date <= ?", *filter.To)
+	}
+
+	if filter.Bounds != nil {
+		q = inBounds(q, *filter.Bounds)
+	}
+
+	rows, err := q.Order("sightings.date ASC, sightings.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rec entities.SightingRecord
+		if err := q.ScanRows(rows, &rec); err != nil {
+			return err
+		}
+
+		if err := fn(&rec); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// FindSignatures implements entities.SightingRepository.
+func (r *repo) FindSignatures(ctx context.Context) ([]entities.SightingSignature, error) {
+	var res []entities.SightingSignature
+	err := db.Conn(ctx, r.db).
+		Model(&entities.Sighting{}).
+		Select(`sightings.id AS sighting_id, sightings.tiger_id, sightings.image_url,
+			sightings.image_signature AS signature`).
+		Joins("JOIN tigers ON tigers.id = sightings.tiger_id AND tigers.deleted_at IS NULL").
+		Where("sightings.image_signature <> ''").
+		Order("sightings.id ASC").
+		Scan(&res).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindDuplicateImage implements entities.SightingRepository.
+//
+// The database cannot count the bits two hashes differ in, so it only narrows the
+// sightings down to the ones sharing a band that is near enough with the hash, which
+// every duplicate does, and those are compared here.
+func (r *repo) FindDuplicateImage(ctx context.Context, hash imageproc.Hash, beforeID uint) (uint, error) {
+	near := hash.NearBands()
+
+	q := db.Conn(ctx, r.db).
+		Model(&entities.Sighting{}).
+		Select("id, image_hash").
+		Where(
+			"(image_hash_band0 IN ? OR image_hash_band1 IN ? OR image_hash_band2 IN ? OR image_hash_band3 IN ?)",
+			near[0], near[1], near[2], near[3],
+		)
+	if beforeID != 0 {
+		q = q.Where("id < ?", beforeID)
+	}
+
+	var candidates []struct {
+		ID        uint
+		ImageHash string
+	}
+	err := q.Order("id ASC").Scan(&candidates).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range candidates {
+		other, err := imageproc.ParseHash(c.ImageHash)
+		if err == nil && hash.IsDuplicate(other) {
+			return c.ID, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// ClusterInBounds groups the sightings inside bounds into a grid of cellSize degrees,
+// largest clusters first.
+func (r *repo) ClusterInBounds(
+	ctx context.Context,
+	bounds entities.Bounds,
+	cellSize float64,
+) ([]entities.SightingCluster, error) {
+	cellLat := "CAST((latitude + 90) / @size AS INTEGER)"
+	cellLng := "CAST((longitude + 180) / @size AS INTEGER)"
+
+	// rank the sightings of every cell so the most recent one can be picked
+	ranked := inBounds(db.Conn(ctx, r.db).Model(&entities.Sighting{}), bounds).
+		Select(
+			"id, latitude, longitude, "+cellLat+" AS cell_lat, "+cellLng+" AS cell_lng, "+
+				"ROW_NUMBER() OVER (PARTITION BY "+cellLat+", "+cellLng+" ORDER BY date DESC, id DESC) AS rn",
+			sql.Named("size", cellSize),
+		)
+
+	var rows []struct {
+		Count     int
+		Latitude  float64
+		Longitude float64
+		LatestID  uint
+	}
+	err := db.Conn(ctx, r.db).
+		Table("(?) AS ranked", ranked).
+		Select("COUNT(*) AS count, AVG(latitude) AS latitude, AVG(longitude) AS longitude, " +
+			"MAX(CASE WHEN rn = 1 THEN id END) AS latest_id").
+		Group("cell_lat, cell_lng").
+		Order("count DESC, latest_id DESC").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return []entities.SightingCluster{}, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.LatestID
+	}
+
+	var latest []entities.Sighting
+	err = db.Conn(ctx, r.db).Find(&latest, ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]entities.Sighting, len(latest))
+	for _, s := range latest {
+		byID[s.ID] = s
+	}
+
+	res := make([]entities.SightingCluster, len(rows))
+	for i, row := range rows {
+		res[i] = entities.SightingCluster{
+			Count:     row.Count,
+			Latitude:  row.Latitude,
+			Longitude: row.Longitude,
+			Latest:    byID[row.LatestID],
+		}
+	}
+
+	return res, nil
+}
+
+// inBounds limits q to the sightings inside bounds.
+func inBounds(q *gorm.DB, bounds entities.Bounds) *gorm.DB {
+	q = q.Where("latitude BETWEEN ? AND ?", bounds.MinLat, bounds.MaxLat)
+
+	if bounds.CrossesAntimeridian() {
+		return q.Where("longitude >= ? OR longitude <= ?", bounds.MinLng, bounds.MaxLng)
+	}
+
+	return q.Where("longitude BETWEEN ? AND ?", bounds.MinLng, bounds.MaxLng)
+}
+
+// ReassignTiger implements entities.SightingRepository.
+func (r *repo) ReassignTiger(ctx context.Context, fromID, toID uint) error {
+	return db.Conn(ctx, r.db).
+		Unscoped().
+		Model(&entities.Sighting{}).
+		Where("tiger_id = ?", fromID).
+		Update("tiger_id", toID).
+		Error
+}
+
 func NewSightingRepository(db *gorm.DB) entities.SightingRepository {
 	return &repo{db}
 }
@@ -2,11 +2,14 @@ package sighting
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/muhwyndhamhp/tigerhall-kittens/db"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/scopes"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -144,6 +147,604 @@ func TestRepository_FindByTigerID(t *testing.T) {
 	}
 }
 
+func TestRepository_FindByID(t *testing.T) {
+	now := time.Now()
+	tc := []struct {
+		name string
+
+		id      uint
+		want    *entities.Sighting
+		wantErr error
+	}{
+		{
+			name: "should return sighting with id 1",
+			id:   1,
+			want: &entities.Sighting{
+				Model: gorm.Model{
+					ID: 1,
+				},
+				Date:      now,
+				Latitude:  -7.550676,
+				Longitude: 110.828316,
+				TigerID:   1,
+				UserID:    1,
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "should return gorm.ErrRecordNotFound given sighting does not exist",
+			id:      2,
+			want:    nil,
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			r := NewSightingRepository(d)
+
+			res, err := r.FindByID(context.Background(), c.id)
+
+			assert.Equal(t, c.wantErr, err)
+			if c.want != nil {
+				assert.Equal(t, c.want.ID, res.ID)
+				assert.Equal(t, c.want.Date.Format(time.RFC3339), res.Date.Format(time.RFC3339))
+				assert.Equal(t, c.want.Latitude, res.Latitude)
+				assert.Equal(t, c.want.Longitude, res.Longitude)
+				assert.Equal(t, c.want.TigerID, res.TigerID)
+				assert.Equal(t, c.want.UserID, res.UserID)
+			}
+		})
+	}
+}
+
+func TestRepository_FindAdjacent(t *testing.T) {
+	now := time.Now()
+	tc := []struct {
+		name string
+
+		date      time.Time
+		excludeID uint
+		wantPrev  uint
+		wantNext  uint
+		wantErr   error
+	}{
+		{
+			name:     "should return seeded sighting as previous given later date",
+			date:     now.Add(time.Hour),
+			wantPrev: 1,
+			wantNext: 0,
+			wantErr:  nil,
+		},
+		{
+			name:     "should return seeded sighting as next given earlier date",
+			date:     now.Add(-time.Hour),
+			wantPrev: 0,
+			wantNext: 1,
+			wantErr:  nil,
+		},
+		{
+			name:      "should return no sighting given the only sighting is excluded",
+			date:      now.Add(time.Hour),
+			excludeID: 1,
+			wantPrev:  0,
+			wantNext:  0,
+			wantErr:   nil,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			r := NewSightingRepository(d)
+
+			prev, next, err := r.FindAdjacent(context.Background(), 1, c.date, c.excludeID)
+
+			assert.Equal(t, c.wantErr, err)
+
+			if c.wantPrev == 0 {
+				assert.Nil(t, prev)
+			} else {
+				assert.Equal(t, c.wantPrev, prev.ID)
+			}
+
+			if c.wantNext == 0 {
+				assert.Nil(t, next)
+			} else {
+				assert.Equal(t, c.wantNext, next.ID)
+			}
+		})
+	}
+}
+
+func TestRepository_FindInBounds(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+
+	tc := []struct {
+		name string
+
+		bounds entities.Bounds
+		from   *time.Time
+		to     *time.Time
+
+		wantIDs []uint
+	}{
+		{
+			name:    "should return sightings inside bounds newest first",
+			bounds:  entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111},
+			wantIDs: []uint{1, 2},
+		},
+		{
+			name:    "should return sightings inside bounds after from",
+			bounds:  entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111},
+			from:    &now,
+			wantIDs: []uint{1},
+		},
+		{
+			name:    "should return sightings inside bounds before to",
+			bounds:  entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111},
+			to:      &yesterday,
+			wantIDs: []uint{2},
+		},
+		{
+			name:    "should return sightings on both sides of the antimeridian",
+			bounds:  entities.Bounds{MinLat: -1, MinLng: 179, MaxLat: 1, MaxLng: -179},
+			wantIDs: []uint{3, 4},
+		},
+		{
+			name:    "should return no sightings given empty bounds",
+			bounds:  entities.Bounds{MinLat: 10, MinLng: 10, MaxLat: 11, MaxLng: 11},
+			wantIDs: []uint{},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			for _, s := range []entities.Sighting{
+				{Date: yesterday, Latitude: -7.250676, Longitude: 110.528316, TigerID: 1, UserID: 1},
+				{Date: now.Add(-48 * time.Hour), Latitude: 0.5, Longitude: 179.5, TigerID: 2, UserID: 1},
+				{Date: now.Add(-72 * time.Hour), Latitude: 0.5, Longitude: -179.5, TigerID: 2, UserID: 1},
+			} {
+				s := s
+				err := d.Create(&s).Error
+				assert.Nil(t, err)
+			}
+
+			r := NewSightingRepository(d)
+
+			res, err := r.FindInBounds(context.Background(), c.bounds, c.from, c.to)
+
+			assert.Nil(t, err)
+
+			ids := []uint{}
+			for _, s := range res {
+				ids = append(ids, s.ID)
+			}
+			assert.Equal(t, c.wantIDs, ids)
+		})
+	}
+}
+
+func TestRepository_ClusterInBounds(t *testing.T) {
+	now := time.Now()
+
+	d := db.GetTestDB()
+	SeedDB(d, now)
+
+	for _, s := range []entities.Sighting{
+		// same 1 degree cell as the seeded sighting, but older
+		{Date: now.Add(-time.Hour), Latitude: -7.250676, Longitude: 110.528316, TigerID: 2, UserID: 1},
+		// another cell
+		{Date: now.Add(-2 * time.Hour), Latitude: -6.5, Longitude: 110.5, TigerID: 3, UserID: 1},
+		// deleted sightings are not clustered
+		{Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: now, Valid: true}}, Date: now.Add(time.Hour), Latitude: -6.5, Longitude: 110.5, TigerID: 4, UserID: 1},
+	} {
+		s := s
+		err := d.Create(&s).Error
+		assert.Nil(t, err)
+	}
+
+	r := NewSightingRepository(d)
+
+	res, err := r.ClusterInBounds(context.Background(), entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -6, MaxLng: 111}, 1)
+
+	assert.Nil(t, err)
+	assert.Len(t, res, 2)
+
+	assert.Equal(t, 2, res[0].Count)
+	assert.InDelta(t, -7.400676, res[0].Latitude, 1e-9)
+	assert.InDelta(t, 110.678316, res[0].Longitude, 1e-9)
+	assert.Equal(t, uint(1), res[0].Latest.ID)
+	assert.Equal(t, uint(1), res[0].Latest.TigerID)
+
+	assert.Equal(t, 1, res[1].Count)
+	assert.Equal(t, -6.5, res[1].Latitude)
+	assert.Equal(t, 110.5, res[1].Longitude)
+	assert.Equal(t, uint(3), res[1].Latest.TigerID)
+
+	res, err = r.ClusterInBounds(context.Background(), entities.Bounds{MinLat: 10, MinLng: 10, MaxLat: 11, MaxLng: 11}, 1)
+
+	assert.Nil(t, err)
+	assert.Empty(t, res)
+}
+
+func TestRepository_FindTrack(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+
+	tc := []struct {
+		name string
+
+		tigerID uint
+		from    *time.Time
+		to      *time.Time
+
+		wantIDs []uint
+	}{
+		{
+			name:    "should return sightings of the tiger oldest first",
+			tigerID: 1,
+			wantIDs: []uint{2, 3, 1},
+		},
+		{
+			name:    "should return sightings of the tiger after from",
+			tigerID: 1,
+			from:    &yesterday,
+			wantIDs: []uint{3, 1},
+		},
+		{
+			name:    "should return sightings of the tiger before to",
+			tigerID: 1,
+			to:      &yesterday,
+			wantIDs: []uint{2, 3},
+		},
+		{
+			name:    "should return no sightings given tiger without sightings",
+			tigerID: 3,
+			wantIDs: []uint{},
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			for _, s := range []entities.Sighting{
+				{Date: now.Add(-48 * time.Hour), Latitude: -7.250676, Longitude: 110.528316, TigerID: 1, UserID: 1},
+				{Date: yesterday, Latitude: -7.350676, Longitude: 110.628316, TigerID: 1, UserID: 1},
+				{Date: yesterday, Latitude: 0.5, Longitude: 179.5, TigerID: 2, UserID: 1},
+			} {
+				s := s
+				err := d.Create(&s).Error
+				assert.Nil(t, err)
+			}
+
+			r := NewSightingRepository(d)
+
+			res, err := r.FindTrack(context.Background(), c.tigerID, c.from, c.to)
+
+			assert.Nil(t, err)
+
+			ids := []uint{}
+			for _, s := range res {
+				ids = append(ids, s.ID)
+			}
+			assert.Equal(t, c.wantIDs, ids)
+		})
+	}
+}
+
+func TestRepository_FindEach(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	tigerID := uint(1)
+	userID := uint(2)
+
+	tc := []struct {
+		name string
+
+		filter  entities.SightingFilter
+		fnErr   error
+		wantIDs []uint
+		wantErr error
+	}{
+		{
+			name:    "should return sightings of tigers that are not deleted oldest first",
+			wantIDs: []uint{2, 3, 1},
+		},
+		{
+			name:    "should return sightings of the tiger",
+			filter:  entities.SightingFilter{TigerID: &tigerID},
+			wantIDs: []uint{2, 1},
+		},
+		{
+			name:    "should return sightings of the user",
+			filter:  entities.SightingFilter{UserID: &userID},
+			wantIDs: []uint{3},
+		},
+		{
+			name:    "should return sightings in the date range",
+			filter:  entities.SightingFilter{From: &yesterday, To: &yesterday},
+			wantIDs: []uint{3},
+		},
+		{
+			name:    "should return sightings inside bounds",
+			filter:  entities.SightingFilter{Bounds: &entities.Bounds{MinLat: -7.6, MinLng: 110.8, MaxLat: -7.5, MaxLng: 110.9}},
+			wantIDs: []uint{1},
+		},
+		{
+			name:    "should stop at the first error of fn",
+			fnErr:   errors.New("broken pipe"),
+			wantIDs: []uint{2},
+			wantErr: errors.New("broken pipe"),
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			for _, tg := range []entities.Tiger{
+				{Name: "tiger-1"},
+				{Name: "tiger-2"},
+				{Name: "tiger-3", Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: now, Valid: true}}},
+			} {
+				tg := tg
+				err := d.Create(&tg).Error
+				assert.Nil(t, err)
+			}
+
+			err := d.Create(&entities.User{Name: "user-2", Email: "mail-2@example.com"}).Error
+			assert.Nil(t, err)
+
+			for _, s := range []entities.Sighting{
+				{Date: now.Add(-48 * time.Hour), Latitude: -7.250676, Longitude: 110.528316, TigerID: 1, UserID: 1},
+				{Date: yesterday, Latitude: -7.350676, Longitude: 110.628316, TigerID: 2, UserID: 2},
+				{Date: yesterday, Latitude: -7.350676, Longitude: 110.628316, TigerID: 3, UserID: 1},
+			} {
+				s := s
+				err := d.Create(&s).Error
+				assert.Nil(t, err)
+			}
+
+			r := NewSightingRepository(d)
+
+			ids := []uint{}
+			var records []entities.SightingRecord
+			err = r.FindEach(context.Background(), c.filter, func(s *entities.SightingRecord) error {
+				ids = append(ids, s.ID)
+				records = append(records, *s)
+				return c.fnErr
+			})
+
+			assert.Equal(t, c.wantErr, err)
+			assert.Equal(t, c.wantIDs, ids)
+
+			for _, rec := range records {
+				assert.Equal(t, fmt.Sprintf("tiger-%d", rec.TigerID), rec.TigerName)
+				assert.NotEmpty(t, rec.UserName)
+				assert.False(t, rec.Date.IsZero())
+			}
+		})
+	}
+}
+
+func TestRepository_Update(t *testing.T) {
+	now := time.Now()
+	tc := []struct {
+		name string
+
+		sighting *entities.Sighting
+		want     *entities.Sighting
+		wantErr  error
+	}{
+		{
+			name: "should update sighting with id 1",
+			sighting: &entities.Sighting{
+				Date:      now,
+				Latitude:  -7.250676,
+				Longitude: 111.828316,
+				TigerID:   1,
+				UserID:    1,
+			},
+			want: &entities.Sighting{
+				Model: gorm.Model{
+					ID: 1,
+				},
+				Date:      now,
+				Latitude:  -7.250676,
+				Longitude: 111.828316,
+				TigerID:   1,
+				UserID:    1,
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			r := NewSightingRepository(d)
+
+			err := r.Update(context.Background(), c.sighting, 1)
+
+			assert.Equal(t, c.wantErr, err)
+			if c.want != nil {
+				res, _ := r.FindByID(context.Background(), 1)
+				assert.Equal(t, c.want.ID, res.ID)
+				assert.Equal(t, c.want.Latitude, res.Latitude)
+				assert.Equal(t, c.want.Longitude, res.Longitude)
+			}
+		})
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	now := time.Now()
+	tc := []struct {
+		name string
+
+		id      uint
+		wantErr error
+	}{
+		{
+			name:    "should soft-delete sighting with id 1",
+			id:      1,
+			wantErr: nil,
+		},
+		{
+			name:    "should return gorm.ErrRecordNotFound given sighting does not exist",
+			id:      2,
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			r := NewSightingRepository(d)
+
+			err := r.Delete(context.Background(), c.id)
+
+			assert.Equal(t, c.wantErr, err)
+			if c.wantErr == nil {
+				_, err := r.FindByID(context.Background(), c.id)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+			}
+		})
+	}
+}
+
+func TestRepository_ReassignTiger(t *testing.T) {
+	now := time.Now()
+	tc := []struct {
+		name string
+
+		fromID    uint
+		wantCount int64
+	}{
+		{
+			name:      "should move the sightings of tiger 1 to tiger 2, deleted ones included",
+			fromID:    1,
+			wantCount: 2,
+		},
+		{
+			name:      "should move nothing given tiger without sightings",
+			fromID:    3,
+			wantCount: 0,
+		},
+	}
+
+	for _, c := range tc {
+		t.Run(c.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDB(d, now)
+
+			r := NewSightingRepository(d)
+
+			err := r.Create(context.Background(), &entities.Sighting{Date: now, TigerID: 1, UserID: 1})
+			assert.NoError(t, err)
+			err = r.Delete(context.Background(), 2)
+			assert.NoError(t, err)
+
+			err = r.ReassignTiger(context.Background(), c.fromID, 2)
+			assert.NoError(t, err)
+
+			var count int64
+			d.Unscoped().Model(&entities.Sighting{}).Where("tiger_id = ?", 2).Count(&count)
+			assert.Equal(t, c.wantCount, count)
+		})
+	}
+}
+
+func TestRepository_FindSignatures(t *testing.T) {
+	now := time.Now()
+	d := db.GetTestDB()
+	SeedDB(d, now)
+
+	for _, tg := range []entities.Tiger{
+		{Name: "tiger-1"},
+		{Name: "tiger-2", Model: gorm.Model{DeletedAt: gorm.DeletedAt{Time: now, Valid: true}}},
+	} {
+		tg := tg
+		err := d.Create(&tg).Error
+		assert.Nil(t, err)
+	}
+
+	for _, s := range []entities.Sighting{
+		{Date: now, TigerID: 1, UserID: 1, ImageURL: "https://img/2.jpg", ImageSignature: "sig-2"},
+		{Date: now, TigerID: 2, UserID: 1, ImageURL: "https://img/3.jpg", ImageSignature: "sig-3"},
+		{Date: now, TigerID: 1, UserID: 1, ImageURL: "https://img/4.jpg", ImageSignature: "sig-4"},
+	} {
+		s := s
+		err := d.Create(&s).Error
+		assert.Nil(t, err)
+	}
+
+	r := NewSightingRepository(d)
+
+	got, err := r.FindSignatures(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.SightingSignature{
+		{SightingID: 2, TigerID: 1, ImageURL: "https://img/2.jpg", Signature: "sig-2"},
+		{SightingID: 4, TigerID: 1, ImageURL: "https://img/4.jpg", Signature: "sig-4"},
+	}, got)
+}
+
+func TestRepository_FindDuplicateImage(t *testing.T) {
+	now := time.Now()
+	d := db.GetTestDB()
+	SeedDB(d, now)
+
+	for _, h := range []imageproc.Hash{0x00000000000000ff, 0x00000000000000fe, 0xff00ff00ff00ff00} {
+		s := entities.Sighting{Date: now, TigerID: 1, UserID: 1}
+		s.SetImageHash(h)
+		err := d.Create(&s).Error
+		assert.Nil(t, err)
+	}
+
+	r := NewSightingRepository(d)
+
+	testCases := []struct {
+		name     string
+		hash     imageproc.Hash
+		beforeID uint
+		want     uint
+	}{
+		{name: "should return the earliest duplicate given the same hash", hash: 0x00000000000000fe, want: 2},
+		{name: "should return a duplicate given a hash a few bits apart", hash: 0x000000000000000f, want: 2},
+		{name: "should only consider sightings before beforeID", hash: 0x00000000000000fe, beforeID: 3, want: 2},
+		{name: "should return 0 given no sighting before beforeID", hash: 0x00000000000000fe, beforeID: 2},
+		{name: "should return 0 given a hash that only shares a band", hash: 0x0000ffff000000ff},
+		{name: "should return 0 given a different hash", hash: 0xffff0000ffff0000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.FindDuplicateImage(context.Background(), tc.hash, tc.beforeID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func SeedDB(d *gorm.DB, now time.Time) {
 	err := d.AutoMigrate(&entities.User{}, &entities.Tiger{}, &entities.Sighting{})
 	if err != nil {
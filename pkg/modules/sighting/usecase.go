@@ -2,8 +2,12 @@ package sighting
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	geo "github.com/kellydunn/golang-geo"
 	"github.com/labstack/gommon/log"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
@@ -12,86 +16,334 @@ import (
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/scopes"
+	"gorm.io/gorm"
 )
 
 type usecase struct {
 	repo      entities.SightingRepository
 	tigerRepo entities.TigerRepository
 	userRepo  entities.UserRepository
+	uow       entities.UnitOfWork
+	policy    entities.SightingPolicy
 	s3        s3client.S3ClientInterface
 	ch        chan<- email.SightingEmail
 }
 
+// maxZoom is the highest zoom level clusters can be requested for, the same as most
+// map tile providers.
+const maxZoom = 22
+
+// clusterCellsPerTile is the number of grid cells a map tile is split into on each side
+// when clustering sightings.
+const clusterCellsPerTile = 8
+
+// maxSightingAttempts is how many times a sighting is created, updated or deleted
+// again against fresh tiger data after losing an optimistic locking race.
+const maxSightingAttempts = 3
+
+// retryOnConflict runs fn again while it fails with ErrConcurrentModification, up to
+// maxSightingAttempts times.
+func retryOnConflict(fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxSightingAttempts; attempt++ {
+		err = fn()
+		if !errors.Is(err, entities.ErrConcurrentModification) {
+			break
+		}
+	}
+
+	return err
+}
+
 // CreateSighting implements entities.SightingUsecase.
 func (u *usecase) CreateSighting(ctx context.Context, sighting *model.NewSighting, userID uint) (*model.Sighting, error) {
-	t, err := u.tigerRepo.FindByID(ctx, sighting.TigerID)
+	return u.createSighting(ctx, sighting, userID, true)
+}
+
+// ImportSighting implements entities.SightingUsecase.
+func (u *usecase) ImportSighting(ctx context.Context, sighting *model.NewSighting, userID uint) (*model.Sighting, error) {
+	return u.createSighting(ctx, sighting, userID, false)
+}
+
+// createSighting validates and stores a new sighting, retrying when the tiger was
+// updated concurrently. With notify set, users who reported the tiger before are
+// emailed about the new sighting.
+func (u *usecase) createSighting(
+	ctx context.Context,
+	sighting *model.NewSighting,
+	userID uint,
+	notify bool,
+) (*model.Sighting, error) {
+	s := entities.Sighting{
+		TigerID: sighting.TigerID,
+		UserID:  userID,
+	}
+
+	err := fillFromExif(&s, sighting)
 	if err != nil {
 		return nil, err
 	}
 
-	p0 := geo.NewPoint(t.LastLatitude, t.LastLongitude)
-	p1 := geo.NewPoint(sighting.Latitude, sighting.Longitude)
-	if p1.GreatCircleDistance(p0) <= 5.0 {
-		return nil, entities.ErrTigerTooClose
+	var t *entities.Tiger
+	err = retryOnConflict(func() error {
+		t, err = u.saveSighting(ctx, sighting.Image, &s)
+		return err
+	})
+	if err != nil {
+		u.removeImages(&s)
+		return nil, err
 	}
 
-	s := entities.Sighting{
-		Date:      sighting.Date,
-		Latitude:  sighting.Latitude,
-		Longitude: sighting.Longitude,
-		TigerID:   sighting.TigerID,
-		UserID:    userID,
-	}
-	if sighting.Image != nil {
-		if !imageproc.IsContentTypeValid(sighting.Image.ContentType, sighting.Image.Filename) {
+	m := &model.Sighting{
+		ID:             s.ID,
+		Date:           s.Date,
+		Latitude:       s.Latitude,
+		Longitude:      s.Longitude,
+		TigerID:        s.TigerID,
+		UserID:         s.UserID,
+		NeedsReview:    s.NeedsReview,
+		MediumImageURL: s.MediumImageURL,
+	}
+
+	if s.ImageURL != "" {
+		m.ImageURL = &s.ImageURL
+	}
+
+	if notify {
+		go u.queueEmail(t, &s)
+	}
+
+	return m, nil
+}
+
+// saveSighting validates s against the current state of its tiger and stores it
+// together with the tiger's new last known position. The image is only processed and
+// uploaded on the first attempt, retries reuse s.ImageHash and s.ImageURL.
+func (u *usecase) saveSighting(ctx context.Context, img *graphql.Upload, s *entities.Sighting) (*entities.Tiger, error) {
+	t, err := u.tigerRepo.FindByID(ctx, s.TigerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Status == model.TigerStatusDeceased {
+		return nil, entities.ErrTigerDeceased
+	}
+
+	// the tiger may have been looked up through one of its aliases
+	s.TigerID = t.ID
+
+	rule, err := u.policy.RuleFor(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.validateMovement(ctx, t, s, rule, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if img != nil && s.ImageHash == "" {
+		if !imageproc.IsContentTypeValid(img.ContentType, img.Filename) {
 			return nil, entities.ErrInvalidImageType
 		}
 
-		r, size, err := imageproc.ResizeImage(sighting.Image.File, sighting.Image.Filename)
+		hash, err := imageproc.ComputeHash(img.File)
 		if err != nil {
 			return nil, err
 		}
 
-		url, err := u.s3.UploadImage(ctx, r, sighting.Image.Filename, sighting.Image.ContentType, int64(size))
+		sig, err := imageproc.ComputeSignature(img.File)
 		if err != nil {
 			return nil, err
 		}
 
-		s.ImageURL = url
+		s.SetImageHash(hash)
+		s.ImageSignature = sig.String()
 	}
 
-	err = u.repo.Create(ctx, &s)
+	// checked on every attempt, validateMovement resets the review flag
+	err = u.validateImage(ctx, s, rule)
 	if err != nil {
 		return nil, err
 	}
 
-	t.LastSeen = s.Date
-	t.LastLatitude = s.Latitude
-	t.LastLongitude = s.Longitude
+	if !rule.MatchesExif(s) {
+		s.NeedsReview = true
+	}
+
+	if img != nil && s.ImageURL == "" {
+		r, err := s3client.UploadRenditions(ctx, u.s3, img.File, img.Filename, img.ContentType)
+		if err != nil {
+			return nil, err
+		}
+
+		s.ImageURL = r.ThumbURL
+		s.MediumImageURL = r.MediumURL
+		s.OriginalImageKey = r.OriginalKey
+	}
+
+	row := *s
+	err = u.uow.Do(ctx, func(ctx context.Context) error {
+		err := u.repo.Create(ctx, &row)
+		if err != nil {
+			return err
+		}
+
+		// backdated sightings should not clobber the tiger's last known position, but the
+		// tiger is still saved so its version moves and concurrent sightings conflict
+		if !row.Date.Before(t.LastSeen) {
+			t.LastSeen = row.Date
+			t.LastLatitude = row.Latitude
+			t.LastLongitude = row.Longitude
+		}
 
-	err = u.tigerRepo.Update(ctx, t, t.ID)
+		return u.tigerRepo.Update(ctx, t, t.ID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	m := &model.Sighting{
-		ID:        s.ID,
-		Date:      s.Date,
-		Latitude:  s.Latitude,
-		Longitude: s.Longitude,
-		TigerID:   s.TigerID,
-		UserID:    s.UserID,
+	*s = row
+	return t, nil
+}
+
+// validateMovement checks a sighting against the sightings that come chronologically
+// right before and after it, using the sighting rule that applies to the tiger. When
+// the tiger has no other sightings and useLastSeen is set, its last known position
+// is used instead. Implausibly fast movements either fail or flag s for review,
+// depending on the rule.
+func (u *usecase) validateMovement(
+	ctx context.Context,
+	t *entities.Tiger,
+	s *entities.Sighting,
+	rule entities.SightingRule,
+	useLastSeen bool,
+) error {
+	prev, next, err := u.repo.FindAdjacent(ctx, t.ID, s.Date, s.ID)
+	if err != nil {
+		return err
 	}
 
-	if s.ImageURL != "" {
-		m.ImageURL = &s.ImageURL
+	var neighbours []entities.Sighting
+	for _, n := range []*entities.Sighting{prev, next} {
+		if n != nil {
+			neighbours = append(neighbours, *n)
+		}
 	}
 
-	go u.queueEmail(t, s.ImageURL)
-	return m, nil
+	if len(neighbours) == 0 && useLastSeen {
+		neighbours = append(neighbours, entities.Sighting{
+			Date:      t.LastSeen,
+			Latitude:  t.LastLatitude,
+			Longitude: t.LastLongitude,
+		})
+	}
+
+	s.NeedsReview = false
+
+	p := geo.NewPoint(s.Latitude, s.Longitude)
+	for _, n := range neighbours {
+		distance := p.GreatCircleDistance(geo.NewPoint(n.Latitude, n.Longitude))
+		gap := s.Date.Sub(n.Date)
+
+		err := rule.Check(distance, gap)
+		if err != nil {
+			return err
+		}
+
+		err = rule.CheckSpeed(distance, gap)
+		if errors.Is(err, entities.ErrImplausibleMovement) && rule.FlagImplausible {
+			s.NeedsReview = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (u *usecase) queueEmail(t *entities.Tiger, imageURL string) {
+// validateImage checks that the image of s was not submitted before with another
+// sighting, of any tiger. A duplicate either fails or flags s for review, depending on
+// the rule.
+func (u *usecase) validateImage(ctx context.Context, s *entities.Sighting, rule entities.SightingRule) error {
+	if s.ImageHash == "" {
+		return nil
+	}
+
+	hash, err := imageproc.ParseHash(s.ImageHash)
+	if err != nil {
+		return err
+	}
+
+	dup, err := u.repo.FindDuplicateImage(ctx, hash, s.ID)
+	if err != nil || dup == 0 {
+		return err
+	}
+
+	if rule.FlagDuplicateImage {
+		s.NeedsReview = true
+		return nil
+	}
+
+	return entities.NewErrDuplicateImage(dup)
+}
+
+// fillFromExif sets the date and position of s from the input, falling back to the
+// EXIF data of a JPEG image for the ones that are not given. The EXIF data is kept on
+// s to check the input against it.
+func fillFromExif(s *entities.Sighting, sighting *model.NewSighting) error {
+	exif := &imageproc.Exif{}
+	if img := sighting.Image; img != nil && img.ContentType == "image/jpeg" {
+		e, err := imageproc.ReadExif(img.File)
+		if err != nil {
+			// the image itself may be fine, it is checked when it is processed
+			log.Warn(err)
+		} else {
+			exif = e
+		}
+	}
+
+	s.SetExif(exif)
+
+	switch {
+	case sighting.Latitude != nil && sighting.Longitude != nil:
+		s.Latitude = *sighting.Latitude
+		s.Longitude = *sighting.Longitude
+	case sighting.Latitude == nil && sighting.Longitude == nil && exif.HasPosition():
+		s.Latitude = *exif.Latitude
+		s.Longitude = *exif.Longitude
+	default:
+		return entities.ErrIncompleteSighting
+	}
+
+	switch {
+	case sighting.Date != nil:
+		s.Date = *sighting.Date
+	case exif.Date != nil:
+		s.Date = *exif.Date
+	default:
+		return entities.ErrIncompleteSighting
+	}
+
+	return nil
+}
+
+// removeImages deletes the uploaded renditions of the image of s that ended up not
+// being referenced by any sighting because the transaction was rolled back.
+func (u *usecase) removeImages(s *entities.Sighting) {
+	err := s3client.DeleteRenditions(context.Background(), u.s3, s3client.Renditions{
+		ThumbURL:    s.ImageURL,
+		MediumURL:   s.MediumImageURL,
+		OriginalKey: s.OriginalImageKey,
+	})
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func (u *usecase) queueEmail(t *entities.Tiger, s *entities.Sighting) {
 	fmt.Println("Sending email to other users...")
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -108,22 +360,22 @@ func (u *usecase) queueEmail(t *entities.Tiger, imageURL string) {
 	}
 
 	sentUsr := map[string]bool{}
-	for _, s := range sh {
-		if _, ok := sentUsr[s.User.Email]; ok {
+	for _, h := range sh {
+		if _, ok := sentUsr[h.User.Email]; ok {
 			continue
 		}
 
 		m := email.SightingEmail{
-			DestinationEmail:  s.User.Email,
+			DestinationEmail:  h.User.Email,
 			TigerName:         t.Name,
-			SightingDate:      t.LastSeen.Format("2006-01-02 15:04:05"),
-			SightingLatitude:  fmt.Sprintf("%f", t.LastLatitude),
-			SightingLongitude: fmt.Sprintf("%f", t.LastLongitude),
-			ImageURL:          imageURL,
+			SightingDate:      s.Date.Format("2006-01-02 15:04:05"),
+			SightingLatitude:  fmt.Sprintf("%f", s.Latitude),
+			SightingLongitude: fmt.Sprintf("%f", s.Longitude),
+			ImageURL:          s.ImageURL,
 		}
 		u.ch <- m
 
-		sentUsr[s.User.Email] = true
+		sentUsr[h.User.Email] = true
 	}
 }
 
@@ -134,27 +386,277 @@ func (u *usecase) GetSightingsByTigerID(ctx context.Context, tigerID uint, page
 		return nil, 0, err
 	}
 
+	return toModels(sightings), count, nil
+}
+
+// GetSightingsInBounds implements entities.SightingUsecase.
+func (u *usecase) GetSightingsInBounds(
+	ctx context.Context,
+	bounds entities.Bounds,
+	from, to *time.Time,
+) ([]*model.Sighting, error) {
+	err := bounds.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if from != nil && to != nil && from.After(*to) {
+		return nil, entities.ErrInvalidDateRange
+	}
+
+	sightings, err := u.repo.FindInBounds(ctx, bounds, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return toModels(sightings), nil
+}
+
+// UpdateSighting implements entities.SightingUsecase.
+func (u *usecase) UpdateSighting(ctx context.Context, id uint, sighting *model.UpdateSighting, userID uint) (*model.Sighting, error) {
+	s, err := u.findOwnedSighting(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sighting.Date != nil {
+		s.Date = *sighting.Date
+	}
+
+	if sighting.Latitude != nil {
+		s.Latitude = *sighting.Latitude
+	}
+
+	if sighting.Longitude != nil {
+		s.Longitude = *sighting.Longitude
+	}
+
+	err = retryOnConflict(func() error {
+		return u.saveUpdatedSighting(ctx, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m := &model.Sighting{
+		ID:             s.ID,
+		Date:           s.Date,
+		Latitude:       s.Latitude,
+		Longitude:      s.Longitude,
+		TigerID:        s.TigerID,
+		UserID:         s.UserID,
+		NeedsReview:    s.NeedsReview,
+		MediumImageURL: s.MediumImageURL,
+	}
+
+	if s.ImageURL != "" {
+		m.ImageURL = &s.ImageURL
+	}
+
+	return m, nil
+}
+
+// DeleteSighting implements entities.SightingUsecase.
+func (u *usecase) DeleteSighting(ctx context.Context, id uint, userID uint) error {
+	s, err := u.findOwnedSighting(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	_, count, err := u.repo.FindByTigerID(ctx, s.TigerID, []scopes.Preload{}, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	if count <= 1 {
+		return entities.ErrLastSighting
+	}
+
+	return retryOnConflict(func() error {
+		return u.uow.Do(ctx, func(ctx context.Context) error {
+			err := u.repo.Delete(ctx, s.ID)
+			if err != nil {
+				return err
+			}
+
+			return u.refreshTigerLastSeen(ctx, s.TigerID)
+		})
+	})
+}
+
+// saveUpdatedSighting validates the changed sighting s against the current state of its
+// tiger and stores it together with the tiger's recomputed last known position.
+func (u *usecase) saveUpdatedSighting(ctx context.Context, s *entities.Sighting) error {
+	t, err := u.tigerRepo.FindByID(ctx, s.TigerID)
+	if err != nil {
+		return err
+	}
+
+	rule, err := u.policy.RuleFor(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	err = u.validateMovement(ctx, t, s, rule, false)
+	if err != nil {
+		return err
+	}
+
+	// the image cannot be replaced anymore, so a duplicate only keeps its review flag
+	rule.FlagDuplicateImage = true
+	err = u.validateImage(ctx, s, rule)
+	if err != nil {
+		return err
+	}
+
+	if !rule.MatchesExif(s) {
+		s.NeedsReview = true
+	}
+
+	return u.uow.Do(ctx, func(ctx context.Context) error {
+		err := u.repo.Update(ctx, s, s.ID)
+		if err != nil {
+			return err
+		}
+
+		// fails when the tiger changed since it was validated against
+		return u.tigerRepo.RefreshLastSeen(ctx, t)
+	})
+}
+
+func (u *usecase) findOwnedSighting(ctx context.Context, id uint, userID uint) (*entities.Sighting, error) {
+	s, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrSightingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.UserID != userID {
+		return nil, entities.ErrSightingNotOwned
+	}
+
+	return s, nil
+}
+
+// refreshTigerLastSeen recomputes the last seen date and location of a tiger
+// from its most recent remaining sighting.
+func (u *usecase) refreshTigerLastSeen(ctx context.Context, tigerID uint) error {
+	t, err := u.tigerRepo.FindByID(ctx, tigerID)
+	if err != nil {
+		return err
+	}
+
+	return u.tigerRepo.RefreshLastSeen(ctx, t)
+}
+
+// GetSightingClusters implements entities.SightingUsecase.
+func (u *usecase) GetSightingClusters(
+	ctx context.Context,
+	bounds entities.Bounds,
+	zoom int,
+) ([]*model.SightingCluster, error) {
+	err := bounds.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if zoom < 0 || zoom > maxZoom {
+		return nil, entities.ErrInvalidZoom
+	}
+
+	// a tile is 360 degrees wide at zoom 0 and halves with every zoom level
+	cellSize := 360 / math.Pow(2, float64(zoom)) / clusterCellsPerTile
+
+	clusters, err := u.repo.ClusterInBounds(ctx, bounds, cellSize)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.SightingCluster, len(clusters))
+	for i, c := range clusters {
+		res[i] = &model.SightingCluster{
+			Count:     c.Count,
+			Latitude:  c.Latitude,
+			Longitude: c.Longitude,
+			LastSeen:  c.Latest.Date,
+			TigerID:   c.Latest.TigerID,
+		}
+	}
+
+	return res, nil
+}
+
+// GetSightingExif implements entities.SightingUsecase.
+func (u *usecase) GetSightingExif(ctx context.Context, id uint) (*model.SightingExif, error) {
+	s, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrSightingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ExifLatitude == nil && s.ExifLongitude == nil && s.ExifDate == nil {
+		return nil, nil
+	}
+
+	return &model.SightingExif{
+		Latitude:  s.ExifLatitude,
+		Longitude: s.ExifLongitude,
+		Date:      s.ExifDate,
+	}, nil
+}
+
+// GetOriginalImageURL implements entities.SightingUsecase.
+func (u *usecase) GetOriginalImageURL(ctx context.Context, id uint, user *entities.User) (*string, error) {
+	s, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrSightingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.OriginalImageKey == "" || user == nil || (!user.IsAdmin() && user.ID != s.UserID) {
+		return nil, nil
+	}
+
+	url, err := u.s3.PresignPrivateImage(ctx, s.OriginalImageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &url, nil
+}
+
+func toModels(sightings []entities.Sighting) []*model.Sighting {
 	var result []*model.Sighting
 	for _, s := range sightings {
 		result = append(result, &model.Sighting{
-			ID:        s.ID,
-			Date:      s.Date,
-			Latitude:  s.Latitude,
-			Longitude: s.Longitude,
-			TigerID:   s.TigerID,
-			UserID:    s.UserID,
-			ImageURL:  &s.ImageURL,
+			ID:             s.ID,
+			Date:           s.Date,
+			Latitude:       s.Latitude,
+			Longitude:      s.Longitude,
+			TigerID:        s.TigerID,
+			UserID:         s.UserID,
+			ImageURL:       &s.ImageURL,
+			NeedsReview:    s.NeedsReview,
+			MediumImageURL: s.MediumImageURL,
 		})
 	}
-	return result, count, nil
+	return result
 }
 
 func NewSightingUsecase(
 	repo entities.SightingRepository,
 	tigerRepo entities.TigerRepository,
 	userRepo entities.UserRepository,
+	uow entities.UnitOfWork,
+	policy entities.SightingPolicy,
 	s3 s3client.S3ClientInterface,
 	ch chan<- email.SightingEmail,
 ) entities.SightingUsecase {
-	return &usecase{repo, tigerRepo, userRepo, s3, ch}
+	return &usecase{repo, tigerRepo, userRepo, uow, policy, s3, ch}
 }
@@ -1,16 +1,24 @@
 package sighting
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"image"
+	"image/color"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
+	geo "github.com/kellydunn/golang-geo"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/email"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
 	s3mocks "github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -18,11 +26,12 @@ import (
 
 func TestUsecase_CreateSighting(t *testing.T) {
 	now := time.Now()
+	lat, lng := -7.550676, 110.828316
 	req := &model.NewSighting{
 		TigerID:   101,
-		Date:      now,
-		Latitude:  -7.550676,
-		Longitude: 110.828316,
+		Date:      &now,
+		Latitude:  &lat,
+		Longitude: &lng,
 		// Image:     &graphql.Upload{}, TODO: handle testing for image
 	}
 
@@ -31,9 +40,14 @@ func TestUsecase_CreateSighting(t *testing.T) {
 		getTigerResp *entities.Tiger
 		getTigerErr  error
 
+		adjacentPrev *entities.Sighting
+		adjacentNext *entities.Sighting
+
 		createSightingErr error
 
-		updateTigerErr error
+		updateTigerErr    error
+		wantTigerUpdated  bool
+		wantTigerLatitude float64
 
 		emailFindByTigerResp []entities.Sighting
 		emailFindByTigerErr  error
@@ -47,6 +61,18 @@ func TestUsecase_CreateSighting(t *testing.T) {
 			getTigerErr: errors.New(""),
 			wantErr:     errors.New(""),
 		},
+		{
+			name: "should return ErrTigerDeceased given tiger is deceased",
+			getTigerResp: &entities.Tiger{
+				Model:         gorm.Model{ID: 101},
+				Name:          "tiger-1",
+				Status:        model.TigerStatusDeceased,
+				LastSeen:      now,
+				LastLatitude:  -7.250676,
+				LastLongitude: 110.828316,
+			},
+			wantErr: entities.ErrTigerDeceased,
+		},
 		{
 			name: "should return ErrTigerTooClose given latitude and longitude is less than 5.0 km from previous sighting",
 			getTigerResp: &entities.Tiger{
@@ -61,7 +87,7 @@ func TestUsecase_CreateSighting(t *testing.T) {
 				LastLatitude:  -7.550676,
 				LastLongitude: 110.828316,
 			},
-			wantErr: entities.ErrTigerTooClose,
+			wantErr: entities.NewErrTigerTooClose(0, 5),
 		},
 		{
 			name:              "should return valid model.Sighting given valid input without image and send email",
@@ -99,6 +125,7 @@ func TestUsecase_CreateSighting(t *testing.T) {
 				UserID:    201,
 				ImageURL:  nil,
 			},
+			wantTigerUpdated: true,
 			wantCh: &email.SightingEmail{
 				DestinationEmail:  "mail-1@example.com",
 				TigerName:         "tiger-1",
@@ -131,6 +158,60 @@ func TestUsecase_CreateSighting(t *testing.T) {
 				UserID:    201,
 				ImageURL:  nil,
 			},
+			wantTigerUpdated: true,
+		},
+		{
+			name: "should return ErrTigerTooClose given backdated sighting is less than 5.0 km from the next sighting",
+			getTigerResp: &entities.Tiger{
+				Model:         gorm.Model{ID: 101},
+				Name:          "tiger-1",
+				LastSeen:      now.Add(time.Hour),
+				LastLatitude:  -7.250676,
+				LastLongitude: 110.828316,
+			},
+			adjacentPrev: &entities.Sighting{
+				Date:      now.Add(-time.Hour),
+				Latitude:  -7.250676,
+				Longitude: 110.828316,
+			},
+			adjacentNext: &entities.Sighting{
+				Date:      now.Add(time.Hour),
+				Latitude:  -7.540676,
+				Longitude: 110.828316,
+			},
+			wantErr: entities.NewErrTigerTooClose(distanceKm(-7.550676, 110.828316, -7.540676, 110.828316), 5),
+		},
+		{
+			name: "should return valid model.Sighting given backdated sighting and keep tiger last seen untouched",
+			getTigerResp: &entities.Tiger{
+				Model:         gorm.Model{ID: 101},
+				Name:          "tiger-1",
+				LastSeen:      now.Add(time.Hour),
+				LastLatitude:  -7.250676,
+				LastLongitude: 110.828316,
+			},
+			adjacentPrev: &entities.Sighting{
+				Date:      now.Add(-time.Hour),
+				Latitude:  -7.850676,
+				Longitude: 110.828316,
+			},
+			adjacentNext: &entities.Sighting{
+				Date:      now.Add(time.Hour),
+				Latitude:  -7.250676,
+				Longitude: 110.828316,
+			},
+			emailFindByTigerResp: []entities.Sighting{},
+			want: &model.Sighting{
+				ID:        0,
+				Date:      now,
+				Latitude:  -7.550676,
+				Longitude: 110.828316,
+				TigerID:   101,
+				UserID:    201,
+				ImageURL:  nil,
+			},
+			wantTigerUpdated:  true,
+			wantTigerLatitude: -7.250676,
 		},
 	}
 
@@ -143,22 +224,29 @@ func TestUsecase_CreateSighting(t *testing.T) {
 			s3 := s3mocks.NewS3ClientInterface(t)
 			ch := make(chan email.SightingEmail)
 
-			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, s3, ch)
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
 
 			tigerRepo.
 				On("FindByID", mock.Anything, req.TigerID).
 				Return(tc.getTigerResp, tc.getTigerErr).
 				Once()
 
+			repo.
+				On("FindAdjacent", mock.Anything, req.TigerID, *req.Date, uint(0)).
+				Return(tc.adjacentPrev, tc.adjacentNext, nil).
+				Maybe()
+
 			repo.
 				On("Create", mock.Anything, mock.Anything).
 				Return(tc.createSightingErr).
 				Maybe()
 
-			tigerRepo.
-				On("Update", mock.Anything, mock.Anything, req.TigerID).
-				Return(tc.updateTigerErr).
-				Maybe()
+			if tc.wantTigerUpdated {
+				tigerRepo.
+					On("Update", mock.Anything, mock.Anything, req.TigerID).
+					Return(tc.updateTigerErr).
+					Once()
+			}
 
 			repo.
 				On("FindByTigerID", mock.Anything, uint(101), mock.Anything, 1, 1000).
@@ -178,50 +266,60 @@ func TestUsecase_CreateSighting(t *testing.T) {
 
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.want, res)
+			if tc.wantTigerLatitude != 0 {
+				assert.Equal(t, tc.wantTigerLatitude, tc.getTigerResp.LastLatitude)
+			}
 		})
 	}
 }
 
-func TestUsecase_GetSightingByTigerID(t *testing.T) {
+func TestUsecase_CreateSighting_ConcurrentModification(t *testing.T) {
 	now := time.Now()
+	lat, lng := -7.550676, 110.828316
+	req := &model.NewSighting{
+		TigerID:   101,
+		Date:      &now,
+		Latitude:  &lat,
+		Longitude: &lng,
+	}
+
 	testCases := []struct {
 		name string
 
-		getSightingsByTigerIDResp []entities.Sighting
-		getSightingsByTigerIDErr  error
+		// fresh position of the tiger after the first attempt lost the race
+		freshLatitude float64
+		updateErrs    []error
 
-		want    []*model.Sighting
+		want    *model.Sighting
 		wantErr error
 	}{
 		{
-			name: "should return valid []*model.Sighting given valid input",
-			getSightingsByTigerIDResp: []entities.Sighting{
-				{
-					Model: gorm.Model{
-						ID:        301,
-						CreatedAt: now,
-						UpdatedAt: now,
-					},
-					Date:      now,
-					Latitude:  -7.550676,
-					Longitude: 110.828316,
-					TigerID:   101,
-					UserID:    201,
-				},
+			name:          "should return ErrTigerTooClose given concurrent sighting moved the tiger close by",
+			freshLatitude: -7.540676,
+			updateErrs:    []error{entities.ErrConcurrentModification},
+			wantErr:       entities.NewErrTigerTooClose(distanceKm(-7.550676, 110.828316, -7.540676, 110.828316), 5),
+		},
+		{
+			name:          "should return valid model.Sighting given retry passes against fresh data",
+			freshLatitude: -7.150676,
+			updateErrs:    []error{entities.ErrConcurrentModification, nil},
+			want: &model.Sighting{
+				Date:      now,
+				Latitude:  -7.550676,
+				Longitude: 110.828316,
+				TigerID:   101,
+				UserID:    201,
 			},
-			getSightingsByTigerIDErr: nil,
-			want: []*model.Sighting{
-				{
-					ID:        301,
-					Date:      now,
-					Latitude:  -7.550676,
-					Longitude: 110.828316,
-					TigerID:   101,
-					UserID:    201,
-					ImageURL:  new(string),
-				},
+		},
+		{
+			name:          "should return ErrConcurrentModification given every attempt lost the race",
+			freshLatitude: -7.150676,
+			updateErrs: []error{
+				entities.ErrConcurrentModification,
+				entities.ErrConcurrentModification,
+				entities.ErrConcurrentModification,
 			},
-			wantErr: nil,
+			wantErr: entities.ErrConcurrentModification,
 		},
 	}
 
@@ -232,20 +330,1232 @@ func TestUsecase_GetSightingByTigerID(t *testing.T) {
 			userRepo := mocks.NewUserRepository(t)
 
 			s3 := s3mocks.NewS3ClientInterface(t)
-			ch := make(chan email.SightingEmail)
+			ch := make(chan email.SightingEmail, 1)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			tigerRepo.
+				On("FindByID", mock.Anything, req.TigerID).
+				Return(&entities.Tiger{
+					Model:         gorm.Model{ID: 101},
+					LastSeen:      now.Add(-time.Hour),
+					LastLatitude:  -7.250676,
+					LastLongitude: 110.828316,
+				}, nil).
+				Once()
+
+			tigerRepo.
+				On("FindByID", mock.Anything, req.TigerID).
+				Return(func(context.Context, uint) (*entities.Tiger, error) {
+					return &entities.Tiger{
+						Model:         gorm.Model{ID: 101},
+						LastSeen:      now.Add(-time.Minute),
+						LastLatitude:  tc.freshLatitude,
+						LastLongitude: 110.828316,
+						Version:       1,
+					}, nil
+				})
+
+			repo.
+				On("FindAdjacent", mock.Anything, req.TigerID, *req.Date, uint(0)).
+				Return(nil, nil, nil)
+
+			repo.
+				On("Create", mock.Anything, mock.Anything).
+				Return(nil)
 
-			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, s3, ch)
+			for _, err := range tc.updateErrs {
+				tigerRepo.
+					On("Update", mock.Anything, mock.Anything, req.TigerID).
+					Return(err).
+					Once()
+			}
 
 			repo.
 				On("FindByTigerID", mock.Anything, uint(101), mock.Anything, 1, 1000).
-				Return(tc.getSightingsByTigerIDResp, len(tc.getSightingsByTigerIDResp), tc.getSightingsByTigerIDErr).
+				Return([]entities.Sighting{}, 0, nil).
 				Maybe()
 
-			res, count, err := usecase.GetSightingsByTigerID(context.Background(), 101, 1, 1000)
+			res, err := usecase.CreateSighting(context.Background(), req, 201)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_CreateSighting_ImplausibleMovement(t *testing.T) {
+	now := time.Now()
+	lat, lng := -7.550676, 110.828316
+	req := &model.NewSighting{
+		TigerID:   101,
+		Date:      &now,
+		Latitude:  &lat,
+		Longitude: &lng,
+	}
+
+	// the tiger was last seen ~33 km away an hour ago
+	distance := distanceKm(-7.550676, 110.828316, -7.250676, 110.828316)
+
+	testCases := []struct {
+		name string
+
+		flagImplausible bool
+
+		want    *model.Sighting
+		wantErr error
+	}{
+		{
+			name:    "should return ErrImplausibleMovement given tiger moved faster than the maximum speed",
+			wantErr: entities.NewErrImplausibleMovement(distance, time.Hour, 20),
+		},
+		{
+			name:            "should return sighting flagged for review given implausible sightings are flagged",
+			flagImplausible: true,
+			want: &model.Sighting{
+				Date:        now,
+				Latitude:    -7.550676,
+				Longitude:   110.828316,
+				TigerID:     101,
+				UserID:      201,
+				NeedsReview: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail, 1)
+
+			policy := NewSightingPolicy(mocks.NewReserveRepository(t), entities.SightingRule{
+				MinDistanceKm:   5,
+				MaxSpeedKmh:     20,
+				FlagImplausible: tc.flagImplausible,
+			})
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), policy, s3, ch)
+
+			tigerRepo.
+				On("FindByID", mock.Anything, req.TigerID).
+				Return(&entities.Tiger{
+					Model:         gorm.Model{ID: 101},
+					LastSeen:      now.Add(-time.Hour),
+					LastLatitude:  -7.250676,
+					LastLongitude: 110.828316,
+				}, nil).
+				Once()
+
+			repo.
+				On("FindAdjacent", mock.Anything, req.TigerID, *req.Date, uint(0)).
+				Return(nil, nil, nil).
+				Once()
+
+			if tc.want != nil {
+				repo.
+					On("Create", mock.Anything, mock.MatchedBy(func(s *entities.Sighting) bool {
+						return s.NeedsReview
+					})).
+					Return(nil).
+					Once()
+
+				tigerRepo.
+					On("Update", mock.Anything, mock.Anything, req.TigerID).
+					Return(nil).
+					Once()
+
+				repo.
+					On("FindByTigerID", mock.Anything, uint(101), mock.Anything, 1, 1000).
+					Return([]entities.Sighting{}, 0, nil).
+					Maybe()
+			}
+
+			res, err := usecase.CreateSighting(context.Background(), req, 201)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_CreateSighting_DuplicateImage(t *testing.T) {
+	now := time.Now()
+	img := gradient()
+	hash := imageproc.HashOf(img)
+	url := "https://img/thumb/tiger.png"
+	mediumURL := "https://img/medium/tiger.png"
+	lat, lng := -7.550676, 110.828316
+
+	testCases := []struct {
+		name string
+
+		flagDuplicate bool
+		duplicateOf   uint
+
+		want    *model.Sighting
+		wantErr error
+	}{
+		{
+			name: "should return sighting given image was not submitted before",
+			want: &model.Sighting{
+				Date:           now,
+				Latitude:       -7.550676,
+				Longitude:      110.828316,
+				TigerID:        101,
+				UserID:         201,
+				ImageURL:       &url,
+				MediumImageURL: mediumURL,
+			},
+		},
+		{
+			name:        "should return ErrDuplicateImage given the same image was submitted before",
+			duplicateOf: 7,
+			wantErr:     entities.NewErrDuplicateImage(7),
+		},
+		{
+			name:          "should return sighting flagged for review given duplicate images are flagged",
+			flagDuplicate: true,
+			duplicateOf:   7,
+			want: &model.Sighting{
+				Date:           now,
+				Latitude:       -7.550676,
+				Longitude:      110.828316,
+				TigerID:        101,
+				UserID:         201,
+				ImageURL:       &url,
+				NeedsReview:    true,
+				MediumImageURL: mediumURL,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail, 1)
+
+			policy := NewSightingPolicy(mocks.NewReserveRepository(t), entities.SightingRule{
+				MinDistanceKm:      5,
+				FlagDuplicateImage: tc.flagDuplicate,
+			})
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), policy, s3, ch)
+
+			tigerRepo.
+				On("FindByID", mock.Anything, uint(101)).
+				Return(&entities.Tiger{
+					Model:         gorm.Model{ID: 101},
+					LastSeen:      now.Add(-48 * time.Hour),
+					LastLatitude:  -7.250676,
+					LastLongitude: 110.828316,
+				}, nil).
+				Once()
+
+			repo.
+				On("FindAdjacent", mock.Anything, uint(101), now, uint(0)).
+				Return(nil, nil, nil).
+				Once()
+
+			repo.
+				On("FindDuplicateImage", mock.Anything, hash, uint(0)).
+				Return(tc.duplicateOf, nil).
+				Once()
+
+			if tc.want != nil {
+				s3.
+					On("UploadImage", mock.Anything, mock.Anything, "thumb/tiger.png", "image/jpeg").
+					Return(url, nil).
+					Once()
+
+				s3.
+					On("UploadImage", mock.Anything, mock.Anything, "medium/tiger.png", "image/jpeg").
+					Return(mediumURL, nil).
+					Once()
+
+				s3.
+					On("UploadPrivateImage", mock.Anything, mock.Anything, "tiger.png", "image/png").
+					Return("tiger.png", nil).
+					Once()
+
+				repo.
+					On("Create", mock.Anything, mock.MatchedBy(func(s *entities.Sighting) bool {
+						return s.ImageHash == hash.String() && s.NeedsReview == tc.flagDuplicate
+					})).
+					Return(nil).
+					Once()
+
+				tigerRepo.
+					On("Update", mock.Anything, mock.Anything, uint(101)).
+					Return(nil).
+					Once()
+
+				repo.
+					On("FindByTigerID", mock.Anything, uint(101), mock.Anything, 1, 1000).
+					Return([]entities.Sighting{}, 0, nil).
+					Maybe()
+			}
+
+			res, err := usecase.CreateSighting(context.Background(), &model.NewSighting{
+				TigerID:   101,
+				Date:      &now,
+				Latitude:  &lat,
+				Longitude: &lng,
+				Image:     testutil.PNGUpload(t, img),
+			}, 201)
 
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.want, res)
-			assert.Equal(t, len(tc.want), count)
 		})
 	}
 }
+
+func TestUsecase_CreateSighting_Exif(t *testing.T) {
+	// testdata/camera-trap.jpg was taken at 7° 33' 2.4" S, 110° 49' 41.94" E on
+	// 2024-03-01 06:30:00 +07:00
+	exifLat := -(7.0 + 33.0/60 + 2.4/3600)
+	exifLng := 110.0 + 49.0/60 + 41.94/3600
+	exifDate := time.Date(2024, 3, 1, 6, 30, 0, 0, time.FixedZone("", 7*3600))
+
+	photo, err := os.ReadFile("testdata/camera-trap.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upload := func() *graphql.Upload {
+		return &graphql.Upload{
+			File:        bytes.NewReader(photo),
+			Filename:    "camera-trap.jpg",
+			Size:        int64(len(photo)),
+			ContentType: "image/jpeg",
+		}
+	}
+
+	nearLat := exifLat - 0.001
+	farLat := exifLat - 0.1
+	nearDate := exifDate.Add(30 * time.Minute)
+	farDate := exifDate.Add(3 * time.Hour)
+
+	testCases := []struct {
+		name string
+
+		input *model.NewSighting
+
+		wantLat         float64
+		wantDate        time.Time
+		wantNeedsReview bool
+		wantErr         error
+	}{
+		{
+			name:     "should take date and position from exif given none are supplied",
+			input:    &model.NewSighting{TigerID: 101, Image: upload()},
+			wantLat:  exifLat,
+			wantDate: exifDate,
+		},
+		{
+			name:     "should keep supplied values given they are close to exif",
+			input:    &model.NewSighting{TigerID: 101, Date: &nearDate, Latitude: &nearLat, Longitude: &exifLng, Image: upload()},
+			wantLat:  nearLat,
+			wantDate: nearDate,
+		},
+		{
+			name:            "should flag for review given supplied position is far from exif",
+			input:           &model.NewSighting{TigerID: 101, Latitude: &farLat, Longitude: &exifLng, Image: upload()},
+			wantLat:         farLat,
+			wantDate:        exifDate,
+			wantNeedsReview: true,
+		},
+		{
+			name:            "should flag for review given supplied date is far from exif",
+			input:           &model.NewSighting{TigerID: 101, Date: &farDate, Image: upload()},
+			wantLat:         exifLat,
+			wantDate:        farDate,
+			wantNeedsReview: true,
+		},
+		{
+			name:    "should return ErrIncompleteSighting given only latitude is supplied",
+			input:   &model.NewSighting{TigerID: 101, Latitude: &nearLat, Image: upload()},
+			wantErr: entities.ErrIncompleteSighting,
+		},
+		{
+			name:    "should return ErrIncompleteSighting given image without exif",
+			input:   &model.NewSighting{TigerID: 101, Date: &nearDate, Image: testutil.PNGUpload(t, gradient())},
+			wantErr: entities.ErrIncompleteSighting,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail, 1)
+
+			policy := NewSightingPolicy(mocks.NewReserveRepository(t), entities.SightingRule{
+				MinDistanceKm:     5,
+				ExifMaxDistanceKm: 1,
+				ExifMaxTimeGap:    time.Hour,
+			})
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), policy, s3, ch)
+
+			if tc.wantErr == nil {
+				tigerRepo.
+					On("FindByID", mock.Anything, uint(101)).
+					Return(&entities.Tiger{
+						Model:         gorm.Model{ID: 101},
+						LastSeen:      exifDate.Add(-48 * time.Hour),
+						LastLatitude:  -7.250676,
+						LastLongitude: 110.828316,
+					}, nil).
+					Once()
+
+				repo.
+					On("FindAdjacent", mock.Anything, uint(101), mock.Anything, uint(0)).
+					Return(nil, nil, nil).
+					Once()
+
+				repo.
+					On("FindDuplicateImage", mock.Anything, mock.Anything, uint(0)).
+					Return(uint(0), nil).
+					Once()
+
+				s3.
+					On("UploadImage", mock.Anything, mock.Anything, "thumb/camera-trap.jpg", "image/jpeg").
+					Return("https://img/thumb/camera-trap.jpg", nil).
+					Once()
+
+				s3.
+					On("UploadImage", mock.Anything, mock.Anything, "medium/camera-trap.jpg", "image/jpeg").
+					Return("https://img/medium/camera-trap.jpg", nil).
+					Once()
+
+				s3.
+					On("UploadPrivateImage", mock.Anything, mock.Anything, "camera-trap.jpg", "image/jpeg").
+					Return("camera-trap.jpg", nil).
+					Once()
+
+				repo.
+					On("Create", mock.Anything, mock.MatchedBy(func(s *entities.Sighting) bool {
+						return *s.ExifLatitude == exifLat && s.ExifDate.Equal(exifDate) &&
+							s.ImageURL == "https://img/thumb/camera-trap.jpg" &&
+							s.MediumImageURL == "https://img/medium/camera-trap.jpg" &&
+							s.OriginalImageKey == "camera-trap.jpg"
+					})).
+					Return(nil).
+					Once()
+
+				tigerRepo.
+					On("Update", mock.Anything, mock.Anything, uint(101)).
+					Return(nil).
+					Once()
+
+				repo.
+					On("FindByTigerID", mock.Anything, uint(101), mock.Anything, 1, 1000).
+					Return([]entities.Sighting{}, 0, nil).
+					Maybe()
+			}
+
+			res, err := usecase.CreateSighting(context.Background(), tc.input, 201)
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				assert.Nil(t, res)
+				return
+			}
+
+			assert.InDelta(t, tc.wantLat, res.Latitude, 1e-9)
+			assert.InDelta(t, exifLng, res.Longitude, 1e-9)
+			assert.True(t, tc.wantDate.Equal(res.Date), "got %v", res.Date)
+			assert.Equal(t, tc.wantNeedsReview, res.NeedsReview)
+		})
+	}
+}
+
+func TestUsecase_ImportSighting(t *testing.T) {
+	now := time.Now()
+	date := now.Add(-72 * time.Hour)
+	lat, lng := -7.550676, 110.828316
+	req := &model.NewSighting{
+		TigerID:   101,
+		Date:      &date,
+		Latitude:  &lat,
+		Longitude: &lng,
+	}
+
+	repo := mocks.NewSightingRepository(t)
+	tigerRepo := mocks.NewTigerRepository(t)
+	userRepo := mocks.NewUserRepository(t)
+
+	// no email is queued for imported sightings, so the queue is never read
+	usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), nil, nil)
+
+	tiger := &entities.Tiger{
+		Model:         gorm.Model{ID: 101},
+		LastSeen:      now,
+		LastLatitude:  -7.050676,
+		LastLongitude: 110.828316,
+	}
+
+	tigerRepo.
+		On("FindByID", mock.Anything, req.TigerID).
+		Return(tiger, nil).
+		Once()
+
+	repo.
+		On("FindAdjacent", mock.Anything, req.TigerID, *req.Date, uint(0)).
+		Return(nil, nil, nil).
+		Once()
+
+	repo.
+		On("Create", mock.Anything, mock.Anything).
+		Return(nil).
+		Once()
+
+	tigerRepo.
+		On("Update", mock.Anything, tiger, req.TigerID).
+		Return(nil).
+		Once()
+
+	res, err := usecase.ImportSighting(context.Background(), req, 201)
+
+	assert.Nil(t, err)
+	assert.Equal(t, *req.Date, res.Date)
+	assert.Equal(t, uint(201), res.UserID)
+	// backdated sightings keep the last known position of the tiger
+	assert.Equal(t, now, tiger.LastSeen)
+}
+
+func TestUsecase_GetSightingByTigerID(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+
+		getSightingsByTigerIDResp []entities.Sighting
+		getSightingsByTigerIDErr  error
+
+		want    []*model.Sighting
+		wantErr error
+	}{
+		{
+			name: "should return valid []*model.Sighting given valid input",
+			getSightingsByTigerIDResp: []entities.Sighting{
+				{
+					Model: gorm.Model{
+						ID:        301,
+						CreatedAt: now,
+						UpdatedAt: now,
+					},
+					Date:      now,
+					Latitude:  -7.550676,
+					Longitude: 110.828316,
+					TigerID:   101,
+					UserID:    201,
+				},
+			},
+			getSightingsByTigerIDErr: nil,
+			want: []*model.Sighting{
+				{
+					ID:        301,
+					Date:      now,
+					Latitude:  -7.550676,
+					Longitude: 110.828316,
+					TigerID:   101,
+					UserID:    201,
+					ImageURL:  new(string),
+				},
+			},
+			wantErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			repo.
+				On("FindByTigerID", mock.Anything, uint(101), mock.Anything, 1, 1000).
+				Return(tc.getSightingsByTigerIDResp, len(tc.getSightingsByTigerIDResp), tc.getSightingsByTigerIDErr).
+				Maybe()
+
+			res, count, err := usecase.GetSightingsByTigerID(context.Background(), 101, 1, 1000)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+			assert.Equal(t, len(tc.want), count)
+		})
+	}
+}
+
+func TestUsecase_GetSightingsInBounds(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+	bounds := entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111}
+
+	testCases := []struct {
+		name string
+
+		bounds entities.Bounds
+		from   *time.Time
+		to     *time.Time
+
+		findResp []entities.Sighting
+		findErr  error
+
+		want    []*model.Sighting
+		wantErr error
+	}{
+		{
+			name:   "should return valid []*model.Sighting given valid bounds",
+			bounds: bounds,
+			from:   &before,
+			to:     &now,
+			findResp: []entities.Sighting{
+				{
+					Model:     gorm.Model{ID: 1},
+					Date:      now,
+					Latitude:  -7.550676,
+					Longitude: 110.828316,
+					TigerID:   101,
+					UserID:    201,
+				},
+			},
+			want: []*model.Sighting{
+				{
+					ID:        1,
+					Date:      now,
+					Latitude:  -7.550676,
+					Longitude: 110.828316,
+					TigerID:   101,
+					UserID:    201,
+					ImageURL:  new(string),
+				},
+			},
+		},
+		{
+			name:    "should return ErrInvalidBounds given minLat greater than maxLat",
+			bounds:  entities.Bounds{MinLat: -7, MinLng: 110, MaxLat: -8, MaxLng: 111},
+			wantErr: entities.ErrInvalidBounds,
+		},
+		{
+			name:    "should return ErrInvalidBounds given longitude out of range",
+			bounds:  entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 181},
+			wantErr: entities.ErrInvalidBounds,
+		},
+		{
+			name:    "should return ErrInvalidDateRange given from after to",
+			bounds:  bounds,
+			from:    &now,
+			to:      &before,
+			wantErr: entities.ErrInvalidDateRange,
+		},
+		{
+			name:    "should return err given failed to find sightings",
+			bounds:  bounds,
+			findErr: errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			repo.
+				On("FindInBounds", mock.Anything, tc.bounds, tc.from, tc.to).
+				Return(tc.findResp, tc.findErr).
+				Maybe()
+
+			res, err := usecase.GetSightingsInBounds(context.Background(), tc.bounds, tc.from, tc.to)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_GetSightingExif(t *testing.T) {
+	lat, lng := -7.550667, 110.828317
+	date := time.Now()
+
+	testCases := []struct {
+		name string
+
+		findResp *entities.Sighting
+		findErr  error
+
+		want    *model.SightingExif
+		wantErr error
+	}{
+		{
+			name: "should return exif data given sighting with exif data",
+			findResp: &entities.Sighting{
+				Model:         gorm.Model{ID: 1},
+				ExifLatitude:  &lat,
+				ExifLongitude: &lng,
+				ExifDate:      &date,
+			},
+			want: &model.SightingExif{Latitude: &lat, Longitude: &lng, Date: &date},
+		},
+		{
+			name:     "should return nil given sighting without exif data",
+			findResp: &entities.Sighting{Model: gorm.Model{ID: 1}},
+		},
+		{
+			name:    "should return ErrSightingNotFound given sighting that does not exist",
+			findErr: gorm.ErrRecordNotFound,
+			wantErr: entities.ErrSightingNotFound,
+		},
+		{
+			name:    "should return err given failed to find sighting",
+			findErr: errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			repo.
+				On("FindByID", mock.Anything, uint(1)).
+				Return(tc.findResp, tc.findErr).
+				Once()
+
+			res, err := usecase.GetSightingExif(context.Background(), 1)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_GetOriginalImageURL(t *testing.T) {
+	url := "https://originals/tiger.png?X-Amz-Signature=abc"
+	reporter := &entities.User{Model: gorm.Model{ID: 201}, Role: entities.RoleUser}
+	admin := &entities.User{Model: gorm.Model{ID: 1}, Role: entities.RoleAdmin}
+	other := &entities.User{Model: gorm.Model{ID: 202}, Role: entities.RoleUser}
+
+	testCases := []struct {
+		name string
+
+		user     *entities.User
+		findResp *entities.Sighting
+		findErr  error
+
+		wantPresign bool
+		want        *string
+		wantErr     error
+	}{
+		{
+			name:        "should return presigned url given user who reported the sighting",
+			user:        reporter,
+			findResp:    &entities.Sighting{Model: gorm.Model{ID: 1}, UserID: 201, OriginalImageKey: "tiger.png"},
+			wantPresign: true,
+			want:        &url,
+		},
+		{
+			name:        "should return presigned url given admin",
+			user:        admin,
+			findResp:    &entities.Sighting{Model: gorm.Model{ID: 1}, UserID: 201, OriginalImageKey: "tiger.png"},
+			wantPresign: true,
+			want:        &url,
+		},
+		{
+			name:     "should return nil given user who did not report the sighting",
+			user:     other,
+			findResp: &entities.Sighting{Model: gorm.Model{ID: 1}, UserID: 201, OriginalImageKey: "tiger.png"},
+		},
+		{
+			name:     "should return nil given sighting without original image",
+			user:     admin,
+			findResp: &entities.Sighting{Model: gorm.Model{ID: 1}, UserID: 201},
+		},
+		{
+			name:    "should return ErrSightingNotFound given sighting that does not exist",
+			user:    admin,
+			findErr: gorm.ErrRecordNotFound,
+			wantErr: entities.ErrSightingNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			repo.
+				On("FindByID", mock.Anything, uint(1)).
+				Return(tc.findResp, tc.findErr).
+				Once()
+
+			if tc.wantPresign {
+				s3.
+					On("PresignPrivateImage", mock.Anything, "tiger.png").
+					Return(url, nil).
+					Once()
+			}
+
+			res, err := usecase.GetOriginalImageURL(context.Background(), 1, tc.user)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_GetSightingClusters(t *testing.T) {
+	now := time.Now()
+	bounds := entities.Bounds{MinLat: -8, MinLng: 110, MaxLat: -7, MaxLng: 111}
+
+	testCases := []struct {
+		name string
+
+		bounds entities.Bounds
+		zoom   int
+
+		wantCellSize float64
+		clusterResp  []entities.SightingCluster
+		clusterErr   error
+
+		want    []*model.SightingCluster
+		wantErr error
+	}{
+		{
+			name:         "should return valid []*model.SightingCluster given valid bounds and zoom",
+			bounds:       bounds,
+			zoom:         3,
+			wantCellSize: 5.625,
+			clusterResp: []entities.SightingCluster{
+				{
+					Count:     2,
+					Latitude:  -7.4,
+					Longitude: 110.7,
+					Latest: entities.Sighting{
+						Model:   gorm.Model{ID: 2},
+						Date:    now,
+						TigerID: 101,
+					},
+				},
+			},
+			want: []*model.SightingCluster{
+				{
+					Count:     2,
+					Latitude:  -7.4,
+					Longitude: 110.7,
+					LastSeen:  now,
+					TigerID:   101,
+				},
+			},
+		},
+		{
+			name:    "should return ErrInvalidBounds given invalid bounds",
+			bounds:  entities.Bounds{MinLat: -91, MinLng: 110, MaxLat: -7, MaxLng: 111},
+			zoom:    3,
+			wantErr: entities.ErrInvalidBounds,
+		},
+		{
+			name:    "should return ErrInvalidZoom given zoom out of range",
+			bounds:  bounds,
+			zoom:    23,
+			wantErr: entities.ErrInvalidZoom,
+		},
+		{
+			name:         "should return err given failed to cluster sightings",
+			bounds:       bounds,
+			zoom:         0,
+			wantCellSize: 45,
+			clusterErr:   errors.New("db error"),
+			wantErr:      errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			repo.
+				On("ClusterInBounds", mock.Anything, tc.bounds, tc.wantCellSize).
+				Return(tc.clusterResp, tc.clusterErr).
+				Maybe()
+
+			res, err := usecase.GetSightingClusters(context.Background(), tc.bounds, tc.zoom)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_UpdateSighting(t *testing.T) {
+	now := time.Now()
+	lat := -7.250676
+
+	existing := func() *entities.Sighting {
+		return &entities.Sighting{
+			Model:     gorm.Model{ID: 301},
+			Date:      now,
+			Latitude:  -7.550676,
+			Longitude: 110.828316,
+			TigerID:   101,
+			UserID:    201,
+		}
+	}
+
+	testCases := []struct {
+		name string
+
+		userID uint
+
+		findByIDResp *entities.Sighting
+		findByIDErr  error
+
+		refreshErrs []error
+
+		want    *model.Sighting
+		wantErr error
+	}{
+		{
+			name:        "should return ErrSightingNotFound given sighting does not exist",
+			userID:      201,
+			findByIDErr: gorm.ErrRecordNotFound,
+			wantErr:     entities.ErrSightingNotFound,
+		},
+		{
+			name:         "should return ErrSightingNotOwned given sighting was reported by another user",
+			userID:       202,
+			findByIDResp: existing(),
+			wantErr:      entities.ErrSightingNotOwned,
+		},
+		{
+			name:         "should update sighting and refresh tiger last seen",
+			userID:       201,
+			findByIDResp: existing(),
+			refreshErrs:  []error{nil},
+			want: &model.Sighting{
+				ID:        301,
+				Date:      now,
+				Latitude:  -7.250676,
+				Longitude: 110.828316,
+				TigerID:   101,
+				UserID:    201,
+			},
+		},
+		{
+			name:         "should retry given the tiger was modified concurrently",
+			userID:       201,
+			findByIDResp: existing(),
+			refreshErrs:  []error{entities.ErrConcurrentModification, nil},
+			want: &model.Sighting{
+				ID:        301,
+				Date:      now,
+				Latitude:  -7.250676,
+				Longitude: 110.828316,
+				TigerID:   101,
+				UserID:    201,
+			},
+		},
+		{
+			name:         "should return ErrConcurrentModification given every attempt lost the race",
+			userID:       201,
+			findByIDResp: existing(),
+			refreshErrs: []error{
+				entities.ErrConcurrentModification,
+				entities.ErrConcurrentModification,
+				entities.ErrConcurrentModification,
+			},
+			wantErr: entities.ErrConcurrentModification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			repo.
+				On("FindByID", mock.Anything, uint(301)).
+				Return(tc.findByIDResp, tc.findByIDErr).
+				Once()
+
+			repo.
+				On("FindAdjacent", mock.Anything, uint(101), now, uint(301)).
+				Return(nil, nil, nil).
+				Maybe()
+
+			repo.
+				On("Update", mock.Anything, mock.Anything, uint(301)).
+				Return(nil).
+				Maybe()
+
+			tigerRepo.
+				On("FindByID", mock.Anything, uint(101)).
+				Return(&entities.Tiger{
+					Model:         gorm.Model{ID: 101},
+					Name:          "tiger-1",
+					LastSeen:      now,
+					LastLatitude:  -7.550676,
+					LastLongitude: 110.828316,
+				}, nil).
+				Maybe()
+
+			for _, err := range tc.refreshErrs {
+				tigerRepo.
+					On("RefreshLastSeen", mock.Anything, mock.MatchedBy(func(t *entities.Tiger) bool {
+						return t.ID == 101
+					})).
+					Return(err).
+					Once()
+			}
+
+			res, err := usecase.UpdateSighting(context.Background(), 301, &model.UpdateSighting{Latitude: &lat}, tc.userID)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestUsecase_DeleteSighting(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		userID uint
+
+		findByIDResp *entities.Sighting
+		findByIDErr  error
+
+		remaining   []entities.Sighting
+		deleteErr   error
+		refreshErrs []error
+
+		wantErr error
+	}{
+		{
+			name:        "should return ErrSightingNotFound given sighting does not exist",
+			userID:      201,
+			findByIDErr: gorm.ErrRecordNotFound,
+			wantErr:     entities.ErrSightingNotFound,
+		},
+		{
+			name:   "should return ErrSightingNotOwned given sighting was reported by another user",
+			userID: 202,
+			findByIDResp: &entities.Sighting{
+				Model:   gorm.Model{ID: 301},
+				TigerID: 101,
+				UserID:  201,
+			},
+			wantErr: entities.ErrSightingNotOwned,
+		},
+		{
+			name:   "should return ErrLastSighting given sighting is the only one left for the tiger",
+			userID: 201,
+			findByIDResp: &entities.Sighting{
+				Model:   gorm.Model{ID: 301},
+				TigerID: 101,
+				UserID:  201,
+			},
+			remaining: []entities.Sighting{
+				{Model: gorm.Model{ID: 301}, TigerID: 101, UserID: 201},
+			},
+			wantErr: entities.ErrLastSighting,
+		},
+		{
+			name:   "should delete sighting and refresh tiger last seen",
+			userID: 201,
+			findByIDResp: &entities.Sighting{
+				Model:   gorm.Model{ID: 301},
+				TigerID: 101,
+				UserID:  201,
+			},
+			remaining: []entities.Sighting{
+				{
+					Model:     gorm.Model{ID: 300},
+					Date:      now.Add(-time.Hour),
+					Latitude:  -7.250676,
+					Longitude: 111.828316,
+					TigerID:   101,
+					UserID:    201,
+				},
+				{Model: gorm.Model{ID: 301}, TigerID: 101, UserID: 201},
+			},
+			refreshErrs: []error{nil},
+			wantErr:     nil,
+		},
+		{
+			name:   "should retry given the tiger was modified concurrently",
+			userID: 201,
+			findByIDResp: &entities.Sighting{
+				Model:   gorm.Model{ID: 301},
+				TigerID: 101,
+				UserID:  201,
+			},
+			remaining: []entities.Sighting{
+				{
+					Model:     gorm.Model{ID: 300},
+					Date:      now.Add(-time.Hour),
+					Latitude:  -7.250676,
+					Longitude: 111.828316,
+					TigerID:   101,
+					UserID:    201,
+				},
+				{Model: gorm.Model{ID: 301}, TigerID: 101, UserID: 201},
+			},
+			refreshErrs: []error{entities.ErrConcurrentModification, nil},
+		},
+		{
+			name:   "should return ErrConcurrentModification given every attempt lost the race",
+			userID: 201,
+			findByIDResp: &entities.Sighting{
+				Model:   gorm.Model{ID: 301},
+				TigerID: 101,
+				UserID:  201,
+			},
+			remaining: []entities.Sighting{
+				{
+					Model:     gorm.Model{ID: 300},
+					Date:      now.Add(-time.Hour),
+					Latitude:  -7.250676,
+					Longitude: 111.828316,
+					TigerID:   101,
+					UserID:    201,
+				},
+				{Model: gorm.Model{ID: 301}, TigerID: 101, UserID: 201},
+			},
+			refreshErrs: []error{
+				entities.ErrConcurrentModification,
+				entities.ErrConcurrentModification,
+				entities.ErrConcurrentModification,
+			},
+			wantErr: entities.ErrConcurrentModification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSightingRepository(t)
+			tigerRepo := mocks.NewTigerRepository(t)
+			userRepo := mocks.NewUserRepository(t)
+
+			s3 := s3mocks.NewS3ClientInterface(t)
+			ch := make(chan email.SightingEmail)
+
+			usecase := NewSightingUsecase(repo, tigerRepo, userRepo, mocks.NewInlineUnitOfWork(t), defaultPolicy(t), s3, ch)
+
+			repo.
+				On("FindByID", mock.Anything, uint(301)).
+				Return(tc.findByIDResp, tc.findByIDErr).
+				Once()
+
+			if len(tc.remaining) > 0 {
+				repo.
+					On("FindByTigerID", mock.Anything, uint(101), mock.Anything, 1, 1).
+					Return(tc.remaining[:1], len(tc.remaining), nil).
+					Once()
+			}
+
+			repo.
+				On("Delete", mock.Anything, uint(301)).
+				Return(tc.deleteErr).
+				Maybe()
+
+			tigerRepo.
+				On("FindByID", mock.Anything, uint(101)).
+				Return(&entities.Tiger{
+					Model:         gorm.Model{ID: 101},
+					Name:          "tiger-1",
+					LastSeen:      now,
+					LastLatitude:  -7.550676,
+					LastLongitude: 110.828316,
+				}, nil).
+				Maybe()
+
+			for _, err := range tc.refreshErrs {
+				tigerRepo.
+					On("RefreshLastSeen", mock.Anything, mock.MatchedBy(func(t *entities.Tiger) bool {
+						return t.ID == 101
+					})).
+					Return(err).
+					Once()
+			}
+
+			err := usecase.DeleteSighting(context.Background(), 301, tc.userID)
+
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+// defaultPolicy applies the default 5 km rule, the tigers under test have no reserve.
+func defaultPolicy(t *testing.T) entities.SightingPolicy {
+	return NewSightingPolicy(mocks.NewReserveRepository(t), entities.SightingRule{MinDistanceKm: 5})
+}
+
+// gradient draws a diagonal gradient with a bright square, something for the image
+// hash to pick up.
+func gradient() image.Image {
+	img := image.NewGray(image.Rect(0, 0, 120, 90))
+	for y := 0; y < 90; y++ {
+		for x := 0; x < 120; x++ {
+			c := uint8(x + y)
+			if x > 30 && x < 60 && y > 20 && y < 50 {
+				c = 250
+			}
+			img.SetGray(x, y, color.Gray{c})
+		}
+	}
+
+	return img
+}
+
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	return geo.NewPoint(lat1, lng1).GreatCircleDistance(geo.NewPoint(lat2, lng2))
+}
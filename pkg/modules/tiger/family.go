@@ -0,0 +1,202 @@
+package tiger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"gorm.io/gorm"
+)
+
+// maxFamilyDepth is the number of generations GetFamily goes up and down at most.
+const maxFamilyDepth = 5
+
+// GetFamily implements entities.TigerUsecase.
+//
+// Parents are always born before their cubs, so walking up or down the tree one
+// generation at a time cannot loop. Relatives reached twice, e.g. through both
+// parents, are only listed once at the closest generation.
+func (u *usecase) GetFamily(ctx context.Context, id uint, depth int) (*model.Family, error) {
+	if depth < 1 || depth > maxFamilyDepth {
+		return nil, entities.ErrInvalidFamilyDepth
+	}
+
+	t, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrTigerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.Family{
+		Ancestors:   []*model.FamilyMember{},
+		Siblings:    []*model.Tiger{},
+		Descendants: []*model.FamilyMember{},
+	}
+
+	seen := map[uint]bool{t.ID: true}
+	generation := []entities.Tiger{*t}
+	for g := 1; g <= depth; g++ {
+		ids := parentIDs(generation, seen)
+		if len(ids) == 0 {
+			break
+		}
+
+		generation, err = u.repo.FindByIDs(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Ancestors = appendMembers(res.Ancestors, generation, g, seen)
+	}
+
+	if ids := parentIDs([]entities.Tiger{*t}, nil); len(ids) > 0 {
+		siblings, err := u.repo.FindChildren(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range siblings {
+			if siblings[i].ID != t.ID {
+				res.Siblings = append(res.Siblings, toModel(&siblings[i]))
+			}
+		}
+	}
+
+	seen = map[uint]bool{t.ID: true}
+	ids := []uint{t.ID}
+	for g := 1; g <= depth; g++ {
+		children, err := u.repo.FindChildren(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		n := len(res.Descendants)
+		res.Descendants = appendMembers(res.Descendants, children, g, seen)
+		if len(res.Descendants) == n {
+			break
+		}
+
+		ids = nil
+		for _, m := range res.Descendants[n:] {
+			ids = append(ids, m.Tiger.ID)
+		}
+	}
+
+	return res, nil
+}
+
+// validateParents makes sure the parents of the tiger exist, fit their role and were
+// born before it. A parent given by an alias id is replaced by the tiger it was merged
+// into. With checkCubs set, the date of birth and sex of the tiger are also checked
+// against its own cubs, for when either of them changes.
+func (u *usecase) validateParents(ctx context.Context, t *entities.Tiger, checkCubs bool) error {
+	parents := []struct {
+		id  **uint
+		sex model.Sex
+	}{
+		{&t.MotherID, model.SexFemale},
+		{&t.FatherID, model.SexMale},
+	}
+
+	for _, p := range parents {
+		if *p.id == nil {
+			continue
+		}
+
+		if t.ID != 0 && **p.id == t.ID {
+			return entities.ErrInvalidParent
+		}
+
+		parent, err := u.repo.FindByID(ctx, **p.id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entities.ErrParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		// an alias of the tiger itself
+		if t.ID != 0 && parent.ID == t.ID {
+			return entities.ErrInvalidParent
+		}
+
+		// family lookups only know canonical ids, so an alias is never stored
+		*p.id = &parent.ID
+
+		if !fitsRole(parent.Sex, p.sex) {
+			return entities.ErrInvalidParent
+		}
+
+		if !parent.DateOfBirth.Before(t.DateOfBirth) {
+			return entities.ErrParentNotOlder
+		}
+	}
+
+	if !checkCubs || t.ID == 0 {
+		return nil
+	}
+
+	cubs, err := u.repo.FindChildren(ctx, []uint{t.ID})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cubs {
+		if c.MotherID != nil && *c.MotherID == t.ID && !fitsRole(t.Sex, model.SexFemale) {
+			return entities.ErrInvalidParent
+		}
+
+		if c.FatherID != nil && *c.FatherID == t.ID && !fitsRole(t.Sex, model.SexMale) {
+			return entities.ErrInvalidParent
+		}
+
+		if !t.DateOfBirth.Before(c.DateOfBirth) {
+			return entities.ErrParentNotOlder
+		}
+	}
+
+	return nil
+}
+
+// fitsRole reports whether a tiger of the given sex can be the parent of the given
+// role. Tigers of unknown sex fit either.
+func fitsRole(sex, role model.Sex) bool {
+	return sex == role || (sex != model.SexMale && sex != model.SexFemale)
+}
+
+// parentIDs returns the ids of the parents of the tigers that are not in seen.
+func parentIDs(tigers []entities.Tiger, seen map[uint]bool) []uint {
+	var res []uint
+	for _, t := range tigers {
+		for _, id := range []*uint{t.MotherID, t.FatherID} {
+			if id != nil && !seen[*id] {
+				res = append(res, *id)
+			}
+		}
+	}
+
+	return res
+}
+
+// appendMembers adds the tigers that are not in seen to members as the given
+// generation and marks them as seen.
+func appendMembers(
+	members []*model.FamilyMember,
+	tigers []entities.Tiger,
+	generation int,
+	seen map[uint]bool,
+) []*model.FamilyMember {
+	for i := range tigers {
+		if seen[tigers[i].ID] {
+			continue
+		}
+
+		seen[tigers[i].ID] = true
+		members = append(members, &model.FamilyMember{Tiger: toModel(&tigers[i]), Generation: generation})
+	}
+
+	return members
+}
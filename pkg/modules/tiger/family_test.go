@@ -0,0 +1,112 @@
+package tiger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	s3mocks "github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_GetFamily(t *testing.T) {
+	id := func(i uint) *uint { return &i }
+
+	grandmother := entities.Tiger{Model: gorm.Model{ID: 10}, Name: "grandmother"}
+	mother := entities.Tiger{Model: gorm.Model{ID: 1}, Name: "mother", MotherID: id(10)}
+	father := entities.Tiger{Model: gorm.Model{ID: 2}, Name: "father"}
+	tiger := entities.Tiger{Model: gorm.Model{ID: 3}, Name: "tiger", MotherID: id(1), FatherID: id(2)}
+	sister := entities.Tiger{Model: gorm.Model{ID: 4}, Name: "sister", MotherID: id(1)}
+	cub := entities.Tiger{Model: gorm.Model{ID: 5}, Name: "cub", MotherID: id(3)}
+
+	testCases := []struct {
+		name string
+
+		depth        int
+		findByIDResp *entities.Tiger
+		findByIDErr  error
+
+		want    *model.Family
+		wantErr error
+	}{
+		{
+			name:         "should return ancestors, siblings and descendants up to depth",
+			depth:        2,
+			findByIDResp: &tiger,
+			want: &model.Family{
+				Ancestors: []*model.FamilyMember{
+					{Tiger: toModel(&mother), Generation: 1},
+					{Tiger: toModel(&father), Generation: 1},
+					{Tiger: toModel(&grandmother), Generation: 2},
+				},
+				Siblings: []*model.Tiger{toModel(&sister)},
+				Descendants: []*model.FamilyMember{
+					{Tiger: toModel(&cub), Generation: 1},
+				},
+			},
+		},
+		{
+			name:         "should return only parents and cubs given depth 1",
+			depth:        1,
+			findByIDResp: &tiger,
+			want: &model.Family{
+				Ancestors: []*model.FamilyMember{
+					{Tiger: toModel(&mother), Generation: 1},
+					{Tiger: toModel(&father), Generation: 1},
+				},
+				Siblings: []*model.Tiger{toModel(&sister)},
+				Descendants: []*model.FamilyMember{
+					{Tiger: toModel(&cub), Generation: 1},
+				},
+			},
+		},
+		{
+			name:    "should return ErrInvalidFamilyDepth given depth 0",
+			depth:   0,
+			wantErr: entities.ErrInvalidFamilyDepth,
+		},
+		{
+			name:    "should return ErrInvalidFamilyDepth given depth above 5",
+			depth:   6,
+			wantErr: entities.ErrInvalidFamilyDepth,
+		},
+		{
+			name:        "should return ErrTigerNotFound given tiger does not exist",
+			depth:       1,
+			findByIDErr: gorm.ErrRecordNotFound,
+			wantErr:     entities.ErrTigerNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindByID", mock.Anything, uint(3)).
+				Return(tc.findByIDResp, tc.findByIDErr).
+				Maybe()
+
+			repo.On("FindByIDs", mock.Anything, []uint{1, 2}).Return([]entities.Tiger{mother, father}, nil).Maybe()
+			repo.On("FindByIDs", mock.Anything, []uint{10}).Return([]entities.Tiger{grandmother}, nil).Maybe()
+			repo.On("FindChildren", mock.Anything, []uint{1, 2}).Return([]entities.Tiger{tiger, sister}, nil).Maybe()
+			repo.On("FindChildren", mock.Anything, []uint{3}).Return([]entities.Tiger{cub}, nil).Maybe()
+			repo.On("FindChildren", mock.Anything, []uint{5}).Return([]entities.Tiger{}, nil).Maybe()
+
+			got, err := uc.GetFamily(context.Background(), 3, tc.depth)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tiger
+
+import (
+	"context"
+	"errors"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"gorm.io/gorm"
+)
+
+// MergeTigers implements entities.TigerUsecase.
+func (u *usecase) MergeTigers(ctx context.Context, sourceID, targetID, userID uint) (*model.Tiger, error) {
+	source, err := u.findTiger(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := u.findTiger(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// either id may be an alias, which resolves to the tiger it was merged into
+	if source.ID == target.ID {
+		return nil, entities.ErrInvalidMerge
+	}
+
+	var merged *entities.Tiger
+	err = u.uow.Do(ctx, func(ctx context.Context) error {
+		err := u.sightingRepo.ReassignTiger(ctx, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+
+		err = u.repo.Merge(ctx, source.ID, target.ID, userID)
+		if err != nil {
+			return err
+		}
+
+		// Merge may have changed the parents of the target, so it is loaded again
+		merged, err = u.repo.FindByID(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+
+		return u.repo.RefreshLastSeen(ctx, merged)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toModel(merged), nil
+}
+
+// GetAliases implements entities.TigerUsecase.
+func (u *usecase) GetAliases(ctx context.Context, id uint) ([]*model.TigerAlias, error) {
+	aliases, err := u.repo.FindAliases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.TigerAlias, len(aliases))
+	for i, a := range aliases {
+		res[i] = &model.TigerAlias{
+			ID:   a.ID,
+			Name: a.Name,
+		}
+
+		if a.MergedAt != nil {
+			res[i].MergedAt = *a.MergedAt
+		}
+
+		if a.MergedByID != nil {
+			res[i].MergedByID = *a.MergedByID
+		}
+	}
+
+	return res, nil
+}
+
+// findTiger returns the tiger with the id, or ErrTigerNotFound if there is none.
+func (u *usecase) findTiger(ctx context.Context, id uint) (*entities.Tiger, error) {
+	t, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrTigerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
@@ -0,0 +1,198 @@
+package tiger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	s3mocks "github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_MergeTigers(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		sourceResp *entities.Tiger
+		sourceErr  error
+		targetResp *entities.Tiger
+		targetErr  error
+		reassign   error
+		refreshErr error
+
+		want    *model.Tiger
+		wantErr error
+	}{
+		{
+			name: "should merge and take the last position from the newest sighting of the merged tiger",
+			sourceResp: &entities.Tiger{
+				Model: gorm.Model{ID: 2},
+				Name:  "tiger-2",
+			},
+			targetResp: &entities.Tiger{
+				Model:         gorm.Model{ID: 1},
+				Name:          "tiger-1",
+				LastSeen:      now.Add(-time.Hour),
+				LastLatitude:  -7.550676,
+				LastLongitude: 110.828316,
+			},
+			want: &model.Tiger{
+				ID:            1,
+				Name:          "tiger-1",
+				LastSeen:      now,
+				LastLatitude:  -7.250676,
+				LastLongitude: 111.828316,
+			},
+		},
+		{
+			name:      "should return ErrTigerNotFound given source does not exist",
+			sourceErr: gorm.ErrRecordNotFound,
+			wantErr:   entities.ErrTigerNotFound,
+		},
+		{
+			name:       "should return ErrTigerNotFound given target does not exist",
+			sourceResp: &entities.Tiger{Model: gorm.Model{ID: 2}},
+			targetErr:  gorm.ErrRecordNotFound,
+			wantErr:    entities.ErrTigerNotFound,
+		},
+		{
+			name:       "should return ErrInvalidMerge given source is an alias of the target",
+			sourceResp: &entities.Tiger{Model: gorm.Model{ID: 1}},
+			targetResp: &entities.Tiger{Model: gorm.Model{ID: 1}},
+			wantErr:    entities.ErrInvalidMerge,
+		},
+		{
+			name:       "should return err given failed to reassign sightings",
+			sourceResp: &entities.Tiger{Model: gorm.Model{ID: 2}},
+			targetResp: &entities.Tiger{Model: gorm.Model{ID: 1}},
+			reassign:   errors.New("db error"),
+			wantErr:    errors.New("db error"),
+		},
+		{
+			name:       "should return err given failed to refresh last seen",
+			sourceResp: &entities.Tiger{Model: gorm.Model{ID: 2}},
+			targetResp: &entities.Tiger{Model: gorm.Model{ID: 1}},
+			refreshErr: entities.ErrConcurrentModification,
+			wantErr:    entities.ErrConcurrentModification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindByID", mock.Anything, uint(2)).
+				Return(tc.sourceResp, tc.sourceErr).
+				Once()
+
+			repo.
+				On("FindByID", mock.Anything, uint(1)).
+				Return(tc.targetResp, tc.targetErr).
+				Maybe()
+
+			sightingRepo.
+				On("ReassignTiger", mock.Anything, uint(2), uint(1)).
+				Return(tc.reassign).
+				Maybe()
+
+			repo.
+				On("Merge", mock.Anything, uint(2), uint(1), uint(7)).
+				Return(nil).
+				Maybe()
+
+			repo.
+				On("RefreshLastSeen", mock.Anything, tc.targetResp).
+				Run(func(args mock.Arguments) {
+					if tc.refreshErr != nil {
+						return
+					}
+
+					// the newest sighting was one of the source
+					t := args.Get(1).(*entities.Tiger)
+					t.LastSeen = now
+					t.LastLatitude = -7.250676
+					t.LastLongitude = 111.828316
+				}).
+				Return(tc.refreshErr).
+				Maybe()
+
+			got, err := uc.MergeTigers(context.Background(), 2, 1, 7)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUsecase_GetAliases(t *testing.T) {
+	now := time.Now()
+	mergedInto, mergedBy := uint(1), uint(7)
+
+	testCases := []struct {
+		name string
+
+		findResp []entities.Tiger
+		findErr  error
+
+		want    []*model.TigerAlias
+		wantErr error
+	}{
+		{
+			name: "should return []*model.TigerAlias and nil error",
+			findResp: []entities.Tiger{
+				{
+					Model:        gorm.Model{ID: 2},
+					Name:         "tiger-2",
+					MergedIntoID: &mergedInto,
+					MergedAt:     &now,
+					MergedByID:   &mergedBy,
+				},
+			},
+			want: []*model.TigerAlias{
+				{ID: 2, Name: "tiger-2", MergedAt: now, MergedByID: 7},
+			},
+		},
+		{
+			name:    "should return err given failed to find aliases",
+			findErr: errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindAliases", mock.Anything, uint(1)).
+				Return(tc.findResp, tc.findErr).
+				Once()
+
+			got, err := uc.GetAliases(context.Background(), 1)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
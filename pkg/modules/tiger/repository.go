@@ -2,7 +2,14 @@ package tiger
 
 import (
 	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
 
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/scopes"
 	"gorm.io/gorm"
@@ -14,7 +21,7 @@ type repo struct {
 
 // Create implements entities.TigerRepository.
 func (r *repo) Create(ctx context.Context, tiger *entities.Tiger) error {
-	err := r.db.WithContext(ctx).Create(tiger).Error
+	err := db.Conn(ctx, r.db).Create(tiger).Error
 	if err != nil {
 		return err
 	}
@@ -23,13 +30,29 @@ func (r *repo) Create(ctx context.Context, tiger *entities.Tiger) error {
 }
 
 // FindAll implements entities.TigerRepository.
-func (r *repo) FindAll(ctx context.Context, page, pageSize int) ([]entities.Tiger, int, error) {
+func (r *repo) FindAll(
+	ctx context.Context,
+	filter *model.TigerFilter,
+	page, pageSize int,
+) ([]entities.Tiger, int, error) {
 	var res []entities.Tiger
 	var count int64
 
-	q := r.db.
-		WithContext(ctx).
-		Model(&entities.Tiger{})
+	q := db.Conn(ctx, r.db).
+		Model(&entities.Tiger{}).
+		Where("merged_into_id IS NULL")
+
+	if filter != nil {
+		if filter.Sex != nil {
+			q = q.Where("sex = ?", *filter.Sex)
+		}
+		if filter.Subspecies != nil {
+			q = q.Where("LOWER(subspecies) = LOWER(?)", *filter.Subspecies)
+		}
+		if len(filter.Status) > 0 {
+			q = q.Where("status IN ?", filter.Status)
+		}
+	}
 
 	err := q.Count(&count).Error
 	if err != nil {
@@ -48,31 +71,379 @@ func (r *repo) FindAll(ctx context.Context, page, pageSize int) ([]entities.Tige
 	return res, int(count), nil
 }
 
+// kmPerDegree is the length of one degree of latitude, used for the bounding box.
+const kmPerDegree = 111.32
+
+// FindNear implements entities.TigerRepository.
+//
+// Candidates are prefiltered in SQL with a bounding box around the point, then the
+// exact great-circle distance is used to filter, sort and paginate them.
+func (r *repo) FindNear(
+	ctx context.Context,
+	latitude, longitude, radiusKm float64,
+	page, pageSize int,
+) ([]entities.NearbyTiger, int, error) {
+	var candidates []entities.Tiger
+
+	q := db.Conn(ctx, r.db).
+		Model(&entities.Tiger{}).
+		Where("merged_into_id IS NULL")
+
+	latDelta := radiusKm / kmPerDegree
+	minLat, maxLat := latitude-latDelta, latitude+latDelta
+	q = q.Where("last_latitude BETWEEN ? AND ?", minLat, maxLat)
+
+	// near the poles every longitude is within the radius
+	if minLat > -90 && maxLat < 90 {
+		lngDelta := radiusKm / (kmPerDegree * math.Cos(latitude*math.Pi/180))
+		minLng, maxLng := longitude-lngDelta, longitude+lngDelta
+
+		switch {
+		case lngDelta >= 180:
+			// the radius wraps around the whole parallel
+		case minLng < -180:
+			q = q.Where("last_longitude >= ? OR last_longitude <= ?", minLng+360, maxLng)
+		case maxLng > 180:
+			q = q.Where("last_longitude >= ? OR last_longitude <= ?", minLng, maxLng-360)
+		default:
+			q = q.Where("last_longitude BETWEEN ? AND ?", minLng, maxLng)
+		}
+	}
+
+	err := q.Find(&candidates).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	p := geo.NewPoint(latitude, longitude)
+
+	res := []entities.NearbyTiger{}
+	for _, t := range candidates {
+		d := p.GreatCircleDistance(geo.NewPoint(t.LastLatitude, t.LastLongitude))
+		if d <= radiusKm {
+			res = append(res, entities.NearbyTiger{Tiger: t, DistanceKm: d})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].DistanceKm < res[j].DistanceKm
+	})
+
+	offset, limit := scopes.PageBounds(page, pageSize)
+	count := len(res)
+	if offset > count {
+		offset = count
+	}
+	if offset+limit > count {
+		limit = count - offset
+	}
+
+	return res[offset : offset+limit], count, nil
+}
+
 // FindByID implements entities.TigerRepository.
+//
+// Merge moves the aliases of a merged tiger along, so an alias always points to a
+// tiger that is not merged itself.
 func (r *repo) FindByID(ctx context.Context, id uint) (*entities.Tiger, error) {
 	var res entities.Tiger
-	err := r.db.WithContext(ctx).First(&res, id).Error
+	err := db.Conn(ctx, r.db).First(&res, id).Error
 	if err != nil {
 		return nil, err
 	}
 
+	if res.MergedIntoID != nil {
+		return r.FindByID(ctx, *res.MergedIntoID)
+	}
+
 	return &res, nil
 }
 
+// ResolveID implements entities.TigerRepository.
+func (r *repo) ResolveID(ctx context.Context, id uint) (uint, error) {
+	var res entities.Tiger
+	err := db.Conn(ctx, r.db).Unscoped().Select("id", "merged_into_id").First(&res, id).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if res.MergedIntoID != nil {
+		return r.ResolveID(ctx, *res.MergedIntoID)
+	}
+
+	return res.ID, nil
+}
+
+// FindByIDs implements entities.TigerRepository.
+func (r *repo) FindByIDs(ctx context.Context, ids []uint) ([]entities.Tiger, error) {
+	var res []entities.Tiger
+	err := db.Conn(ctx, r.db).
+		Where("id IN ?", ids).
+		Order("date_of_birth ASC, id ASC").
+		Find(&res).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindChildren implements entities.TigerRepository.
+func (r *repo) FindChildren(ctx context.Context, parentIDs []uint) ([]entities.Tiger, error) {
+	var res []entities.Tiger
+	err := db.Conn(ctx, r.db).
+		Where("mother_id IN ? OR father_id IN ?", parentIDs, parentIDs).
+		Order("date_of_birth ASC, id ASC").
+		Find(&res).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // Update implements entities.TigerRepository.
+//
+// The update only goes through when the stored version still matches tiger.Version,
+// otherwise entities.ErrConcurrentModification is returned and tiger is left as is.
 func (r *repo) Update(ctx context.Context, tiger *entities.Tiger, id uint) error {
 	if tiger.ID == 0 {
 		tiger.ID = id
 	}
 
-	err := r.db.WithContext(ctx).Save(tiger).Error
+	version := tiger.Version
+	tiger.Version++
+
+	res := db.Conn(ctx, r.db).
+		Model(tiger).
+		Where("version = ?", version).
+		Select("*").
+		Updates(tiger)
+	if res.Error != nil {
+		tiger.Version = version
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		tiger.Version = version
+		return entities.ErrConcurrentModification
+	}
+
+	return nil
+}
+
+// Delete implements entities.TigerRepository.
+//
+// The tiger and its sightings are soft-deleted with the same timestamp, so Restore
+// can tell them apart from sightings that were deleted on their own.
+func (r *repo) Delete(ctx context.Context, id uint) error {
+	return db.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var t entities.Tiger
+		err := tx.First(&t, id).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		err = tx.Model(&entities.Sighting{}).
+			Where("tiger_id = ?", id).
+			Update("deleted_at", now).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&t).Update("deleted_at", now).Error
+	})
+}
+
+// Restore implements entities.TigerRepository.
+func (r *repo) Restore(ctx context.Context, id uint) error {
+	return db.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var t entities.Tiger
+		err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&t, id).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().
+			Model(&entities.Sighting{}).
+			Where("tiger_id = ? AND deleted_at = ?", id, t.DeletedAt).
+			Update("deleted_at", nil).
+			Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&t).Update("deleted_at", nil).Error
+	})
+}
+
+// Merge implements entities.TigerRepository.
+//
+// A target descending from the source loses that parent, as it would otherwise become
+// its own parent, and takes over the parents of the source it has no parent for.
+func (r *repo) Merge(ctx context.Context, sourceID, targetID, userID uint) error {
+	return db.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var source, target entities.Tiger
+		err := tx.First(&source, sourceID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.First(&target, targetID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entities.Tiger{}).
+			Where("id = ?", sourceID).
+			Updates(map[string]interface{}{
+				"merged_into_id": targetID,
+				"merged_at":      time.Now(),
+				"merged_by_id":   userID,
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		for _, p := range []struct {
+			column            string
+			target, inherited *uint
+		}{
+			{"mother_id", target.MotherID, source.MotherID},
+			{"father_id", target.FatherID, source.FatherID},
+		} {
+			if p.target != nil && *p.target != sourceID {
+				continue
+			}
+
+			var parent *uint
+			if p.inherited != nil && *p.inherited != targetID {
+				parent = p.inherited
+			}
+
+			err = tx.Model(&entities.Tiger{}).
+				Where("id = ?", targetID).
+				Update(p.column, parent).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, column := range []string{"merged_into_id", "mother_id", "father_id"} {
+			err = tx.Unscoped().
+				Model(&entities.Tiger{}).
+				Where(column+" = ? AND id <> ?", sourceID, targetID).
+				Update(column, targetID).
+				Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// RefreshLastSeen implements entities.TigerRepository.
+func (r *repo) RefreshLastSeen(ctx context.Context, tiger *entities.Tiger) error {
+	var latest entities.Sighting
+	err := db.Conn(ctx, r.db).
+		Where("tiger_id = ?", tiger.ID).
+		Order("date DESC, id DESC").
+		First(&latest).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
+	res := db.Conn(ctx, r.db).
+		Model(&entities.Tiger{}).
+		Where("id = ? AND version = ?", tiger.ID, tiger.Version).
+		Updates(map[string]interface{}{
+			"last_seen":      latest.Date,
+			"last_latitude":  latest.Latitude,
+			"last_longitude": latest.Longitude,
+			"version":        tiger.Version + 1,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return entities.ErrConcurrentModification
+	}
+
+	tiger.LastSeen = latest.Date
+	tiger.LastLatitude = latest.Latitude
+	tiger.LastLongitude = latest.Longitude
+	tiger.Version++
+
 	return nil
 }
 
+// FindAliases implements entities.TigerRepository.
+func (r *repo) FindAliases(ctx context.Context, tigerID uint) ([]entities.Tiger, error) {
+	var res []entities.Tiger
+	err := db.Conn(ctx, r.db).
+		Where("merged_into_id = ?", tigerID).
+		Order("merged_at ASC, id ASC").
+		Find(&res).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// FindNames implements entities.TigerRepository.
+//
+// Names are matched in Go rather than with a full-text index, as typos have to be
+// tolerated and the number of tigers stays small.
+func (r *repo) FindNames(ctx context.Context) ([]entities.TigerName, error) {
+	var res []entities.TigerName
+	err := db.Conn(ctx, r.db).
+		Model(&entities.Tiger{}).
+		Select("COALESCE(merged_into_id, id) AS tiger_id, name, merged_into_id IS NOT NULL AS alias").
+		Scan(&res).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CreateStatusChange implements entities.TigerRepository.
+func (r *repo) CreateStatusChange(ctx context.Context, change *entities.TigerStatusChange) error {
+	return db.Conn(ctx, r.db).Create(change).Error
+}
+
+// FindStatusHistory implements entities.TigerRepository.
+func (r *repo) FindStatusHistory(ctx context.Context, tigerID uint) ([]entities.TigerStatusChange, error) {
+	var res []entities.TigerStatusChange
+	err := db.Conn(ctx, r.db).
+		Where("tiger_id = ?", tigerID).
+		Order("date ASC, id ASC").
+		Find(&res).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func NewTigerRepository(db *gorm.DB) entities.TigerRepository {
 	return &repo{db}
 }
@@ -117,10 +117,12 @@ func TestRepository_FindByID(t *testing.T) {
 
 func TestRepository_FindAll(t *testing.T) {
 	now := time.Now()
+	female, male, bengal := model.SexFemale, model.SexMale, "bengal"
 
 	testCases := []struct {
 		name string
 
+		filter   *model.TigerFilter
 		page     int
 		pageSize int
 		want     []*model.Tiger
@@ -142,6 +144,43 @@ func TestRepository_FindAll(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "should retrieve tigers matching every field of the filter",
+			filter: &model.TigerFilter{
+				Sex:        &female,
+				Subspecies: &bengal,
+				Status:     []model.TigerStatus{model.TigerStatusMissing, model.TigerStatusActive},
+			},
+			page:     1,
+			pageSize: 10,
+			want: []*model.Tiger{
+				{
+					ID:            1,
+					Name:          "tiger-1",
+					DateOfBirth:   now,
+					LastSeen:      now,
+					LastLatitude:  -7.550676,
+					LastLongitude: 110.828316,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "should not retrieve tigers of another sex",
+			filter:   &model.TigerFilter{Sex: &male},
+			page:     1,
+			pageSize: 10,
+			want:     []*model.Tiger{},
+			wantErr:  nil,
+		},
+		{
+			name:     "should not retrieve tigers of another status",
+			filter:   &model.TigerFilter{Status: []model.TigerStatus{model.TigerStatusDeceased}},
+			page:     1,
+			pageSize: 10,
+			want:     []*model.Tiger{},
+			wantErr:  nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -151,9 +190,11 @@ func TestRepository_FindAll(t *testing.T) {
 
 			r := NewTigerRepository(d)
 
-			got, _, err := r.FindAll(context.Background(), tc.page, tc.pageSize)
+			got, count, err := r.FindAll(context.Background(), tc.filter, tc.page, tc.pageSize)
 
 			assert.Equal(t, tc.wantErr, err)
+			assert.Len(t, got, len(tc.want))
+			assert.Equal(t, len(tc.want), count)
 
 			for i, want := range tc.want {
 				assert.Equal(t, want.ID, got[i].ID)
@@ -167,6 +208,110 @@ func TestRepository_FindAll(t *testing.T) {
 	}
 }
 
+func TestRepository_FindNear(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		latitude  float64
+		longitude float64
+		radiusKm  float64
+		page      int
+		pageSize  int
+
+		wantNames []string
+		wantCount int
+	}{
+		{
+			name:      "should return tigers within radius sorted by distance",
+			latitude:  -7.550676,
+			longitude: 110.828316,
+			radiusKm:  50,
+			page:      1,
+			pageSize:  10,
+			wantNames: []string{"tiger-1", "tiger-2"},
+			wantCount: 2,
+		},
+		{
+			name:      "should paginate tigers within radius",
+			latitude:  -7.550676,
+			longitude: 110.828316,
+			radiusKm:  50,
+			page:      2,
+			pageSize:  1,
+			wantNames: []string{"tiger-2"},
+			wantCount: 2,
+		},
+		{
+			name:      "should return empty page given page beyond the results",
+			latitude:  -7.550676,
+			longitude: 110.828316,
+			radiusKm:  50,
+			page:      3,
+			pageSize:  10,
+			wantNames: []string{},
+			wantCount: 2,
+		},
+		{
+			name:      "should exclude tigers inside the bounding box but outside the radius",
+			latitude:  0,
+			longitude: 0,
+			radiusKm:  100,
+			page:      1,
+			pageSize:  10,
+			wantNames: []string{},
+			wantCount: 0,
+		},
+		{
+			name:      "should return tigers across the antimeridian",
+			latitude:  0,
+			longitude: -179.9,
+			radiusKm:  50,
+			page:      1,
+			pageSize:  10,
+			wantNames: []string{"tiger-4"},
+			wantCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			for _, tg := range []entities.Tiger{
+				// ~33 km north of tiger-1
+				{Name: "tiger-2", LastLatitude: -7.250676, LastLongitude: 110.828316},
+				// in the corner of the bounding box of (0, 0), ~126 km away
+				{Name: "tiger-3", LastLatitude: 0.8, LastLongitude: 0.8},
+				{Name: "tiger-4", LastLatitude: 0.1, LastLongitude: 179.9},
+			} {
+				tg := tg
+				err := d.Create(&tg).Error
+				assert.Nil(t, err)
+			}
+
+			r := NewTigerRepository(d)
+
+			got, count, err := r.FindNear(context.Background(), tc.latitude, tc.longitude, tc.radiusKm, tc.page, tc.pageSize)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.wantCount, count)
+
+			names := []string{}
+			for i, g := range got {
+				names = append(names, g.Name)
+				if i > 0 {
+					assert.LessOrEqual(t, got[i-1].DistanceKm, g.DistanceKm)
+				}
+				assert.LessOrEqual(t, g.DistanceKm, tc.radiusKm)
+			}
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+}
+
 func TestRepository_Update(t *testing.T) {
 	now := time.Now()
 
@@ -198,6 +343,27 @@ func TestRepository_Update(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "should return ErrConcurrentModification given stale version",
+			tiger: &entities.Tiger{
+				Name:          "tiger-1-updated",
+				DateOfBirth:   now,
+				LastSeen:      now,
+				LastLatitude:  -7.550676,
+				LastLongitude: 110.828316,
+				Version:       3,
+			},
+			id: 1,
+			want: &model.Tiger{
+				ID:            1,
+				Name:          "tiger-1",
+				DateOfBirth:   now,
+				LastSeen:      now,
+				LastLatitude:  -7.550676,
+				LastLongitude: 110.828316,
+			},
+			wantErr: entities.ErrConcurrentModification,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -225,8 +391,505 @@ func TestRepository_Update(t *testing.T) {
 	}
 }
 
+func TestRepository_Delete(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		id      uint
+		wantErr error
+	}{
+		{
+			name:    "should soft-delete tiger with id 1 and its sightings",
+			id:      1,
+			wantErr: nil,
+		},
+		{
+			name:    "should return gorm.ErrRecordNotFound given tiger does not exist",
+			id:      2,
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			r := NewTigerRepository(d)
+
+			err := r.Delete(context.Background(), tc.id)
+
+			assert.Equal(t, tc.wantErr, err)
+
+			if tc.wantErr == nil {
+				_, err := r.FindByID(context.Background(), tc.id)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+				var count int64
+				d.Model(&entities.Sighting{}).Where("tiger_id = ?", tc.id).Count(&count)
+				assert.Equal(t, int64(0), count)
+
+				d.Unscoped().Model(&entities.Sighting{}).Where("tiger_id = ?", tc.id).Count(&count)
+				assert.Equal(t, int64(1), count)
+			}
+		})
+	}
+}
+
+func TestRepository_ResolveID(t *testing.T) {
+	now := time.Now()
+	d := db.GetTestDB()
+	SeedDb(d, now)
+
+	r := NewTigerRepository(d)
+
+	for _, name := range []string{"tiger-2", "tiger-3"} {
+		err := r.Create(context.Background(), &entities.Tiger{Name: name, DateOfBirth: now, LastSeen: now})
+		assert.NoError(t, err)
+	}
+
+	err := r.Merge(context.Background(), 3, 2, 1)
+	assert.NoError(t, err)
+
+	err = r.Merge(context.Background(), 2, 1, 1)
+	assert.NoError(t, err)
+
+	err = r.Delete(context.Background(), 1)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		id      uint
+		want    uint
+		wantErr error
+	}{
+		{name: "should return the id of a deleted tiger", id: 1, want: 1},
+		{name: "should return the tiger an alias was merged into", id: 2, want: 1},
+		{name: "should follow aliases of aliases", id: 3, want: 1},
+		{name: "should return gorm.ErrRecordNotFound given tiger does not exist", id: 99, wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := r.ResolveID(context.Background(), tc.id)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRepository_Restore(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		id        uint
+		deleteErr bool
+		wantErr   error
+	}{
+		{
+			name:    "should restore tiger with id 1 and the sightings deleted with it",
+			id:      1,
+			wantErr: nil,
+		},
+		{
+			name:      "should return gorm.ErrRecordNotFound given tiger is not deleted",
+			id:        1,
+			deleteErr: true,
+			wantErr:   gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			// a sighting deleted on its own should stay deleted after restore
+			err := d.Create(&entities.Sighting{
+				Model:     gorm.Model{DeletedAt: gorm.DeletedAt{Time: now.Add(-time.Hour), Valid: true}},
+				Date:      now,
+				Latitude:  -7.250676,
+				Longitude: 110.828316,
+				TigerID:   1,
+				UserID:    1,
+			}).Error
+			assert.NoError(t, err)
+
+			r := NewTigerRepository(d)
+
+			if !tc.deleteErr {
+				err = r.Delete(context.Background(), tc.id)
+				assert.NoError(t, err)
+			}
+
+			err = r.Restore(context.Background(), tc.id)
+
+			assert.Equal(t, tc.wantErr, err)
+
+			if tc.wantErr == nil {
+				got, err := r.FindByID(context.Background(), tc.id)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.id, got.ID)
+
+				var count int64
+				d.Model(&entities.Sighting{}).Where("tiger_id = ?", tc.id).Count(&count)
+				assert.Equal(t, int64(1), count)
+			}
+		})
+	}
+}
+
+func TestRepository_FindChildren(t *testing.T) {
+	now := time.Now()
+	motherID, fatherID := uint(1), uint(2)
+
+	testCases := []struct {
+		name string
+
+		parentIDs []uint
+		wantNames []string
+	}{
+		{
+			name:      "should retrieve the cubs of the mother, oldest first",
+			parentIDs: []uint{motherID},
+			wantNames: []string{"cub-1", "cub-2"},
+		},
+		{
+			name:      "should retrieve the cubs of either parent once",
+			parentIDs: []uint{motherID, fatherID},
+			wantNames: []string{"cub-1", "cub-2", "cub-3"},
+		},
+		{
+			name:      "should retrieve no cubs given tiger without cubs",
+			parentIDs: []uint{3},
+			wantNames: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			r := NewTigerRepository(d)
+
+			for _, cub := range []entities.Tiger{
+				{Name: "father", DateOfBirth: now.AddDate(-5, 0, 0)},
+				{Name: "cub-2", DateOfBirth: now.AddDate(-1, 0, 0), MotherID: &motherID, FatherID: &fatherID},
+				{Name: "cub-1", DateOfBirth: now.AddDate(-2, 0, 0), MotherID: &motherID},
+				{Name: "cub-3", DateOfBirth: now, FatherID: &fatherID},
+			} {
+				err := r.Create(context.Background(), &cub)
+				assert.NoError(t, err)
+			}
+
+			got, err := r.FindChildren(context.Background(), tc.parentIDs)
+			assert.NoError(t, err)
+
+			names := []string{}
+			for _, c := range got {
+				names = append(names, c.Name)
+			}
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+}
+
+func TestRepository_Merge(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		lookupID uint
+		wantID   uint
+	}{
+		{
+			name:     "should find the target given the id of the source",
+			lookupID: 2,
+			wantID:   1,
+		},
+		{
+			name:     "should find the target given the id of an alias of the source",
+			lookupID: 3,
+			wantID:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			r := NewTigerRepository(d)
+
+			sourceID := uint(2)
+			for _, tiger := range []entities.Tiger{
+				{Name: "tiger-2", DateOfBirth: now, LastSeen: now},
+				{Name: "tiger-3", DateOfBirth: now, LastSeen: now},
+				{Name: "cub", DateOfBirth: now, LastSeen: now, MotherID: &sourceID},
+			} {
+				err := r.Create(context.Background(), &tiger)
+				assert.NoError(t, err)
+			}
+
+			err := r.Merge(context.Background(), 3, 2, 7)
+			assert.NoError(t, err)
+
+			err = r.Merge(context.Background(), 2, 1, 7)
+			assert.NoError(t, err)
+
+			got, err := r.FindByID(context.Background(), tc.lookupID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantID, got.ID)
+
+			aliases, err := r.FindAliases(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Len(t, aliases, 2)
+			assert.Equal(t, uint(7), *aliases[0].MergedByID)
+
+			cubs, err := r.FindChildren(context.Background(), []uint{1})
+			assert.NoError(t, err)
+			assert.Len(t, cubs, 1)
+
+			all, count, err := r.FindAll(context.Background(), nil, 1, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, count)
+			assert.Len(t, all, 2)
+		})
+	}
+}
+
+func TestRepository_Merge_Parents(t *testing.T) {
+	now := time.Now()
+	grandmotherID, fatherID, sourceID, otherMotherID := uint(2), uint(3), uint(4), uint(1)
+
+	testCases := []struct {
+		name string
+
+		sourceMother *uint
+		targetMother *uint
+
+		wantMother *uint
+		wantFather *uint
+	}{
+		{
+			name:         "should give the target the parents of the source given the source is its mother",
+			sourceMother: &grandmotherID,
+			targetMother: &sourceID,
+			wantMother:   &grandmotherID,
+			wantFather:   &fatherID,
+		},
+		{
+			name:         "should clear the mother of the target given the source is its mother and has none",
+			targetMother: &sourceID,
+			wantFather:   &fatherID,
+		},
+		{
+			name:         "should keep the mother of the target given it is another tiger",
+			sourceMother: &grandmotherID,
+			targetMother: &otherMotherID,
+			wantMother:   &otherMotherID,
+			wantFather:   &fatherID,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			r := NewTigerRepository(d)
+
+			for _, tiger := range []entities.Tiger{
+				{Name: "grandmother", DateOfBirth: now, LastSeen: now, Sex: model.SexFemale},
+				{Name: "father", DateOfBirth: now, LastSeen: now, Sex: model.SexMale},
+				{Name: "source", DateOfBirth: now, LastSeen: now, Sex: model.SexFemale, MotherID: tc.sourceMother, FatherID: &fatherID},
+				{Name: "target", DateOfBirth: now, LastSeen: now, Sex: model.SexFemale, MotherID: tc.targetMother},
+			} {
+				err := r.Create(context.Background(), &tiger)
+				assert.NoError(t, err)
+			}
+
+			err := r.Merge(context.Background(), sourceID, 5, 7)
+			assert.NoError(t, err)
+
+			got, err := r.FindByID(context.Background(), 5)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantMother, got.MotherID)
+			assert.Equal(t, tc.wantFather, got.FatherID)
+		})
+	}
+}
+
+func TestRepository_RefreshLastSeen(t *testing.T) {
+	now := time.Now().UTC()
+
+	testCases := []struct {
+		name string
+
+		staleVersion bool
+
+		wantErr error
+	}{
+		{
+			name: "should take the last seen date and position from the newest sighting",
+		},
+		{
+			name:         "should return ErrConcurrentModification given the tiger was updated since it was loaded",
+			staleVersion: true,
+			wantErr:      entities.ErrConcurrentModification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			r := NewTigerRepository(d)
+
+			for _, s := range []entities.Sighting{
+				{Date: now.Add(-time.Hour), Latitude: -7.1, Longitude: 110.1, TigerID: 1, UserID: 1},
+				{Date: now.Add(time.Hour), Latitude: -7.2, Longitude: 110.2, TigerID: 1, UserID: 1},
+			} {
+				err := d.Create(&s).Error
+				assert.NoError(t, err)
+			}
+
+			tiger, err := r.FindByID(context.Background(), 1)
+			assert.NoError(t, err)
+
+			if tc.staleVersion {
+				err = r.Update(context.Background(), &entities.Tiger{Model: tiger.Model, Name: "tiger-1", Version: tiger.Version}, 1)
+				assert.NoError(t, err)
+			}
+
+			err = r.RefreshLastSeen(context.Background(), tiger)
+			assert.Equal(t, tc.wantErr, err)
+
+			got, err := r.FindByID(context.Background(), 1)
+			assert.NoError(t, err)
+
+			if tc.wantErr != nil {
+				assert.Equal(t, "tiger-1", got.Name)
+				return
+			}
+
+			assert.True(t, now.Add(time.Hour).Equal(got.LastSeen), "got %v", got.LastSeen)
+			assert.Equal(t, -7.2, got.LastLatitude)
+			assert.Equal(t, 110.2, got.LastLongitude)
+			assert.Equal(t, tiger.Version, got.Version)
+			assert.Equal(t, model.SexFemale, got.Sex)
+		})
+	}
+}
+
+func TestRepository_FindNames(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		merge bool
+		want  []entities.TigerName
+	}{
+		{
+			name: "should retrieve the names of all tigers",
+			want: []entities.TigerName{
+				{TigerID: 1, Name: "tiger-1"},
+				{TigerID: 2, Name: "tiger-2"},
+			},
+		},
+		{
+			name:  "should retrieve merged tigers as aliases of the tiger they were merged into",
+			merge: true,
+			want: []entities.TigerName{
+				{TigerID: 1, Name: "tiger-1"},
+				{TigerID: 1, Name: "tiger-2", Alias: true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			r := NewTigerRepository(d)
+
+			err := r.Create(context.Background(), &entities.Tiger{Name: "tiger-2", DateOfBirth: now, LastSeen: now})
+			assert.NoError(t, err)
+
+			if tc.merge {
+				err = r.Merge(context.Background(), 2, 1, 1)
+				assert.NoError(t, err)
+			}
+
+			got, err := r.FindNames(context.Background())
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tc.want, got)
+		})
+	}
+}
+
+func TestRepository_FindStatusHistory(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name string
+
+		tigerID   uint
+		wantNotes []string
+	}{
+		{
+			name:      "should retrieve the status changes of the tiger ordered by date",
+			tigerID:   1,
+			wantNotes: []string{"not seen for months", "found again"},
+		},
+		{
+			name:      "should retrieve no status changes given tiger without changes",
+			tigerID:   2,
+			wantNotes: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := db.GetTestDB()
+			SeedDb(d, now)
+
+			r := NewTigerRepository(d)
+
+			// recorded in reverse, the history follows the date the changes took effect
+			for _, c := range []entities.TigerStatusChange{
+				{TigerID: 1, PreviousStatus: model.TigerStatusMissing, Status: model.TigerStatusActive, Date: now, Note: "found again"},
+				{TigerID: 1, PreviousStatus: model.TigerStatusActive, Status: model.TigerStatusMissing, Date: now.Add(-24 * time.Hour), Note: "not seen for months"},
+			} {
+				err := r.CreateStatusChange(context.Background(), &c)
+				assert.NoError(t, err)
+			}
+
+			got, err := r.FindStatusHistory(context.Background(), tc.tigerID)
+			assert.NoError(t, err)
+
+			notes := []string{}
+			for _, c := range got {
+				notes = append(notes, c.Note)
+			}
+			assert.Equal(t, tc.wantNotes, notes)
+		})
+	}
+}
+
 func SeedDb(d *gorm.DB, now time.Time) {
-	err := d.AutoMigrate(&entities.Tiger{}, &entities.Sighting{}, &entities.User{})
+	err := d.AutoMigrate(&entities.Tiger{}, &entities.TigerStatusChange{}, &entities.Sighting{}, &entities.User{})
 	if err != nil {
 		panic(err)
 	}
@@ -234,6 +897,8 @@ func SeedDb(d *gorm.DB, now time.Time) {
 	err = d.Create(&entities.Tiger{
 		Name:          "tiger-1",
 		DateOfBirth:   now,
+		Sex:           model.SexFemale,
+		Subspecies:    "Bengal",
 		LastSeen:      now,
 		LastLatitude:  -7.550676,
 		LastLongitude: 110.828316,
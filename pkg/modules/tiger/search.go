@@ -0,0 +1,110 @@
+package tiger
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/fuzzy"
+)
+
+// maxSearchLimit is the number of tigers SearchTigers returns at most.
+const maxSearchLimit = 50
+
+type searchMatch struct {
+	entities.TigerName
+	score int
+}
+
+// SearchTigers implements entities.TigerUsecase.
+//
+// Every tiger is listed once with the best of its names, its own name winning over
+// an alias that matches as well.
+func (u *usecase) SearchTigers(ctx context.Context, query string, limit int) ([]*model.TigerSearchResult, error) {
+	if strings.TrimSpace(query) == "" || limit < 1 || limit > maxSearchLimit {
+		return nil, entities.ErrInvalidSearchQuery
+	}
+
+	names, err := u.repo.FindNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	best := map[uint]searchMatch{}
+	for _, n := range names {
+		score, ok := fuzzy.Match(query, n.Name)
+		if !ok {
+			continue
+		}
+
+		m := searchMatch{n, score}
+		if b, found := best[n.TigerID]; !found || m.better(b) {
+			best[n.TigerID] = m
+		}
+	}
+
+	matches := make([]searchMatch, 0, len(best))
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		if matches[i].Name != matches[j].Name {
+			return matches[i].Name < matches[j].Name
+		}
+		return matches[i].TigerID < matches[j].TigerID
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	res := []*model.TigerSearchResult{}
+	if len(matches) == 0 {
+		return res, nil
+	}
+
+	ids := make([]uint, len(matches))
+	for i, m := range matches {
+		ids[i] = m.TigerID
+	}
+
+	tigers, err := u.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*entities.Tiger, len(tigers))
+	for i := range tigers {
+		byID[tigers[i].ID] = &tigers[i]
+	}
+
+	for _, m := range matches {
+		// aliases of a deleted tiger are still listed by FindNames
+		t, ok := byID[m.TigerID]
+		if !ok {
+			continue
+		}
+
+		res = append(res, &model.TigerSearchResult{
+			Tiger:       toModel(t),
+			MatchedName: m.Name,
+			Alias:       m.Alias,
+		})
+	}
+
+	return res, nil
+}
+
+func (m searchMatch) better(other searchMatch) bool {
+	if m.score != other.score {
+		return m.score < other.score
+	}
+
+	return !m.Alias && other.Alias
+}
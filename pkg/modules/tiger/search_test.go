@@ -0,0 +1,133 @@
+package tiger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	s3mocks "github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_SearchTigers(t *testing.T) {
+	names := []entities.TigerName{
+		{TigerID: 1, Name: "Raja"},
+		{TigerID: 2, Name: "Rani"},
+		{TigerID: 3, Name: "Machali"},
+		{TigerID: 3, Name: "Raja Besar", Alias: true},
+		{TigerID: 4, Name: "Rajah"},
+	}
+
+	tigers := map[uint]entities.Tiger{
+		1: {Model: gorm.Model{ID: 1}, Name: "Raja"},
+		2: {Model: gorm.Model{ID: 2}, Name: "Rani"},
+		3: {Model: gorm.Model{ID: 3}, Name: "Machali"},
+		4: {Model: gorm.Model{ID: 4}, Name: "Rajah"},
+	}
+
+	testCases := []struct {
+		name string
+
+		query        string
+		limit        int
+		findNamesErr error
+		wantIDs      []uint
+
+		want    []*model.TigerSearchResult
+		wantErr error
+	}{
+		{
+			name:    "should return exact match first, then prefix matches including aliases",
+			query:   "raja",
+			limit:   10,
+			wantIDs: []uint{1, 3, 4},
+			want: []*model.TigerSearchResult{
+				{Tiger: toModel(&entities.Tiger{Model: gorm.Model{ID: 1}, Name: "Raja"}), MatchedName: "Raja"},
+				{Tiger: toModel(&entities.Tiger{Model: gorm.Model{ID: 3}, Name: "Machali"}), MatchedName: "Raja Besar", Alias: true},
+				{Tiger: toModel(&entities.Tiger{Model: gorm.Model{ID: 4}, Name: "Rajah"}), MatchedName: "Rajah"},
+			},
+		},
+		{
+			name:    "should return tigers matching with a typo",
+			query:   "macahli",
+			limit:   10,
+			wantIDs: []uint{3},
+			want: []*model.TigerSearchResult{
+				{Tiger: toModel(&entities.Tiger{Model: gorm.Model{ID: 3}, Name: "Machali"}), MatchedName: "Machali"},
+			},
+		},
+		{
+			name:    "should return no more than limit tigers",
+			query:   "raja",
+			limit:   1,
+			wantIDs: []uint{1},
+			want: []*model.TigerSearchResult{
+				{Tiger: toModel(&entities.Tiger{Model: gorm.Model{ID: 1}, Name: "Raja"}), MatchedName: "Raja"},
+			},
+		},
+		{
+			name:  "should return empty list given no match",
+			query: "sultan",
+			limit: 10,
+			want:  []*model.TigerSearchResult{},
+		},
+		{
+			name:    "should return ErrInvalidSearchQuery given empty query",
+			query:   "  ",
+			limit:   10,
+			wantErr: entities.ErrInvalidSearchQuery,
+		},
+		{
+			name:    "should return ErrInvalidSearchQuery given limit above 50",
+			query:   "raja",
+			limit:   51,
+			wantErr: entities.ErrInvalidSearchQuery,
+		},
+		{
+			name:         "should return err given failed to find names",
+			query:        "raja",
+			limit:        10,
+			findNamesErr: errors.New("db error"),
+			wantErr:      errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindNames", mock.Anything).
+				Return(names, tc.findNamesErr).
+				Maybe()
+
+			if tc.wantIDs != nil {
+				var resp []entities.Tiger
+				for _, id := range tc.wantIDs {
+					resp = append(resp, tigers[id])
+				}
+
+				repo.
+					On("FindByIDs", mock.Anything, tc.wantIDs).
+					Return(resp, nil).
+					Once()
+			}
+
+			got, err := uc.SearchTigers(context.Background(), tc.query, tc.limit)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
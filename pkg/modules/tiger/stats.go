@@ -0,0 +1,123 @@
+package tiger
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+)
+
+// statsCacheSize is how many tigers the movement statistics are cached for.
+const statsCacheSize = 1000
+
+// statsCache keeps the movement statistics of the most recently requested tigers
+// together with the version of the tiger they were computed for. Every change to the
+// sightings of a tiger also updates the tiger, so a different version means the
+// statistics are stale. Only the latest version of a tiger is kept, and the least
+// recently used tiger is evicted once size tigers are cached.
+type statsCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[uint]*list.Element
+}
+
+type statsEntry struct {
+	id      uint
+	version uint
+	stats   model.MovementStats
+}
+
+func newStatsCache(size int) *statsCache {
+	return &statsCache{size: size, order: list.New(), entries: map[uint]*list.Element{}}
+}
+
+func (c *statsCache) get(id, version uint) (model.MovementStats, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[id]
+	if !ok {
+		return model.MovementStats{}, false
+	}
+
+	e := el.Value.(*statsEntry)
+	if e.version != version {
+		return model.MovementStats{}, false
+	}
+
+	c.order.MoveToFront(el)
+	return e.stats, true
+}
+
+func (c *statsCache) set(id, version uint, stats model.MovementStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[id]; ok {
+		el.Value = &statsEntry{id, version, stats}
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[id] = c.order.PushFront(&statsEntry{id, version, stats})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*statsEntry).id)
+	}
+}
+
+// GetMovementStats implements entities.TigerUsecase.
+func (u *usecase) GetMovementStats(ctx context.Context, tiger *model.Tiger) (*model.MovementStats, error) {
+	stats, ok := u.stats.get(tiger.ID, tiger.Version)
+	if !ok {
+		sightings, err := u.sightingRepo.FindTrack(ctx, tiger.ID, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		stats = movementStats(sightings)
+		u.stats.set(tiger.ID, tiger.Version, stats)
+	}
+
+	// the only statistic depending on the current time is never cached
+	stats.DaysSinceLastSeen = int(math.Max(0, time.Since(tiger.LastSeen).Hours()/24))
+
+	return &stats, nil
+}
+
+// movementStats computes the statistics of sightings in chronological order.
+func movementStats(sightings []entities.Sighting) model.MovementStats {
+	var stats model.MovementStats
+
+	reporters := map[uint]struct{}{}
+	for i, s := range sightings {
+		reporters[s.UserID] = struct{}{}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := sightings[i-1]
+		jump := geo.NewPoint(prev.Latitude, prev.Longitude).GreatCircleDistance(geo.NewPoint(s.Latitude, s.Longitude))
+
+		stats.TotalDistanceKm += jump
+		stats.MaxJumpKm = math.Max(stats.MaxJumpKm, jump)
+	}
+
+	stats.DistinctReporters = len(reporters)
+
+	if len(sightings) > 1 {
+		days := sightings[len(sightings)-1].Date.Sub(sightings[0].Date).Hours() / 24
+		stats.AverageDailyDisplacementKm = stats.TotalDistanceKm / math.Max(days, 1)
+	}
+
+	return stats
+}
@@ -0,0 +1,165 @@
+package tiger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	s3mocks "github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUsecase_GetMovementStats(t *testing.T) {
+	now := time.Now()
+
+	sightings := []entities.Sighting{
+		{Date: now.Add(-96 * time.Hour), Latitude: -7.550676, Longitude: 110.828316, UserID: 1},
+		{Date: now.Add(-72 * time.Hour), Latitude: -7.450676, Longitude: 110.828316, UserID: 2},
+		{Date: now.Add(-48 * time.Hour), Latitude: -7.450676, Longitude: 111.028316, UserID: 1},
+	}
+
+	short := geo.NewPoint(-7.550676, 110.828316).GreatCircleDistance(geo.NewPoint(-7.450676, 110.828316))
+	long := geo.NewPoint(-7.450676, 110.828316).GreatCircleDistance(geo.NewPoint(-7.450676, 111.028316))
+
+	testCases := []struct {
+		name string
+
+		findTrackResp []entities.Sighting
+		findTrackErr  error
+
+		want    *model.MovementStats
+		wantErr error
+	}{
+		{
+			name:          "should return statistics of the sightings",
+			findTrackResp: sightings,
+			want: &model.MovementStats{
+				TotalDistanceKm:            short + long,
+				AverageDailyDisplacementKm: (short + long) / 2,
+				MaxJumpKm:                  long,
+				DaysSinceLastSeen:          2,
+				DistinctReporters:          2,
+			},
+		},
+		{
+			name:          "should return zero distances given a single sighting",
+			findTrackResp: sightings[2:],
+			want: &model.MovementStats{
+				DaysSinceLastSeen: 2,
+				DistinctReporters: 1,
+			},
+		},
+		{
+			name:          "should count at least one day for the average",
+			findTrackResp: []entities.Sighting{sightings[0], {Date: sightings[0].Date.Add(time.Hour), Latitude: -7.450676, Longitude: 110.828316, UserID: 1}},
+			want: &model.MovementStats{
+				TotalDistanceKm:            short,
+				AverageDailyDisplacementKm: short,
+				MaxJumpKm:                  short,
+				DaysSinceLastSeen:          2,
+				DistinctReporters:          1,
+			},
+		},
+		{
+			name:         "should return err given failed to find sightings",
+			findTrackErr: errors.New("db error"),
+			wantErr:      errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			sightingRepo.
+				On("FindTrack", mock.Anything, uint(1), (*time.Time)(nil), (*time.Time)(nil)).
+				Return(tc.findTrackResp, tc.findTrackErr).
+				Once()
+
+			tiger := &model.Tiger{ID: 1, LastSeen: now.Add(-50 * time.Hour), Version: 3}
+
+			got, err := uc.GetMovementStats(context.Background(), tiger)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+
+			if tc.wantErr == nil {
+				// the same version is served from the cache without loading sightings again
+				cached, err := uc.GetMovementStats(context.Background(), tiger)
+				assert.Nil(t, err)
+				assert.Equal(t, tc.want, cached)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetMovementStats_VersionChange(t *testing.T) {
+	now := time.Now()
+
+	repo := mocks.NewTigerRepository(t)
+	sightingRepo := mocks.NewSightingRepository(t)
+	reserveRepo := mocks.NewReserveRepository(t)
+	policy := mocks.NewSightingPolicy(t)
+	s3 := s3mocks.NewS3ClientInterface(t)
+
+	uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+	sightingRepo.
+		On("FindTrack", mock.Anything, uint(1), (*time.Time)(nil), (*time.Time)(nil)).
+		Return([]entities.Sighting{{Date: now, UserID: 1}}, nil).
+		Once()
+
+	sightingRepo.
+		On("FindTrack", mock.Anything, uint(1), (*time.Time)(nil), (*time.Time)(nil)).
+		Return([]entities.Sighting{{Date: now, UserID: 1}, {Date: now, UserID: 2}}, nil).
+		Once()
+
+	got, err := uc.GetMovementStats(context.Background(), &model.Tiger{ID: 1, LastSeen: now, Version: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, got.DistinctReporters)
+
+	// a new sighting bumps the version of the tiger
+	got, err = uc.GetMovementStats(context.Background(), &model.Tiger{ID: 1, LastSeen: now, Version: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, got.DistinctReporters)
+}
+
+func TestStatsCache(t *testing.T) {
+	c := newStatsCache(2)
+
+	c.set(1, 1, model.MovementStats{DistinctReporters: 1})
+	c.set(2, 1, model.MovementStats{DistinctReporters: 2})
+
+	// a newer version replaces the cached one of the tiger
+	c.set(1, 2, model.MovementStats{DistinctReporters: 3})
+	_, ok := c.get(1, 1)
+	assert.False(t, ok)
+
+	got, ok := c.get(1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, 3, got.DistinctReporters)
+
+	// tiger 2 is the least recently used one now
+	c.set(3, 1, model.MovementStats{DistinctReporters: 4})
+	_, ok = c.get(2, 1)
+	assert.False(t, ok)
+
+	_, ok = c.get(1, 2)
+	assert.True(t, ok)
+
+	_, ok = c.get(3, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.order.Len())
+}
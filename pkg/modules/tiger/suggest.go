@@ -0,0 +1,108 @@
+package tiger
+
+import (
+	"context"
+	"sort"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
+)
+
+// maxSuggestionLimit is the number of tigers SuggestTigers returns at most.
+const maxSuggestionLimit = 20
+
+type suggestion struct {
+	entities.SightingSignature
+	similarity float64
+}
+
+// SuggestTigers implements entities.TigerUsecase.
+//
+// The signature of img is compared with the signature of every sighting photo, and
+// every tiger is listed once with its most similar photo.
+func (u *usecase) SuggestTigers(ctx context.Context, img *graphql.Upload, limit int) ([]*model.TigerSuggestion, error) {
+	if limit < 1 || limit > maxSuggestionLimit {
+		return nil, entities.ErrInvalidSuggestionLimit
+	}
+
+	if img == nil || !imageproc.IsContentTypeValid(img.ContentType, img.Filename) {
+		return nil, entities.ErrInvalidImageType
+	}
+
+	sig, err := imageproc.ComputeSignature(img.File)
+	if err != nil {
+		return nil, err
+	}
+
+	signatures, err := u.sightingRepo.FindSignatures(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	best := map[uint]suggestion{}
+	for _, s := range signatures {
+		other, err := imageproc.ParseSignature(s.Signature)
+		if err != nil {
+			continue
+		}
+
+		m := suggestion{s, sig.Similarity(other)}
+		if b, found := best[s.TigerID]; !found || m.similarity > b.similarity {
+			best[s.TigerID] = m
+		}
+	}
+
+	matches := make([]suggestion, 0, len(best))
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].similarity != matches[j].similarity {
+			return matches[i].similarity > matches[j].similarity
+		}
+		return matches[i].TigerID < matches[j].TigerID
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	res := []*model.TigerSuggestion{}
+	if len(matches) == 0 {
+		return res, nil
+	}
+
+	ids := make([]uint, len(matches))
+	for i, m := range matches {
+		ids[i] = m.TigerID
+	}
+
+	tigers, err := u.repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]*entities.Tiger, len(tigers))
+	for i := range tigers {
+		byID[tigers[i].ID] = &tigers[i]
+	}
+
+	for _, m := range matches {
+		t, ok := byID[m.TigerID]
+		if !ok {
+			continue
+		}
+
+		res = append(res, &model.TigerSuggestion{
+			Tiger:      toModel(t),
+			Similarity: m.similarity,
+			SightingID: m.SightingID,
+			ImageURL:   m.ImageURL,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,132 @@
+package tiger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
+	s3mocks "github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUsecase_SuggestTigers(t *testing.T) {
+	// stripes slanted by different amounts stand in for the patterns of different tigers
+	straight := testutil.Stripes(12, 0, 230)
+	slanted := testutil.Stripes(12, 4, 230)
+	steep := testutil.Stripes(12, 20, 230)
+
+	signatures := []entities.SightingSignature{
+		{SightingID: 1, TigerID: 1, ImageURL: "https://img/1.jpg", Signature: imageproc.SignatureOf(steep).String()},
+		{SightingID: 2, TigerID: 2, ImageURL: "https://img/2.jpg", Signature: imageproc.SignatureOf(slanted).String()},
+		{SightingID: 3, TigerID: 1, ImageURL: "https://img/3.jpg", Signature: imageproc.SignatureOf(straight).String()},
+		{SightingID: 4, TigerID: 3, ImageURL: "https://img/4.jpg", Signature: "corrupted"},
+	}
+
+	tigers := map[uint]entities.Tiger{
+		1: {Model: gorm.Model{ID: 1}, Name: "Raja"},
+		2: {Model: gorm.Model{ID: 2}, Name: "Rani"},
+	}
+
+	testCases := []struct {
+		name string
+
+		image             *graphql.Upload
+		limit             int
+		findSignaturesErr error
+		wantIDs           []uint
+
+		wantSightingIDs []uint
+		wantErr         error
+	}{
+		{
+			name:            "should return every tiger once with its most similar photo, most similar first",
+			image:           testutil.PNGUpload(t, straight),
+			limit:           5,
+			wantIDs:         []uint{1, 2},
+			wantSightingIDs: []uint{3, 2},
+		},
+		{
+			name:            "should return no more than limit tigers",
+			image:           testutil.PNGUpload(t, slanted),
+			limit:           1,
+			wantIDs:         []uint{2},
+			wantSightingIDs: []uint{2},
+		},
+		{
+			name:    "should return ErrInvalidSuggestionLimit given limit above 20",
+			image:   testutil.PNGUpload(t, straight),
+			limit:   21,
+			wantErr: entities.ErrInvalidSuggestionLimit,
+		},
+		{
+			name:    "should return ErrInvalidImageType given image that is not jpeg or png",
+			image:   &graphql.Upload{File: bytes.NewReader(nil), Filename: "tiger.gif", ContentType: "image/gif"},
+			limit:   5,
+			wantErr: entities.ErrInvalidImageType,
+		},
+		{
+			name:              "should return err given failed to find signatures",
+			image:             testutil.PNGUpload(t, straight),
+			limit:             5,
+			findSignaturesErr: errors.New("db error"),
+			wantErr:           errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			sightingRepo.
+				On("FindSignatures", mock.Anything).
+				Return(signatures, tc.findSignaturesErr).
+				Maybe()
+
+			if tc.wantIDs != nil {
+				var resp []entities.Tiger
+				for _, id := range tc.wantIDs {
+					resp = append(resp, tigers[id])
+				}
+
+				repo.
+					On("FindByIDs", mock.Anything, tc.wantIDs).
+					Return(resp, nil).
+					Once()
+			}
+
+			got, err := uc.SuggestTigers(context.Background(), tc.image, tc.limit)
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			var gotSightingIDs []uint
+			for i, s := range got {
+				gotSightingIDs = append(gotSightingIDs, s.SightingID)
+				assert.Equal(t, tc.wantIDs[i], s.Tiger.ID)
+				assert.Equal(t, signatures[s.SightingID-1].ImageURL, s.ImageURL)
+				if i > 0 {
+					assert.LessOrEqual(t, s.Similarity, got[i-1].Similarity)
+				}
+			}
+			assert.Equal(t, tc.wantSightingIDs, gotSightingIDs)
+			assert.InDelta(t, 1, got[0].Similarity, 1e-9)
+		})
+	}
+}
@@ -2,31 +2,66 @@ package tiger
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/labstack/gommon/log"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/geojson"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/homerange"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
 	"github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client"
+	"gorm.io/gorm"
 )
 
+// kdeLevel is the share of the kernel density the home range contour holds.
+const kdeLevel = 0.95
+
 type usecase struct {
 	repo         entities.TigerRepository
 	sightingRepo entities.SightingRepository
+	reserveRepo  entities.ReserveRepository
+	uow          entities.UnitOfWork
+	policy       entities.SightingPolicy
 	s3           s3client.S3ClientInterface
+	stats        *statsCache
 }
 
 // CreateTiger implements entities.TigerUsecase.
 func (u *usecase) CreateTiger(ctx context.Context, tiger *model.NewTiger, userID uint) (*model.Tiger, error) {
 	t := entities.Tiger{
-		Name:          tiger.Name,
-		DateOfBirth:   tiger.DateOfBirth,
-		LastSeen:      tiger.LastSeen,
-		LastLatitude:  tiger.LastLatitude,
-		LastLongitude: tiger.LastLongitude,
+		Name:             tiger.Name,
+		DateOfBirth:      tiger.DateOfBirth,
+		Sex:              model.SexUnknown,
+		Status:           model.TigerStatusActive,
+		LastSeen:         tiger.LastSeen,
+		LastLatitude:     tiger.LastLatitude,
+		LastLongitude:    tiger.LastLongitude,
+		ReserveID:        tiger.ReserveID,
+		MinDistanceKm:    tiger.MinDistanceKm,
+		NearbyAfterHours: tiger.NearbyAfterHours,
+		MotherID:         tiger.MotherID,
+		FatherID:         tiger.FatherID,
 	}
 
-	err := u.repo.Create(ctx, &t)
-	if err != nil {
+	if tiger.Sex != nil {
+		t.Sex = *tiger.Sex
+	}
+
+	if tiger.Subspecies != nil {
+		t.Subspecies = *tiger.Subspecies
+	}
+
+	if tiger.Marks != nil {
+		t.Marks = *tiger.Marks
+	}
+
+	if err := u.validateRule(ctx, &t); err != nil {
+		return nil, err
+	}
+
+	if err := u.validateParents(ctx, &t, false); err != nil {
 		return nil, err
 	}
 
@@ -34,7 +69,6 @@ func (u *usecase) CreateTiger(ctx context.Context, tiger *model.NewTiger, userID
 		Date:      tiger.LastSeen,
 		Latitude:  tiger.LastLatitude,
 		Longitude: tiger.LastLongitude,
-		TigerID:   t.ID,
 		UserID:    userID,
 	}
 
@@ -43,32 +77,85 @@ func (u *usecase) CreateTiger(ctx context.Context, tiger *model.NewTiger, userID
 			return nil, entities.ErrInvalidImageType
 		}
 
-		r, size, err := imageproc.ResizeImage(tiger.Image.File, tiger.Image.Filename)
+		rule, err := u.policy.RuleFor(ctx, &t)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := imageproc.ComputeHash(tiger.Image.File)
 		if err != nil {
 			return nil, err
 		}
 
-		url, err := u.s3.UploadImage(ctx, r, tiger.Image.Filename, tiger.Image.ContentType, int64(size))
+		// the first sighting gets the same duplicate check as any later one
+		dup, err := u.sightingRepo.FindDuplicateImage(ctx, hash, 0)
 		if err != nil {
 			return nil, err
 		}
 
-		sighting.ImageURL = url
+		if dup != 0 {
+			if !rule.FlagDuplicateImage {
+				return nil, entities.NewErrDuplicateImage(dup)
+			}
+
+			sighting.NeedsReview = true
+		}
+
+		if tiger.Image.ContentType == "image/jpeg" {
+			e, err := imageproc.ReadExif(tiger.Image.File)
+			if err != nil {
+				// the image itself may be fine, it is checked when it is processed
+				log.Warn(err)
+			} else {
+				sighting.SetExif(e)
+			}
+		}
+
+		if !rule.MatchesExif(&sighting) {
+			sighting.NeedsReview = true
+		}
+
+		sig, err := imageproc.ComputeSignature(tiger.Image.File)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := s3client.UploadRenditions(ctx, u.s3, tiger.Image.File, tiger.Image.Filename, tiger.Image.ContentType)
+		if err != nil {
+			return nil, err
+		}
+
+		sighting.ImageURL = r.ThumbURL
+		sighting.MediumImageURL = r.MediumURL
+		sighting.OriginalImageKey = r.OriginalKey
+		sighting.SetImageHash(hash)
+		sighting.ImageSignature = sig.String()
 	}
 
-	err = u.sightingRepo.Create(ctx, &sighting)
+	err := u.uow.Do(ctx, func(ctx context.Context) error {
+		err := u.repo.Create(ctx, &t)
+		if err != nil {
+			return err
+		}
+
+		sighting.TigerID = t.ID
+
+		return u.sightingRepo.Create(ctx, &sighting)
+	})
 	if err != nil {
+		deleteErr := s3client.DeleteRenditions(context.Background(), u.s3, s3client.Renditions{
+			ThumbURL:    sighting.ImageURL,
+			MediumURL:   sighting.MediumImageURL,
+			OriginalKey: sighting.OriginalImageKey,
+		})
+		if deleteErr != nil {
+			log.Error(deleteErr)
+		}
+
 		return nil, err
 	}
 
-	return &model.Tiger{
-		ID:            t.ID,
-		Name:          t.Name,
-		DateOfBirth:   t.DateOfBirth,
-		LastSeen:      t.LastSeen,
-		LastLatitude:  t.LastLatitude,
-		LastLongitude: t.LastLongitude,
-	}, nil
+	return toModel(&t), nil
 }
 
 // GetTigerByID implements entities.TigerUsecase.
@@ -78,44 +165,364 @@ func (u *usecase) GetTigerByID(ctx context.Context, id uint) (*model.Tiger, erro
 		return nil, err
 	}
 
-	return &model.Tiger{
-		ID:            t.ID,
-		Name:          t.Name,
-		DateOfBirth:   t.DateOfBirth,
-		LastSeen:      t.LastSeen,
-		LastLatitude:  t.LastLatitude,
-		LastLongitude: t.LastLongitude,
-		Sightings:     nil,
-	}, nil
+	return toModel(t), nil
+}
+
+// GetHomeRange implements entities.TigerUsecase.
+func (u *usecase) GetHomeRange(
+	ctx context.Context,
+	id uint,
+	from, to *time.Time,
+	withKDE bool,
+) (*model.HomeRange, error) {
+	if from != nil && to != nil && from.After(*to) {
+		return nil, entities.ErrInvalidDateRange
+	}
+
+	sightings, err := u.sightingRepo.FindTrack(ctx, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]homerange.Point, len(sightings))
+	for i, s := range sightings {
+		points[i] = homerange.Point{s.Latitude, s.Longitude}
+	}
+
+	ring, area, err := homerange.MCP(points)
+	if errors.Is(err, homerange.ErrTooFewPoints) {
+		return nil, entities.ErrNotEnoughSightings
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.HomeRange{
+		Sightings:  len(sightings),
+		Mcp:        *geojson.NewPolygon([][][2]float64{ring}),
+		McpAreaKm2: area,
+	}
+
+	if withKDE {
+		polygons, area, err := homerange.KDE(points, kdeLevel)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Kde = geojson.NewMultiPolygon(polygons)
+		res.KdeAreaKm2 = &area
+	}
+
+	return res, nil
 }
 
 // GetTigers implements entities.TigerUsecase.
-func (u *usecase) GetTigers(ctx context.Context, page int, pageSize int) ([]*model.Tiger, int, error) {
-	tigers, count, err := u.repo.FindAll(ctx, page, pageSize)
+func (u *usecase) GetTigers(
+	ctx context.Context,
+	filter *model.TigerFilter,
+	page, pageSize int,
+) ([]*model.Tiger, int, error) {
+	tigers, count, err := u.repo.FindAll(ctx, filter, page, pageSize)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	res := make([]*model.Tiger, len(tigers))
-	for i, t := range tigers {
-		res[i] = &model.Tiger{
-			ID:            t.ID,
-			Name:          t.Name,
-			DateOfBirth:   t.DateOfBirth,
-			LastSeen:      t.LastSeen,
-			LastLatitude:  t.LastLatitude,
-			LastLongitude: t.LastLongitude,
-			Sightings:     nil,
+	for i := range tigers {
+		res[i] = toModel(&tigers[i])
+	}
+
+	return res, count, nil
+}
+
+// GetTigersNear implements entities.TigerUsecase.
+func (u *usecase) GetTigersNear(
+	ctx context.Context,
+	latitude, longitude, radiusKm float64,
+	page, pageSize int,
+) ([]*model.NearbyTiger, int, error) {
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, 0, entities.ErrInvalidCoordinates
+	}
+
+	if radiusKm <= 0 {
+		return nil, 0, entities.ErrInvalidRadius
+	}
+
+	tigers, count, err := u.repo.FindNear(ctx, latitude, longitude, radiusKm, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res := make([]*model.NearbyTiger, len(tigers))
+	for i := range tigers {
+		res[i] = &model.NearbyTiger{
+			Tiger:      toModel(&tigers[i].Tiger),
+			DistanceKm: tigers[i].DistanceKm,
 		}
 	}
 
 	return res, count, nil
 }
 
+// UpdateTiger implements entities.TigerUsecase.
+func (u *usecase) UpdateTiger(ctx context.Context, id uint, tiger *model.UpdateTiger) (*model.Tiger, error) {
+	t, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrTigerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tiger.Name != nil {
+		t.Name = *tiger.Name
+	}
+
+	if tiger.DateOfBirth != nil {
+		t.DateOfBirth = *tiger.DateOfBirth
+	}
+
+	if tiger.Sex != nil {
+		t.Sex = *tiger.Sex
+	}
+
+	if tiger.Subspecies != nil {
+		t.Subspecies = *tiger.Subspecies
+	}
+
+	if tiger.Marks != nil {
+		t.Marks = *tiger.Marks
+	}
+
+	if tiger.ReserveID != nil {
+		t.ReserveID = tiger.ReserveID
+	}
+
+	if tiger.ClearSightingRule {
+		t.MinDistanceKm = nil
+		t.NearbyAfterHours = nil
+	}
+
+	if tiger.MinDistanceKm != nil {
+		t.MinDistanceKm = tiger.MinDistanceKm
+	}
+
+	if tiger.NearbyAfterHours != nil {
+		t.NearbyAfterHours = tiger.NearbyAfterHours
+	}
+
+	if tiger.MotherID != nil {
+		t.MotherID = parentID(*tiger.MotherID)
+	}
+
+	if tiger.FatherID != nil {
+		t.FatherID = parentID(*tiger.FatherID)
+	}
+
+	if err := u.validateRule(ctx, t); err != nil {
+		return nil, err
+	}
+
+	if err := u.validateParents(ctx, t, tiger.DateOfBirth != nil || tiger.Sex != nil); err != nil {
+		return nil, err
+	}
+
+	err = u.repo.Update(ctx, t, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toModel(t), nil
+}
+
+// DeleteTiger implements entities.TigerUsecase.
+//
+// Like UpdateTiger, an alias id acts on the tiger it was merged into.
+func (u *usecase) DeleteTiger(ctx context.Context, id uint) error {
+	id, err := u.repo.ResolveID(ctx, id)
+	if err == nil {
+		err = u.repo.Delete(ctx, id)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrTigerNotFound
+	}
+
+	return err
+}
+
+// RestoreTiger implements entities.TigerUsecase.
+//
+// Like UpdateTiger, an alias id acts on the tiger it was merged into.
+func (u *usecase) RestoreTiger(ctx context.Context, id uint) (*model.Tiger, error) {
+	id, err := u.repo.ResolveID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrTigerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = u.repo.FindByID(ctx, id)
+	if err == nil {
+		return nil, entities.ErrTigerNotDeleted
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	err = u.repo.Restore(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrTigerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetTigerByID(ctx, id)
+}
+
+// UpdateTigerStatus implements entities.TigerUsecase.
+//
+// Deceased is final, and changing to the current status is rejected so every entry
+// of the history is an actual change.
+func (u *usecase) UpdateTigerStatus(
+	ctx context.Context,
+	id uint,
+	input *model.TigerStatusInput,
+	userID uint,
+) (*model.Tiger, error) {
+	t, err := u.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrTigerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Status == input.Status || t.Status == model.TigerStatusDeceased {
+		return nil, entities.ErrInvalidStatusTransition
+	}
+
+	change := entities.TigerStatusChange{
+		TigerID:        t.ID,
+		PreviousStatus: t.Status,
+		Status:         input.Status,
+		Date:           time.Now(),
+		UserID:         userID,
+	}
+
+	if input.Date != nil {
+		change.Date = *input.Date
+	}
+
+	if input.Note != nil {
+		change.Note = *input.Note
+	}
+
+	t.Status = input.Status
+
+	err = u.uow.Do(ctx, func(ctx context.Context) error {
+		err := u.repo.Update(ctx, t, t.ID)
+		if err != nil {
+			return err
+		}
+
+		return u.repo.CreateStatusChange(ctx, &change)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toModel(t), nil
+}
+
+// GetStatusHistory implements entities.TigerUsecase.
+func (u *usecase) GetStatusHistory(ctx context.Context, id uint) ([]*model.TigerStatusChange, error) {
+	changes, err := u.repo.FindStatusHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*model.TigerStatusChange, len(changes))
+	for i, c := range changes {
+		res[i] = &model.TigerStatusChange{
+			ID:             c.ID,
+			PreviousStatus: c.PreviousStatus,
+			Status:         c.Status,
+			Date:           c.Date,
+			Note:           optional(c.Note),
+			UserID:         c.UserID,
+		}
+	}
+
+	return res, nil
+}
+
+// validateRule makes sure the reserve of the tiger exists and its sighting rule overrides are not negative.
+func (u *usecase) validateRule(ctx context.Context, t *entities.Tiger) error {
+	if (t.MinDistanceKm != nil && *t.MinDistanceKm < 0) || (t.NearbyAfterHours != nil && *t.NearbyAfterHours < 0) {
+		return entities.ErrInvalidSightingRule
+	}
+
+	if t.ReserveID == nil {
+		return nil
+	}
+
+	_, err := u.reserveRepo.FindByID(ctx, *t.ReserveID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrReserveNotFound
+	}
+
+	return err
+}
+
+func toModel(t *entities.Tiger) *model.Tiger {
+	return &model.Tiger{
+		ID:               t.ID,
+		Name:             t.Name,
+		DateOfBirth:      t.DateOfBirth,
+		Sex:              t.Sex,
+		Subspecies:       optional(t.Subspecies),
+		Marks:            optional(t.Marks),
+		Status:           t.Status,
+		LastSeen:         t.LastSeen,
+		LastLatitude:     t.LastLatitude,
+		LastLongitude:    t.LastLongitude,
+		ReserveID:        t.ReserveID,
+		MinDistanceKm:    t.MinDistanceKm,
+		NearbyAfterHours: t.NearbyAfterHours,
+		MotherID:         t.MotherID,
+		FatherID:         t.FatherID,
+		Version:          t.Version,
+	}
+}
+
+// optional returns nil for an empty string, so unset text fields are null in the API.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// parentID returns nil for 0, which clears a parent on update.
+func parentID(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+
+	return &id
+}
+
 func NewTigerUsecase(
 	repo entities.TigerRepository,
 	sightingRepo entities.SightingRepository,
+	reserveRepo entities.ReserveRepository,
+	uow entities.UnitOfWork,
+	policy entities.SightingPolicy,
 	s3 s3client.S3ClientInterface,
 ) entities.TigerUsecase {
-	return &usecase{repo, sightingRepo, s3}
+	return &usecase{repo, sightingRepo, reserveRepo, uow, policy, s3, newStatsCache(statsCacheSize)}
 }
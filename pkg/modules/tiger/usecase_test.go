@@ -1,14 +1,20 @@
 package tiger
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"image"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph/model"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities/mocks"
 	s3mocks "github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -16,14 +22,51 @@ import (
 
 func TestUsecase_CreateTiger(t *testing.T) {
 	now := time.Now()
+
+	// the photo was taken at 7° 33' 2.4" S, 110° 49' 41.94" E on 2024-03-01 06:30:00 +07:00
+	photo, err := os.ReadFile("../sighting/testdata/camera-trap.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exifLat := -(7.0 + 33.0/60 + 2.4/3600)
+	exifLng := 110.0 + 49.0/60 + 41.94/3600
+	exifDate := time.Date(2024, 3, 1, 6, 30, 0, 0, time.FixedZone("", 7*3600))
+
+	jpegUpload := func() *graphql.Upload {
+		return &graphql.Upload{
+			File:        bytes.NewReader(photo),
+			Filename:    "tiger.jpg",
+			Size:        int64(len(photo)),
+			ContentType: "image/jpeg",
+		}
+	}
+
+	want := &model.Tiger{
+		Name:          "tiger-1",
+		DateOfBirth:   now,
+		Sex:           model.SexUnknown,
+		Status:        model.TigerStatusActive,
+		LastSeen:      now,
+		LastLatitude:  -7.550676,
+		LastLongitude: 110.828316,
+	}
+
 	testCases := []struct {
 		name string
 
+		image             *graphql.Upload
+		duplicateOf       uint
+		flagDuplicate     bool
+		exifMaxTimeGap    time.Duration
 		createTigerErr    error
 		createSightingErr error
 
-		want    *model.Tiger
-		wantErr error
+		want            *model.Tiger
+		wantErr         error
+		wantImageDelete bool
+		wantExif        bool
+		wantReview      bool
 	}{
 		{
 			name:              "should return *model.Tiger and nil error",
@@ -32,35 +75,136 @@ func TestUsecase_CreateTiger(t *testing.T) {
 			want: &model.Tiger{
 				Name:          "tiger-1",
 				DateOfBirth:   now,
+				Sex:           model.SexUnknown,
+				Status:        model.TigerStatusActive,
 				LastSeen:      now,
 				LastLatitude:  -7.550676,
 				LastLongitude: 110.828316,
 			},
 			wantErr: nil,
 		},
+		{
+			name:              "should return error and delete uploaded image given sighting creation fails",
+			image:             testutil.PNGUpload(t, image.NewRGBA(image.Rect(0, 0, 10, 10))),
+			createTigerErr:    nil,
+			createSightingErr: errors.New("no such table: sightings"),
+			want:              nil,
+			wantErr:           errors.New("no such table: sightings"),
+			wantImageDelete:   true,
+		},
+		{
+			name:        "should return ErrDuplicateImage given the image was submitted before",
+			image:       testutil.PNGUpload(t, image.NewRGBA(image.Rect(0, 0, 10, 10))),
+			duplicateOf: 7,
+			wantErr:     entities.NewErrDuplicateImage(7),
+		},
+		{
+			name:          "should flag the sighting for review given duplicate images are flagged",
+			image:         testutil.PNGUpload(t, image.NewRGBA(image.Rect(0, 0, 10, 10))),
+			duplicateOf:   7,
+			flagDuplicate: true,
+			want:          want,
+			wantReview:    true,
+		},
+		{
+			name:     "should store the EXIF data of the image with the sighting",
+			image:    jpegUpload(),
+			want:     want,
+			wantExif: true,
+		},
+		{
+			name:           "should flag the sighting for review given it is further from the EXIF date than the rule allows",
+			image:          jpegUpload(),
+			exifMaxTimeGap: time.Hour,
+			want:           want,
+			wantExif:       true,
+			wantReview:     true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := mocks.NewTigerRepository(t)
 			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
 			s3 := s3mocks.NewS3ClientInterface(t)
 
-			uc := NewTigerUsecase(repo, sightingRepo, s3)
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
 
-			repo.
-				On("Create", mock.Anything, &entities.Tiger{
-					Name:          "tiger-1",
-					DateOfBirth:   now,
-					LastSeen:      now,
-					LastLatitude:  -7.550676,
-					LastLongitude: 110.828316,
-				}).
-				Return(tc.createTigerErr).
-				Once()
+			policy.
+				On("RuleFor", mock.Anything, mock.Anything).
+				Return(entities.SightingRule{
+					FlagDuplicateImage: tc.flagDuplicate,
+					ExifMaxTimeGap:     tc.exifMaxTimeGap,
+				}, nil).
+				Maybe()
+
+			sightingRepo.
+				On("FindDuplicateImage", mock.Anything, mock.Anything, uint(0)).
+				Return(tc.duplicateOf, nil).
+				Maybe()
+
+			if tc.image != nil {
+				s3.
+					On("UploadImage", mock.Anything, mock.Anything, "thumb/"+tc.image.Filename, "image/jpeg").
+					Return("https://example.com/thumb/tiger.png", nil).
+					Maybe()
+
+				s3.
+					On("UploadImage", mock.Anything, mock.Anything, "medium/"+tc.image.Filename, "image/jpeg").
+					Return("https://example.com/medium/tiger.png", nil).
+					Maybe()
+
+				s3.
+					On("UploadPrivateImage", mock.Anything, mock.Anything, tc.image.Filename, tc.image.ContentType).
+					Return("tiger.png", nil).
+					Maybe()
+			}
+
+			if tc.wantImageDelete {
+				s3.
+					On("DeleteImage", mock.Anything, "https://example.com/thumb/tiger.png").
+					Return(nil).
+					Once()
+
+				s3.
+					On("DeleteImage", mock.Anything, "https://example.com/medium/tiger.png").
+					Return(nil).
+					Once()
+
+				s3.
+					On("DeletePrivateImage", mock.Anything, "tiger.png").
+					Return(nil).
+					Once()
+			}
+
+			if tc.duplicateOf == 0 || tc.flagDuplicate {
+				repo.
+					On("Create", mock.Anything, &entities.Tiger{
+						Name:          "tiger-1",
+						DateOfBirth:   now,
+						Sex:           model.SexUnknown,
+						Status:        model.TigerStatusActive,
+						LastSeen:      now,
+						LastLatitude:  -7.550676,
+						LastLongitude: 110.828316,
+					}).
+					Return(tc.createTigerErr).
+					Once()
+			}
 
 			sightingRepo.
-				On("Create", mock.Anything, mock.Anything).
+				On("Create", mock.Anything, mock.MatchedBy(func(s *entities.Sighting) bool {
+					if !tc.wantExif {
+						return s.NeedsReview == tc.wantReview && s.ExifDate == nil
+					}
+
+					return s.NeedsReview == tc.wantReview &&
+						s.ExifLatitude != nil && *s.ExifLatitude == exifLat &&
+						s.ExifLongitude != nil && *s.ExifLongitude == exifLng &&
+						s.ExifDate != nil && s.ExifDate.Equal(exifDate)
+				})).
 				Return(tc.createSightingErr).
 				Maybe()
 
@@ -70,6 +214,7 @@ func TestUsecase_CreateTiger(t *testing.T) {
 				LastSeen:      now,
 				LastLatitude:  -7.550676,
 				LastLongitude: 110.828316,
+				Image:         tc.image,
 			}, 1)
 
 			assert.Equal(t, tc.wantErr, err)
@@ -120,9 +265,11 @@ func TestUsecase_GetTigerByID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := mocks.NewTigerRepository(t)
 			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
 			s3 := s3mocks.NewS3ClientInterface(t)
 
-			uc := NewTigerUsecase(repo, sightingRepo, s3)
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
 
 			repo.
 				On("FindByID", mock.Anything, uint(1)).
@@ -139,6 +286,7 @@ func TestUsecase_GetTigerByID(t *testing.T) {
 
 func TestUsecase_GetTigers(t *testing.T) {
 	now := time.Now()
+	filter := &model.TigerFilter{Status: []model.TigerStatus{model.TigerStatusActive}}
 
 	testCases := []struct {
 		name string
@@ -188,16 +336,18 @@ func TestUsecase_GetTigers(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := mocks.NewTigerRepository(t)
 			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
 			s3 := s3mocks.NewS3ClientInterface(t)
 
-			uc := NewTigerUsecase(repo, sightingRepo, s3)
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
 
 			repo.
-				On("FindAll", mock.Anything, 1, 10).
+				On("FindAll", mock.Anything, filter, 1, 10).
 				Return(tc.findAllResp, tc.findAllCount, tc.findAllErr).
 				Once()
 
-			got, count, err := uc.GetTigers(context.Background(), 1, 10)
+			got, count, err := uc.GetTigers(context.Background(), filter, 1, 10)
 
 			assert.Equal(t, tc.wantErr, err)
 			assert.Equal(t, tc.want, got)
@@ -205,3 +355,836 @@ func TestUsecase_GetTigers(t *testing.T) {
 		})
 	}
 }
+
+func TestUsecase_GetTigersNear(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		latitude  float64
+		longitude float64
+		radiusKm  float64
+
+		findNearResp  []entities.NearbyTiger
+		findNearCount int
+		findNearErr   error
+
+		want      []*model.NearbyTiger
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name:      "should return []*model.NearbyTiger and nil error",
+			latitude:  -7.550676,
+			longitude: 110.828316,
+			radiusKm:  10,
+			findNearResp: []entities.NearbyTiger{
+				{
+					Tiger: entities.Tiger{
+						Model:         gorm.Model{ID: 1},
+						Name:          "tiger-1",
+						LastLatitude:  -7.550676,
+						LastLongitude: 110.828316,
+					},
+					DistanceKm: 0,
+				},
+			},
+			findNearCount: 1,
+			want: []*model.NearbyTiger{
+				{
+					Tiger: &model.Tiger{
+						ID:            1,
+						Name:          "tiger-1",
+						LastLatitude:  -7.550676,
+						LastLongitude: 110.828316,
+					},
+					DistanceKm: 0,
+				},
+			},
+			wantCount: 1,
+		},
+		{
+			name:      "should return ErrInvalidCoordinates given latitude out of range",
+			latitude:  -91,
+			longitude: 110.828316,
+			radiusKm:  10,
+			wantErr:   entities.ErrInvalidCoordinates,
+		},
+		{
+			name:      "should return ErrInvalidRadius given radius is not positive",
+			latitude:  -7.550676,
+			longitude: 110.828316,
+			radiusKm:  0,
+			wantErr:   entities.ErrInvalidRadius,
+		},
+		{
+			name:        "should return err given failed to find tigers",
+			latitude:    -7.550676,
+			longitude:   110.828316,
+			radiusKm:    10,
+			findNearErr: errors.New("db error"),
+			wantErr:     errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindNear", mock.Anything, tc.latitude, tc.longitude, tc.radiusKm, 1, 10).
+				Return(tc.findNearResp, tc.findNearCount, tc.findNearErr).
+				Maybe()
+
+			got, count, err := uc.GetTigersNear(context.Background(), tc.latitude, tc.longitude, tc.radiusKm, 1, 10)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantCount, count)
+		})
+	}
+}
+
+func TestUsecase_GetHomeRange(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	// about 10 km apart, the corners of a 100 km² square and its center
+	square := []entities.Sighting{
+		{Latitude: -7.595, Longitude: 110.755},
+		{Latitude: -7.595, Longitude: 110.845},
+		{Latitude: -7.505, Longitude: 110.845},
+		{Latitude: -7.505, Longitude: 110.755},
+		{Latitude: -7.55, Longitude: 110.8},
+	}
+
+	testCases := []struct {
+		name string
+
+		from    *time.Time
+		to      *time.Time
+		withKDE bool
+
+		findTrackResp []entities.Sighting
+		findTrackErr  error
+
+		wantArea float64
+		wantKDE  bool
+		wantErr  error
+	}{
+		{
+			name:          "should return the minimum convex polygon",
+			findTrackResp: square,
+			wantArea:      100,
+		},
+		{
+			name:          "should return the kernel density contour given kde",
+			from:          &before,
+			to:            &now,
+			withKDE:       true,
+			findTrackResp: square,
+			wantArea:      100,
+			wantKDE:       true,
+		},
+		{
+			name:          "should return ErrNotEnoughSightings given two sightings",
+			findTrackResp: square[:2],
+			wantErr:       entities.ErrNotEnoughSightings,
+		},
+		{
+			name:    "should return ErrInvalidDateRange given from after to",
+			from:    &now,
+			to:      &before,
+			wantErr: entities.ErrInvalidDateRange,
+		},
+		{
+			name:         "should return err given failed to find sightings",
+			findTrackErr: errors.New("db error"),
+			wantErr:      errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			sightingRepo.
+				On("FindTrack", mock.Anything, uint(1), tc.from, tc.to).
+				Return(tc.findTrackResp, tc.findTrackErr).
+				Maybe()
+
+			got, err := uc.GetHomeRange(context.Background(), 1, tc.from, tc.to, tc.withKDE)
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.Equal(t, len(tc.findTrackResp), got.Sightings)
+			assert.Equal(t, "Polygon", got.Mcp.Type)
+			assert.InDelta(t, tc.wantArea, got.McpAreaKm2, 1)
+
+			if tc.wantKDE {
+				assert.Equal(t, "MultiPolygon", got.Kde.Type)
+				assert.Greater(t, *got.KdeAreaKm2, 0.0)
+			} else {
+				assert.Nil(t, got.Kde)
+				assert.Nil(t, got.KdeAreaKm2)
+			}
+		})
+	}
+}
+
+func TestUsecase_UpdateTiger(t *testing.T) {
+	now := time.Now()
+	name := "tiger-1-updated"
+	reserveID := uint(7)
+	negative, minDistance, nearbyAfter := -1.0, 2.0, 12.0
+	male, marks, empty := model.SexMale, "notched left ear", ""
+	motherID, noParent, selfID := uint(2), uint(0), uint(1)
+	born := now.AddDate(-1, 0, 0)
+
+	testCases := []struct {
+		name string
+
+		input *model.UpdateTiger
+
+		findByIDResp     *entities.Tiger
+		findByIDErr      error
+		findReserveErr   error
+		findParentResp   *entities.Tiger
+		findParentErr    error
+		findChildrenResp []entities.Tiger
+		updateErr        error
+
+		want    *model.Tiger
+		wantErr error
+	}{
+		{
+			name:  "should return updated *model.Tiger and nil error",
+			input: &model.UpdateTiger{Name: &name},
+			findByIDResp: &entities.Tiger{
+				Model:         gorm.Model{ID: 1},
+				Name:          "tiger-1",
+				DateOfBirth:   now,
+				LastSeen:      now,
+				LastLatitude:  -7.550676,
+				LastLongitude: 110.828316,
+			},
+			want: &model.Tiger{
+				ID:            1,
+				Name:          "tiger-1-updated",
+				DateOfBirth:   now,
+				LastSeen:      now,
+				LastLatitude:  -7.550676,
+				LastLongitude: 110.828316,
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "should update sex and marks and clear subspecies given empty string",
+			input: &model.UpdateTiger{Sex: &male, Marks: &marks, Subspecies: &empty},
+			findByIDResp: &entities.Tiger{
+				Model:      gorm.Model{ID: 1},
+				Name:       "tiger-1",
+				Sex:        model.SexUnknown,
+				Subspecies: "Bengal",
+			},
+			want: &model.Tiger{
+				ID:    1,
+				Name:  "tiger-1",
+				Sex:   model.SexMale,
+				Marks: &marks,
+			},
+		},
+		{
+			name:  "should set mother given older female tiger",
+			input: &model.UpdateTiger{MotherID: &motherID},
+			findByIDResp: &entities.Tiger{
+				Model:       gorm.Model{ID: 1},
+				Name:        "tiger-1",
+				DateOfBirth: now,
+			},
+			findParentResp: &entities.Tiger{
+				Model:       gorm.Model{ID: 2},
+				DateOfBirth: born,
+				Sex:         model.SexFemale,
+			},
+			want: &model.Tiger{
+				ID:          1,
+				Name:        "tiger-1",
+				DateOfBirth: now,
+				MotherID:    &motherID,
+			},
+		},
+		{
+			name:  "should clear mother given 0",
+			input: &model.UpdateTiger{MotherID: &noParent},
+			findByIDResp: &entities.Tiger{
+				Model:    gorm.Model{ID: 1},
+				Name:     "tiger-1",
+				MotherID: &motherID,
+			},
+			want: &model.Tiger{
+				ID:   1,
+				Name: "tiger-1",
+			},
+		},
+		{
+			name:  "should return ErrParentNotFound given mother does not exist",
+			input: &model.UpdateTiger{MotherID: &motherID},
+			findByIDResp: &entities.Tiger{
+				Model: gorm.Model{ID: 1},
+			},
+			findParentErr: gorm.ErrRecordNotFound,
+			wantErr:       entities.ErrParentNotFound,
+		},
+		{
+			name:  "should return ErrInvalidParent given male mother",
+			input: &model.UpdateTiger{MotherID: &motherID},
+			findByIDResp: &entities.Tiger{
+				Model:       gorm.Model{ID: 1},
+				DateOfBirth: now,
+			},
+			findParentResp: &entities.Tiger{
+				Model:       gorm.Model{ID: 2},
+				DateOfBirth: born,
+				Sex:         model.SexMale,
+			},
+			wantErr: entities.ErrInvalidParent,
+		},
+		{
+			name:  "should return ErrInvalidParent given tiger as its own mother",
+			input: &model.UpdateTiger{MotherID: &selfID},
+			findByIDResp: &entities.Tiger{
+				Model: gorm.Model{ID: 1},
+			},
+			wantErr: entities.ErrInvalidParent,
+		},
+		{
+			name:  "should return ErrParentNotOlder given mother born after the tiger",
+			input: &model.UpdateTiger{MotherID: &motherID},
+			findByIDResp: &entities.Tiger{
+				Model:       gorm.Model{ID: 1},
+				DateOfBirth: born,
+			},
+			findParentResp: &entities.Tiger{
+				Model:       gorm.Model{ID: 2},
+				DateOfBirth: now,
+			},
+			wantErr: entities.ErrParentNotOlder,
+		},
+		{
+			name:  "should return ErrParentNotOlder given date of birth after a cub",
+			input: &model.UpdateTiger{DateOfBirth: &now},
+			findByIDResp: &entities.Tiger{
+				Model:       gorm.Model{ID: 1},
+				DateOfBirth: born,
+			},
+			findChildrenResp: []entities.Tiger{
+				{Model: gorm.Model{ID: 3}, MotherID: &selfID, DateOfBirth: born.AddDate(0, 6, 0)},
+			},
+			wantErr: entities.ErrParentNotOlder,
+		},
+		{
+			name:  "should return ErrInvalidParent given male mother of a cub",
+			input: &model.UpdateTiger{Sex: &male},
+			findByIDResp: &entities.Tiger{
+				Model: gorm.Model{ID: 1},
+			},
+			findChildrenResp: []entities.Tiger{
+				{Model: gorm.Model{ID: 3}, MotherID: &selfID, DateOfBirth: now},
+			},
+			wantErr: entities.ErrInvalidParent,
+		},
+		{
+			name:        "should return ErrTigerNotFound given tiger does not exist",
+			input:       &model.UpdateTiger{Name: &name},
+			findByIDErr: gorm.ErrRecordNotFound,
+			want:        nil,
+			wantErr:     entities.ErrTigerNotFound,
+		},
+		{
+			name:  "should return updated *model.Tiger given existing reserve",
+			input: &model.UpdateTiger{ReserveID: &reserveID},
+			findByIDResp: &entities.Tiger{
+				Model: gorm.Model{ID: 1},
+				Name:  "tiger-1",
+			},
+			want: &model.Tiger{
+				ID:        1,
+				Name:      "tiger-1",
+				ReserveID: &reserveID,
+			},
+		},
+		{
+			name:  "should return ErrReserveNotFound given reserve does not exist",
+			input: &model.UpdateTiger{ReserveID: &reserveID},
+			findByIDResp: &entities.Tiger{
+				Model: gorm.Model{ID: 1},
+				Name:  "tiger-1",
+			},
+			findReserveErr: gorm.ErrRecordNotFound,
+			wantErr:        entities.ErrReserveNotFound,
+		},
+		{
+			name:  "should return ErrInvalidSightingRule given negative minimum distance",
+			input: &model.UpdateTiger{MinDistanceKm: &negative},
+			findByIDResp: &entities.Tiger{
+				Model: gorm.Model{ID: 1},
+				Name:  "tiger-1",
+			},
+			wantErr: entities.ErrInvalidSightingRule,
+		},
+		{
+			name:  "should clear the sighting rule overrides given clearSightingRule",
+			input: &model.UpdateTiger{ClearSightingRule: true},
+			findByIDResp: &entities.Tiger{
+				Model:            gorm.Model{ID: 1},
+				Name:             "tiger-1",
+				MinDistanceKm:    &minDistance,
+				NearbyAfterHours: &nearbyAfter,
+			},
+			want: &model.Tiger{
+				ID:   1,
+				Name: "tiger-1",
+			},
+		},
+		{
+			name:  "should set the given override after clearing the sighting rule overrides",
+			input: &model.UpdateTiger{ClearSightingRule: true, MinDistanceKm: &minDistance},
+			findByIDResp: &entities.Tiger{
+				Model:            gorm.Model{ID: 1},
+				Name:             "tiger-1",
+				MinDistanceKm:    &nearbyAfter,
+				NearbyAfterHours: &nearbyAfter,
+			},
+			want: &model.Tiger{
+				ID:            1,
+				Name:          "tiger-1",
+				MinDistanceKm: &minDistance,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindByID", mock.Anything, uint(1)).
+				Return(tc.findByIDResp, tc.findByIDErr).
+				Once()
+
+			if tc.input.ReserveID != nil && tc.findByIDErr == nil {
+				reserveRepo.
+					On("FindByID", mock.Anything, *tc.input.ReserveID).
+					Return(&entities.Reserve{}, tc.findReserveErr).
+					Once()
+			}
+
+			if tc.findParentResp != nil || tc.findParentErr != nil {
+				repo.
+					On("FindByID", mock.Anything, *tc.input.MotherID).
+					Return(tc.findParentResp, tc.findParentErr).
+					Once()
+			}
+
+			repo.
+				On("FindChildren", mock.Anything, []uint{1}).
+				Return(tc.findChildrenResp, nil).
+				Maybe()
+
+			repo.
+				On("Update", mock.Anything, mock.Anything, uint(1)).
+				Return(tc.updateErr).
+				Maybe()
+
+			got, err := uc.UpdateTiger(context.Background(), 1, tc.input)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUsecase_UpdateTigerStatus(t *testing.T) {
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	note := "found dead near the river"
+
+	testCases := []struct {
+		name string
+
+		input *model.TigerStatusInput
+
+		findByIDResp *entities.Tiger
+		findByIDErr  error
+		updateErr    error
+
+		wantChange *entities.TigerStatusChange
+		want       *model.Tiger
+		wantErr    error
+	}{
+		{
+			name:  "should update status and record the change",
+			input: &model.TigerStatusInput{Status: model.TigerStatusDeceased, Date: &date, Note: &note},
+			findByIDResp: &entities.Tiger{
+				Model:  gorm.Model{ID: 1},
+				Name:   "tiger-1",
+				Status: model.TigerStatusMissing,
+			},
+			wantChange: &entities.TigerStatusChange{
+				TigerID:        1,
+				PreviousStatus: model.TigerStatusMissing,
+				Status:         model.TigerStatusDeceased,
+				Date:           date,
+				Note:           note,
+				UserID:         2,
+			},
+			want: &model.Tiger{
+				ID:     1,
+				Name:   "tiger-1",
+				Status: model.TigerStatusDeceased,
+			},
+		},
+		{
+			name:        "should return ErrTigerNotFound given tiger does not exist",
+			input:       &model.TigerStatusInput{Status: model.TigerStatusMissing},
+			findByIDErr: gorm.ErrRecordNotFound,
+			wantErr:     entities.ErrTigerNotFound,
+		},
+		{
+			name:  "should return ErrInvalidStatusTransition given the same status",
+			input: &model.TigerStatusInput{Status: model.TigerStatusActive},
+			findByIDResp: &entities.Tiger{
+				Model:  gorm.Model{ID: 1},
+				Status: model.TigerStatusActive,
+			},
+			wantErr: entities.ErrInvalidStatusTransition,
+		},
+		{
+			name:  "should return ErrInvalidStatusTransition given deceased tiger",
+			input: &model.TigerStatusInput{Status: model.TigerStatusActive},
+			findByIDResp: &entities.Tiger{
+				Model:  gorm.Model{ID: 1},
+				Status: model.TigerStatusDeceased,
+			},
+			wantErr: entities.ErrInvalidStatusTransition,
+		},
+		{
+			name:  "should return err given failed to update tiger",
+			input: &model.TigerStatusInput{Status: model.TigerStatusRelocated},
+			findByIDResp: &entities.Tiger{
+				Model:  gorm.Model{ID: 1},
+				Status: model.TigerStatusActive,
+			},
+			updateErr: entities.ErrConcurrentModification,
+			wantErr:   entities.ErrConcurrentModification,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindByID", mock.Anything, uint(1)).
+				Return(tc.findByIDResp, tc.findByIDErr).
+				Once()
+
+			repo.
+				On("Update", mock.Anything, mock.Anything, uint(1)).
+				Return(tc.updateErr).
+				Maybe()
+
+			if tc.wantChange != nil {
+				repo.
+					On("CreateStatusChange", mock.Anything, tc.wantChange).
+					Return(nil).
+					Once()
+			}
+
+			got, err := uc.UpdateTigerStatus(context.Background(), 1, tc.input, 2)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUsecase_GetStatusHistory(t *testing.T) {
+	date := time.Now()
+	note := "moved to a larger reserve"
+
+	testCases := []struct {
+		name string
+
+		findResp []entities.TigerStatusChange
+		findErr  error
+
+		want    []*model.TigerStatusChange
+		wantErr error
+	}{
+		{
+			name: "should return []*model.TigerStatusChange and nil error",
+			findResp: []entities.TigerStatusChange{
+				{
+					Model:          gorm.Model{ID: 3},
+					TigerID:        1,
+					PreviousStatus: model.TigerStatusActive,
+					Status:         model.TigerStatusMissing,
+					Date:           date,
+					UserID:         2,
+				},
+				{
+					Model:          gorm.Model{ID: 4},
+					TigerID:        1,
+					PreviousStatus: model.TigerStatusMissing,
+					Status:         model.TigerStatusRelocated,
+					Date:           date,
+					Note:           note,
+					UserID:         2,
+				},
+			},
+			want: []*model.TigerStatusChange{
+				{
+					ID:             3,
+					PreviousStatus: model.TigerStatusActive,
+					Status:         model.TigerStatusMissing,
+					Date:           date,
+					UserID:         2,
+				},
+				{
+					ID:             4,
+					PreviousStatus: model.TigerStatusMissing,
+					Status:         model.TigerStatusRelocated,
+					Date:           date,
+					Note:           &note,
+					UserID:         2,
+				},
+			},
+		},
+		{
+			name:    "should return err given failed to find history",
+			findErr: errors.New("db error"),
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("FindStatusHistory", mock.Anything, uint(1)).
+				Return(tc.findResp, tc.findErr).
+				Once()
+
+			got, err := uc.GetStatusHistory(context.Background(), 1)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUsecase_DeleteTiger(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		id         uint
+		resolveErr error
+		deleteErr  error
+
+		wantErr error
+	}{
+		{
+			name:      "should return nil error",
+			id:        1,
+			deleteErr: nil,
+			wantErr:   nil,
+		},
+		{
+			name:    "should delete the tiger an alias was merged into given an alias id",
+			id:      5,
+			wantErr: nil,
+		},
+		{
+			name:       "should return ErrTigerNotFound given tiger does not exist",
+			id:         1,
+			resolveErr: gorm.ErrRecordNotFound,
+			wantErr:    entities.ErrTigerNotFound,
+		},
+		{
+			name:      "should return ErrTigerNotFound given tiger is already deleted",
+			id:        1,
+			deleteErr: gorm.ErrRecordNotFound,
+			wantErr:   entities.ErrTigerNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("ResolveID", mock.Anything, tc.id).
+				Return(uint(1), tc.resolveErr).
+				Once()
+
+			if tc.resolveErr == nil {
+				repo.
+					On("Delete", mock.Anything, uint(1)).
+					Return(tc.deleteErr).
+					Once()
+			}
+
+			err := uc.DeleteTiger(context.Background(), tc.id)
+
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestUsecase_RestoreTiger(t *testing.T) {
+	now := time.Now()
+	tiger := &entities.Tiger{
+		Model:         gorm.Model{ID: 1},
+		Name:          "tiger-1",
+		DateOfBirth:   now,
+		LastSeen:      now,
+		LastLatitude:  -7.550676,
+		LastLongitude: 110.828316,
+	}
+	restored := &model.Tiger{
+		ID:            1,
+		Name:          "tiger-1",
+		DateOfBirth:   now,
+		LastSeen:      now,
+		LastLatitude:  -7.550676,
+		LastLongitude: 110.828316,
+	}
+
+	testCases := []struct {
+		name string
+
+		id           uint
+		resolveErr   error
+		findByIDResp []*entities.Tiger
+		findByIDErr  error
+		restoreErr   error
+
+		want    *model.Tiger
+		wantErr error
+	}{
+		{
+			name:         "should return restored *model.Tiger and nil error",
+			id:           1,
+			findByIDResp: []*entities.Tiger{nil, tiger},
+			want:         restored,
+			wantErr:      nil,
+		},
+		{
+			name:         "should restore the tiger an alias was merged into given an alias id",
+			id:           5,
+			findByIDResp: []*entities.Tiger{nil, tiger},
+			want:         restored,
+		},
+		{
+			name:         "should return ErrTigerNotDeleted given tiger is not deleted",
+			id:           1,
+			findByIDResp: []*entities.Tiger{tiger},
+			want:         nil,
+			wantErr:      entities.ErrTigerNotDeleted,
+		},
+		{
+			name:         "should return the error given the tiger cannot be looked up",
+			id:           1,
+			findByIDResp: []*entities.Tiger{nil},
+			findByIDErr:  errors.New("database is locked"),
+			wantErr:      errors.New("database is locked"),
+		},
+		{
+			name:       "should return ErrTigerNotFound given tiger does not exist",
+			id:         1,
+			resolveErr: gorm.ErrRecordNotFound,
+			want:       nil,
+			wantErr:    entities.ErrTigerNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTigerRepository(t)
+			sightingRepo := mocks.NewSightingRepository(t)
+			reserveRepo := mocks.NewReserveRepository(t)
+			policy := mocks.NewSightingPolicy(t)
+			s3 := s3mocks.NewS3ClientInterface(t)
+
+			uc := NewTigerUsecase(repo, sightingRepo, reserveRepo, mocks.NewInlineUnitOfWork(t), policy, s3)
+
+			repo.
+				On("ResolveID", mock.Anything, tc.id).
+				Return(uint(1), tc.resolveErr).
+				Once()
+
+			for _, resp := range tc.findByIDResp {
+				err := tc.findByIDErr
+				if resp == nil && err == nil {
+					err = gorm.ErrRecordNotFound
+				}
+
+				repo.
+					On("FindByID", mock.Anything, uint(1)).
+					Return(resp, err).
+					Once()
+			}
+
+			repo.
+				On("Restore", mock.Anything, uint(1)).
+				Return(tc.restoreErr).
+				Maybe()
+
+			got, err := uc.RestoreTiger(context.Background(), tc.id)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
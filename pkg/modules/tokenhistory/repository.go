@@ -3,6 +3,7 @@ package tokenhistory
 import (
 	"context"
 
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	"gorm.io/gorm"
 )
@@ -13,7 +14,7 @@ type repo struct {
 
 // Create implements entities.TokenHistoryRepository.
 func (r *repo) Create(ctx context.Context, token *entities.TokenHistory) error {
-	err := r.db.WithContext(ctx).Create(token).Error
+	err := db.Conn(ctx, r.db).Create(token).Error
 	if err != nil {
 		return err
 	}
@@ -24,8 +25,7 @@ func (r *repo) Create(ctx context.Context, token *entities.TokenHistory) error {
 // FindByToken implements entities.TokenHistoryRepository.
 func (r *repo) FindByToken(ctx context.Context, token string) (*entities.TokenHistory, error) {
 	var res entities.TokenHistory
-	err := r.db.
-		WithContext(ctx).
+	err := db.Conn(ctx, r.db).
 		Where("token = ?", token).
 		First(&res).
 		Error
@@ -63,3 +63,21 @@ func UserByCtx(ctx context.Context) (*entities.User, error) {
 	v := i.(*entities.User)
 	return v, nil
 }
+
+// AdminByCtx returns the user in the context, or ErrForbidden if the user is not an admin.
+func AdminByCtx(ctx context.Context) (*entities.User, error) {
+	u, err := UserByCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.ID == 0 {
+		return nil, entities.ErrUserByCtxNotFound
+	}
+
+	if !u.IsAdmin() {
+		return nil, entities.ErrForbidden
+	}
+
+	return u, nil
+}
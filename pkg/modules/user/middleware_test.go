@@ -241,3 +241,43 @@ func TestMiddleware_UserByCtx(t *testing.T) {
 		})
 	}
 }
+
+func TestMiddleware_AdminByCtx(t *testing.T) {
+	testCases := []struct {
+		name    string
+		user    *entities.User
+		wantErr error
+	}{
+		{
+			name:    "should return admin from context",
+			user:    &entities.User{Model: gorm.Model{ID: 1}, Role: entities.RoleAdmin},
+			wantErr: nil,
+		},
+		{
+			name:    "should return ErrForbidden given user is not admin",
+			user:    &entities.User{Model: gorm.Model{ID: 1}, Role: entities.RoleUser},
+			wantErr: entities.ErrForbidden,
+		},
+		{
+			name:    "should return ErrUserByCtxNotFound given no user in context",
+			user:    nil,
+			wantErr: entities.ErrUserByCtxNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.user != nil {
+				ctx = context.WithValue(ctx, KeyUser, tc.user)
+			}
+
+			u, err := AdminByCtx(ctx)
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr == nil {
+				assert.Equal(t, tc.user, u)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 
+	"github.com/muhwyndhamhp/tigerhall-kittens/db"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/entities"
 	"gorm.io/gorm"
 )
@@ -14,7 +15,7 @@ type repo struct {
 // FindByID implements entities.UserRepository.
 func (r *repo) FindByID(ctx context.Context, id uint) (*entities.User, error) {
 	var res entities.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	err := db.Conn(ctx, r.db).Where("id = ?", id).First(&res).Error
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +25,7 @@ func (r *repo) FindByID(ctx context.Context, id uint) (*entities.User, error) {
 
 // Create implements entities.UserRepository.
 func (r *repo) Create(ctx context.Context, user *entities.User) error {
-	err := r.db.WithContext(ctx).Create(user).Error
+	err := db.Conn(ctx, r.db).Create(user).Error
 	if err != nil {
 		return err
 	}
@@ -35,7 +36,7 @@ func (r *repo) Create(ctx context.Context, user *entities.User) error {
 // FindByEmail implements entities.UserRepository.
 func (r *repo) FindByEmail(ctx context.Context, email string) (*entities.User, error) {
 	var res entities.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&res).Error
+	err := db.Conn(ctx, r.db).Where("email = ?", email).First(&res).Error
 	if err != nil {
 		return nil, err
 	}
@@ -11,6 +11,9 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/muhwyndhamhp/tigerhall-kittens/db"
 	"github.com/muhwyndhamhp/tigerhall-kittens/graph"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/export"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/importer"
+	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/reserve"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/sighting"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/tiger"
 	"github.com/muhwyndhamhp/tigerhall-kittens/pkg/modules/tokenhistory"
@@ -40,17 +43,28 @@ func main() {
 	tigerRepo := tiger.NewTigerRepository(d)
 	sightingRepo := sighting.NewSightingRepository(d)
 	tokenRepo := tokenhistory.NewTokenHistoryRepository(d)
+	reserveRepo := reserve.NewReserveRepository(d)
+	uow := db.NewUnitOfWork(d)
+	policy := sighting.NewSightingPolicy(reserveRepo, sighting.DefaultSightingRule())
 
 	userUsecase := user.NewUserUsecase(userRepo, tokenRepo)
-	tigerUsecase := tiger.NewTigerUsecase(tigerRepo, sightingRepo, s3)
-	sightingUsecase := sighting.NewSightingUsecase(sightingRepo, tigerRepo, userRepo, s3, queue)
+	tigerUsecase := tiger.NewTigerUsecase(tigerRepo, sightingRepo, reserveRepo, uow, policy, s3)
+	sightingUsecase := sighting.NewSightingUsecase(sightingRepo, tigerRepo, userRepo, uow, policy, s3, queue)
+	reserveUsecase := reserve.NewReserveUsecase(reserveRepo)
+	importUsecase := importer.NewImportUsecase(tigerUsecase, sightingUsecase, uow)
+	exportUsecase := export.NewExportUsecase(tigerRepo, sightingRepo)
 
-	resolver := graph.NewResolver(userUsecase, tigerUsecase, sightingUsecase)
+	resolver := graph.NewResolver(userUsecase, tigerUsecase, sightingUsecase, reserveUsecase, importUsecase)
 	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
 
 	e.Use(user.AuthMiddleware(userRepo, tokenRepo))
 	e.GET("/graphiql", echo.WrapHandler(playground.Handler("GraphQL playground", "/query")))
 	e.POST("/query", echo.WrapHandler(srv))
+	e.GET("/export/tigers.geojson", export.TigersGeoJSON(exportUsecase))
+	e.GET("/export/tigers/:id/track.geojson", export.TrackGeoJSON(exportUsecase))
+	e.GET("/export/tigers/:id/track.gpx", export.TrackGPX(exportUsecase))
+	e.GET("/export/tigers/:id/track.kml", export.TrackKML(exportUsecase))
+	e.GET("/export/sightings.csv", export.SightingsCSV(exportUsecase))
 	e.GET("/altair", ServeAltair)
 	e.GET("/", func(c echo.Context) error { return c.Redirect(http.StatusMovedPermanently, "/altair") })
 
@@ -8,18 +8,26 @@ import (
 )
 
 const (
-	PORT                    = "PORT"
-	ENV_FILE                = ".env"
-	LIBSQL_URL              = "LIBSQL_URL"
-	LIBSQL_TOKEN            = "LIBSQL_TOKEN"
-	JWT_SECRET              = "JWT_SECRET"
-	JWT_EXPIRY_DURATION     = "JWT_EXPIRY_DURATION"
-	CF_ACCOUNT_ID           = "CF_ACCOUNT_ID"
-	CF_R2_ACCESS_KEY_ID     = "CF_R2_ACCESS_KEY_ID"
-	CF_R2_SECRET_ACCESS_KEY = "CF_R2_SECRET_ACCESS_KEY"
-	SENDGRID_API_KEY        = "SENDGRID_API_KEY"
-	SENDGRID_SENDER_EMAIL   = "SENDGRID_SENDER_EMAIL"
-	BASE_URL                = "BASE_URL"
+	PORT                          = "PORT"
+	ENV_FILE                      = ".env"
+	LIBSQL_URL                    = "LIBSQL_URL"
+	LIBSQL_TOKEN                  = "LIBSQL_TOKEN"
+	JWT_SECRET                    = "JWT_SECRET"
+	JWT_EXPIRY_DURATION           = "JWT_EXPIRY_DURATION"
+	CF_ACCOUNT_ID                 = "CF_ACCOUNT_ID"
+	CF_R2_ACCESS_KEY_ID           = "CF_R2_ACCESS_KEY_ID"
+	CF_R2_SECRET_ACCESS_KEY       = "CF_R2_SECRET_ACCESS_KEY"
+	SENDGRID_API_KEY              = "SENDGRID_API_KEY"
+	SENDGRID_SENDER_EMAIL         = "SENDGRID_SENDER_EMAIL"
+	BASE_URL                      = "BASE_URL"
+	SIGHTING_MIN_DISTANCE_KM      = "SIGHTING_MIN_DISTANCE_KM"
+	SIGHTING_NEARBY_AFTER_HOURS   = "SIGHTING_NEARBY_AFTER_HOURS"
+	SIGHTING_MAX_SPEED_KMH        = "SIGHTING_MAX_SPEED_KMH"
+	SIGHTING_FLAG_IMPLAUSIBLE     = "SIGHTING_FLAG_IMPLAUSIBLE"
+	SIGHTING_FLAG_DUPLICATE_IMAGE = "SIGHTING_FLAG_DUPLICATE_IMAGE"
+	SIGHTING_EXIF_MAX_DISTANCE_KM = "SIGHTING_EXIF_MAX_DISTANCE_KM"
+	SIGHTING_EXIF_MAX_HOURS       = "SIGHTING_EXIF_MAX_HOURS"
+	IMAGE_STRIP_METADATA          = "IMAGE_STRIP_METADATA"
 )
 
 func init() {
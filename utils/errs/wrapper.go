@@ -9,22 +9,48 @@ import (
 type ServiceError struct {
 	ErrorCode string
 	Err       error
+	// extensions holds extra details that will be added to `errors.extensions` next to the code.
+	// It is kept behind a pointer so ServiceError stays comparable with `==`.
+	extensions *map[string]interface{}
 }
 
 func (e ServiceError) Error() string {
 	return e.Err.Error()
 }
 
+// Is matches service errors by their code, so errors carrying extra details still
+// match the sentinel they were created from.
+func (e ServiceError) Is(target error) bool {
+	t, ok := target.(ServiceError)
+	return ok && t.ErrorCode == e.ErrorCode
+}
+
+// WithDetails returns a copy of the error with a new message and extra extensions.
+func (e ServiceError) WithDetails(err error, extensions map[string]interface{}) ServiceError {
+	return ServiceError{
+		ErrorCode:  e.ErrorCode,
+		Err:        err,
+		extensions: &extensions,
+	}
+}
+
 func RespError(err error) error {
 	errList := gqlerror.List{}
 
 	var serviceErr ServiceError
 	if errors.As(err, &serviceErr) {
+		ext := map[string]interface{}{
+			"code": serviceErr.ErrorCode,
+		}
+		if serviceErr.extensions != nil {
+			for k, v := range *serviceErr.extensions {
+				ext[k] = v
+			}
+		}
+
 		errList = append(errList, &gqlerror.Error{
-			Message: serviceErr.Error(),
-			Extensions: map[string]interface{}{
-				"code": serviceErr.ErrorCode,
-			},
+			Message:    serviceErr.Error(),
+			Extensions: ext,
 		})
 	} else {
 		errList = append(errList, &gqlerror.Error{
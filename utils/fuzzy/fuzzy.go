@@ -0,0 +1,151 @@
+// Package fuzzy matches short search queries against names, tolerating different
+// case, partial input and typos.
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scores of the kinds of matches, lower is better. Typos add their edit distance to
+// ScoreTypo.
+const (
+	ScoreExact = iota
+	ScorePrefix
+	ScoreWordPrefix
+	ScoreSubstring
+	ScoreTypo
+)
+
+// Match reports whether query matches name and how well. The query matches when it
+// equals the name, is a prefix of the name or of one of its words, is contained in
+// it, or is within a few typos of the start of the name or of one of its words.
+// Case, accents and surrounding spaces are ignored.
+func Match(query, name string) (score int, ok bool) {
+	q, n := normalize(query), normalize(name)
+	if q == "" || n == "" {
+		return 0, false
+	}
+
+	switch {
+	case q == n:
+		return ScoreExact, true
+	case strings.HasPrefix(n, q):
+		return ScorePrefix, true
+	}
+
+	words := strings.Fields(n)
+	for _, w := range words[1:] {
+		if strings.HasPrefix(w, q) {
+			return ScoreWordPrefix, true
+		}
+	}
+
+	if strings.Contains(n, q) {
+		return ScoreSubstring, true
+	}
+
+	maxTypos := MaxTypos(q)
+	if maxTypos == 0 {
+		return 0, false
+	}
+
+	best := maxTypos + 1
+	for _, w := range append([]string{n}, words...) {
+		if d := prefixDistance(q, w); d < best {
+			best = d
+		}
+	}
+
+	if best > maxTypos {
+		return 0, false
+	}
+
+	return ScoreTypo + best, true
+}
+
+// MaxTypos returns how many typos are tolerated in query. Short queries have to
+// match exactly, otherwise almost every name would match them.
+func MaxTypos(query string) int {
+	switch n := len([]rune(query)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// normalize lowercases s, strips accents and collapses whitespace.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(fold(r))
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// fold maps the common accented latin letters to their base letter.
+func fold(r rune) rune {
+	switch {
+	case strings.ContainsRune("àáâãäå", r):
+		return 'a'
+	case strings.ContainsRune("èéêë", r):
+		return 'e'
+	case strings.ContainsRune("ìíîï", r):
+		return 'i'
+	case strings.ContainsRune("òóôõö", r):
+		return 'o'
+	case strings.ContainsRune("ùúûü", r):
+		return 'u'
+	case r == 'ñ':
+		return 'n'
+	case r == 'ç':
+		return 'c'
+	}
+
+	return r
+}
+
+// prefixDistance returns the smallest optimal string alignment distance between a
+// and any prefix of b, so incomplete input is not counted as typos.
+func prefixDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// rows i-2, i-1 and i of the distance matrix
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	best := prev[0]
+	for _, d := range prev[1:] {
+		best = min(best, d)
+	}
+
+	return best
+}
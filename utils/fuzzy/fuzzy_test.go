@@ -0,0 +1,112 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		query     string
+		candidate string
+
+		wantScore int
+		wantOK    bool
+	}{
+		{
+			name:      "should match the same name ignoring case and spaces",
+			query:     " raja  ",
+			candidate: "Raja",
+			wantScore: ScoreExact,
+			wantOK:    true,
+		},
+		{
+			name:      "should match the start of the name",
+			query:     "raj",
+			candidate: "Raja Besar",
+			wantScore: ScorePrefix,
+			wantOK:    true,
+		},
+		{
+			name:      "should match the start of a later word",
+			query:     "bes",
+			candidate: "Raja Besar",
+			wantScore: ScoreWordPrefix,
+			wantOK:    true,
+		},
+		{
+			name:      "should match a part of the name",
+			query:     "esa",
+			candidate: "Raja Besar",
+			wantScore: ScoreSubstring,
+			wantOK:    true,
+		},
+		{
+			name:      "should match ignoring accents",
+			query:     "rene",
+			candidate: "René",
+			wantScore: ScoreExact,
+			wantOK:    true,
+		},
+		{
+			name:      "should match with a swapped letter",
+			query:     "sheer khan",
+			candidate: "Shere Khan",
+			wantScore: ScoreTypo + 1,
+			wantOK:    true,
+		},
+		{
+			name:      "should match an incomplete word with a typo",
+			query:     "bessa",
+			candidate: "Raja Besar",
+			wantScore: ScoreTypo + 1,
+			wantOK:    true,
+		},
+		{
+			name:      "should match a long name with two typos",
+			query:     "machlli teh queen",
+			candidate: "Machali the Queen",
+			wantScore: ScoreTypo + 2,
+			wantOK:    true,
+		},
+		{
+			name:      "should match a four letter query with a typo",
+			query:     "rjaa",
+			candidate: "Raja",
+			wantScore: ScoreTypo + 1,
+			wantOK:    true,
+		},
+		{
+			name:      "should not match a three letter query with a typo",
+			query:     "rjz",
+			candidate: "Raja",
+			wantOK:    false,
+		},
+		{
+			name:      "should not match a different name",
+			query:     "sultan",
+			candidate: "Raja Besar",
+			wantOK:    false,
+		},
+		{
+			name:      "should not match an empty query",
+			query:     " ",
+			candidate: "Raja",
+			wantOK:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			score, ok := Match(tc.query, tc.candidate)
+
+			assert.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				assert.Equal(t, tc.wantScore, score)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// FeatureCollection is a GeoJSON FeatureCollection object, see RFC 7946.
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature object.
+type Feature struct {
+	Type       string                 `json:"type"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry. Coordinates are in [longitude, latitude] order as
+// GeoJSON requires.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+func NewFeatureCollection(features ...*Feature) *FeatureCollection {
+	if features == nil {
+		features = []*Feature{}
+	}
+
+	return &FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
+
+func NewFeature(geometry *Geometry, properties map[string]interface{}) *Feature {
+	return &Feature{
+		Type:       "Feature",
+		Geometry:   geometry,
+		Properties: properties,
+	}
+}
+
+func NewPoint(latitude, longitude float64) *Geometry {
+	return &Geometry{
+		Type:        "Point",
+		Coordinates: []float64{longitude, latitude},
+	}
+}
+
+// NewLineString returns a LineString through the given [latitude, longitude] pairs.
+func NewLineString(positions [][2]float64) *Geometry {
+	coords := make([][]float64, len(positions))
+	for i, p := range positions {
+		coords[i] = []float64{p[1], p[0]}
+	}
+
+	return &Geometry{
+		Type:        "LineString",
+		Coordinates: coords,
+	}
+}
+
+// NewPolygon returns a Polygon from closed rings of [latitude, longitude] pairs, the
+// first ring being the outer boundary and the others holes.
+func NewPolygon(rings [][][2]float64) *Geometry {
+	return &Geometry{
+		Type:        "Polygon",
+		Coordinates: polygonCoordinates(rings),
+	}
+}
+
+// NewMultiPolygon returns a MultiPolygon from polygons given as in NewPolygon.
+func NewMultiPolygon(polygons [][][][2]float64) *Geometry {
+	coords := make([][][][]float64, len(polygons))
+	for i, rings := range polygons {
+		coords[i] = polygonCoordinates(rings)
+	}
+
+	return &Geometry{
+		Type:        "MultiPolygon",
+		Coordinates: coords,
+	}
+}
+
+func polygonCoordinates(rings [][][2]float64) [][][]float64 {
+	coords := make([][][]float64, len(rings))
+	for i, ring := range rings {
+		coords[i] = make([][]float64, len(ring))
+		for j, p := range ring {
+			coords[i][j] = []float64{p[1], p[0]}
+		}
+	}
+
+	return coords
+}
+
+// MarshalGQL writes the geometry as a JSON object for the GeoJSON GraphQL scalar.
+func (g Geometry) MarshalGQL(w io.Writer) {
+	b, err := json.Marshal(g)
+	if err != nil {
+		b = []byte("null")
+	}
+
+	_, _ = w.Write(b)
+}
+
+// UnmarshalGQL implements graphql.Unmarshaler, geometries are only returned by the API.
+func (g *Geometry) UnmarshalGQL(v interface{}) error {
+	return errors.New("GeoJSON is not supported as input")
+}
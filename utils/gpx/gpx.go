@@ -0,0 +1,74 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const namespace = "http://www.topografix.com/GPX/1/1"
+
+// GPX is the root element of a GPX 1.1 document. Elements are declared in the
+// order the schema requires, handheld devices tend to be strict about it.
+type GPX struct {
+	XMLName   xml.Name   `xml:"gpx"`
+	Version   string     `xml:"version,attr"`
+	Creator   string     `xml:"creator,attr"`
+	Xmlns     string     `xml:"xmlns,attr"`
+	Metadata  *Metadata  `xml:"metadata,omitempty"`
+	Waypoints []Waypoint `xml:"wpt"`
+	Tracks    []Track    `xml:"trk"`
+}
+
+type Metadata struct {
+	Name string     `xml:"name,omitempty"`
+	Time *time.Time `xml:"time,omitempty"`
+}
+
+// Waypoint is used both for standalone waypoints and for the points of a track segment.
+type Waypoint struct {
+	Lat   float64    `xml:"lat,attr"`
+	Lon   float64    `xml:"lon,attr"`
+	Time  *time.Time `xml:"time,omitempty"`
+	Name  string     `xml:"name,omitempty"`
+	Desc  string     `xml:"desc,omitempty"`
+	Links []Link     `xml:"link"`
+}
+
+type Link struct {
+	Href string `xml:"href,attr"`
+	Text string `xml:"text,omitempty"`
+}
+
+type Track struct {
+	Name     string    `xml:"name,omitempty"`
+	Segments []Segment `xml:"trkseg"`
+}
+
+type Segment struct {
+	Points []Waypoint `xml:"trkpt"`
+}
+
+func New(name string, waypoints []Waypoint, tracks ...Track) *GPX {
+	now := time.Now().UTC()
+
+	return &GPX{
+		Version:   "1.1",
+		Creator:   "tigerhall-kittens",
+		Xmlns:     namespace,
+		Metadata:  &Metadata{Name: name, Time: &now},
+		Waypoints: waypoints,
+		Tracks:    tracks,
+	}
+}
+
+// NewWaypoint returns a waypoint at the given position, the time is stored in UTC
+// as the GPX schema expects.
+func NewWaypoint(latitude, longitude float64, t time.Time) Waypoint {
+	t = t.UTC()
+
+	return Waypoint{
+		Lat:  latitude,
+		Lon:  longitude,
+		Time: &t,
+	}
+}
@@ -0,0 +1,219 @@
+package homerange
+
+import (
+	"math"
+	"sort"
+)
+
+type grid struct {
+	nx, ny  int
+	density []float64
+}
+
+func newGrid(nx, ny int) *grid {
+	return &grid{nx: nx, ny: ny, density: make([]float64, nx*ny)}
+}
+
+// highest marks the densest cells that together hold the given share of the density.
+func (g *grid) highest(level float64) []bool {
+	order := make([]int, len(g.density))
+	var total float64
+	for i, d := range g.density {
+		order[i] = i
+		total += d
+	}
+
+	sort.Slice(order, func(a, b int) bool {
+		return g.density[order[a]] > g.density[order[b]]
+	})
+
+	inside := make([]bool, len(g.density))
+	var sum float64
+	for _, i := range order {
+		if sum >= level*total {
+			break
+		}
+		inside[i] = true
+		sum += g.density[i]
+	}
+
+	return inside
+}
+
+func (g *grid) in(inside []bool, i, j int) bool {
+	return i >= 0 && j >= 0 && i < g.nx && j < g.ny && inside[j*g.nx+i]
+}
+
+// edge is a side of a cell, between two grid corners.
+type edge struct {
+	from, to [2]int
+}
+
+// trace returns the boundaries of the regions of inside cells as polygons of closed
+// rings in grid corner coordinates. Boundaries keep inside cells on their left, so
+// outer rings are counter-clockwise and holes clockwise.
+func (g *grid) trace(inside []bool) [][][][2]int {
+	out := map[[2]int][]edge{}
+	count := 0
+	for j := 0; j < g.ny; j++ {
+		for i := 0; i < g.nx; i++ {
+			if !g.in(inside, i, j) {
+				continue
+			}
+
+			var edges []edge
+			if !g.in(inside, i, j-1) {
+				edges = append(edges, edge{[2]int{i, j}, [2]int{i + 1, j}})
+			}
+			if !g.in(inside, i+1, j) {
+				edges = append(edges, edge{[2]int{i + 1, j}, [2]int{i + 1, j + 1}})
+			}
+			if !g.in(inside, i, j+1) {
+				edges = append(edges, edge{[2]int{i + 1, j + 1}, [2]int{i, j + 1}})
+			}
+			if !g.in(inside, i-1, j) {
+				edges = append(edges, edge{[2]int{i, j + 1}, [2]int{i, j}})
+			}
+
+			for _, e := range edges {
+				out[e.from] = append(out[e.from], e)
+				count++
+			}
+		}
+	}
+
+	// start from the lowest corners so the output does not depend on map order
+	starts := make([][2]int, 0, len(out))
+	for c := range out {
+		starts = append(starts, c)
+	}
+	sort.Slice(starts, func(a, b int) bool {
+		if starts[a][1] != starts[b][1] {
+			return starts[a][1] < starts[b][1]
+		}
+		return starts[a][0] < starts[b][0]
+	})
+
+	var outers, holes [][][2]int
+	for _, s := range starts {
+		for len(out[s]) > 0 {
+			ring := follow(out, s)
+			if ringArea(ring) > 0 {
+				outers = append(outers, ring)
+			} else {
+				holes = append(holes, ring)
+			}
+		}
+	}
+
+	polygons := make([][][][2]int, len(outers))
+	for i, o := range outers {
+		polygons[i] = [][][2]int{o}
+	}
+
+	// a hole belongs to the smallest outer ring around it
+	for _, h := range holes {
+		best := -1
+		for i, o := range outers {
+			if contains(o, h[0]) && (best < 0 || ringArea(o) < ringArea(outers[best])) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			polygons[best] = append(polygons[best], h)
+		}
+	}
+
+	return polygons
+}
+
+// follow walks the unused edges from start until it is back at start and returns the
+// corners where the walk changes direction as a closed ring. Where two regions touch
+// at a corner it turns left, keeping them apart.
+func follow(out map[[2]int][]edge, start [2]int) [][2]int {
+	ring := [][2]int{start}
+	at := start
+	var dir [2]int
+
+	for {
+		edges := out[at]
+		pick := 0
+		if len(edges) > 1 {
+			best := math.Inf(-1)
+			for k, e := range edges {
+				d := [2]int{e.to[0] - e.from[0], e.to[1] - e.from[1]}
+				// cross product of the current and the next direction, positive is left
+				if turn := float64(dir[0]*d[1] - dir[1]*d[0]); turn > best {
+					best, pick = turn, k
+				}
+			}
+		}
+
+		e := edges[pick]
+		out[at] = append(edges[:pick:pick], edges[pick+1:]...)
+		if len(out[at]) == 0 {
+			delete(out, at)
+		}
+
+		d := [2]int{e.to[0] - e.from[0], e.to[1] - e.from[1]}
+		if d == dir {
+			ring = ring[:len(ring)-1]
+		}
+		ring = append(ring, e.to)
+		dir = d
+		at = e.to
+
+		if at == start {
+			break
+		}
+	}
+
+	// the ring may have started in the middle of a straight side
+	if len(ring) > 3 {
+		first := [2]int{ring[1][0] - ring[0][0], ring[1][1] - ring[0][1]}
+		if sameDirection(first, dir) {
+			ring = append(ring[1:len(ring)-1], ring[1])
+		}
+	}
+
+	return ring
+}
+
+func sameDirection(a, b [2]int) bool {
+	return sign(a[0]) == sign(b[0]) && sign(a[1]) == sign(b[1])
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// ringArea is the signed area of a closed ring, positive for counter-clockwise rings.
+func ringArea(ring [][2]int) float64 {
+	var area int
+	for i := 0; i < len(ring)-1; i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return float64(area) / 2
+}
+
+// contains reports whether corner c is inside the closed ring, by ray casting from
+// the middle of the cell above and right of c so it never lies on the ring.
+func contains(ring [][2]int, c [2]int) bool {
+	x, y := float64(c[0])+0.5, float64(c[1])+0.5
+	in := false
+	for i := 0; i < len(ring)-1; i++ {
+		ax, ay := float64(ring[i][0]), float64(ring[i][1])
+		bx, by := float64(ring[i+1][0]), float64(ring[i+1][1])
+		if (ay > y) != (by > y) && x < ax+(y-ay)*(bx-ax)/(by-ay) {
+			in = !in
+		}
+	}
+	return in
+}
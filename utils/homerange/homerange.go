@@ -0,0 +1,247 @@
+// Package homerange estimates the area an animal lives in from the positions it was
+// seen at. Positions are projected onto a local plane in kilometers around their
+// centroid, which is accurate enough for the tens of kilometers a home range spans.
+package homerange
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// kmPerDegree is the length of a degree of latitude, and of longitude at the equator.
+const kmPerDegree = 111.32
+
+// gridSize is the number of cells the longest side of the KDE grid is split into.
+const gridSize = 100
+
+// kernelRadius is how many bandwidths away from a position its kernel is still summed.
+const kernelRadius = 4
+
+var ErrTooFewPoints = errors.New("at least 3 positions that are not on a single line are needed")
+
+// Point is a position as a [latitude, longitude] pair.
+type Point = [2]float64
+
+type vec struct {
+	x, y float64
+}
+
+type projection struct {
+	lat0, lng0, cosLat0 float64
+}
+
+// newProjection centers the projection on the centroid of points, taking care of
+// positions on both sides of the antimeridian.
+func newProjection(points []Point) projection {
+	var lat, dLng float64
+	for _, p := range points {
+		lat += p[0]
+		dLng += wrap(p[1] - points[0][1])
+	}
+
+	n := float64(len(points))
+	lat0 := lat / n
+
+	return projection{
+		lat0:    lat0,
+		lng0:    wrap(points[0][1] + dLng/n),
+		cosLat0: math.Cos(lat0 * math.Pi / 180),
+	}
+}
+
+func (p projection) forward(pt Point) vec {
+	return vec{
+		x: wrap(pt[1]-p.lng0) * p.cosLat0 * kmPerDegree,
+		y: (pt[0] - p.lat0) * kmPerDegree,
+	}
+}
+
+func (p projection) inverse(v vec) Point {
+	return Point{
+		p.lat0 + v.y/kmPerDegree,
+		wrap(p.lng0 + v.x/(p.cosLat0*kmPerDegree)),
+	}
+}
+
+// wrap normalizes a longitude or a longitude difference to [-180, 180].
+func wrap(lng float64) float64 {
+	for lng > 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
+
+// MCP returns the minimum convex polygon around points as a closed counter-clockwise
+// ring, together with its area in km².
+func MCP(points []Point) ([]Point, float64, error) {
+	if len(points) < 3 {
+		return nil, 0, ErrTooFewPoints
+	}
+
+	proj := newProjection(points)
+	vs := make([]vec, len(points))
+	for i, p := range points {
+		vs[i] = proj.forward(p)
+	}
+
+	hull := convexHull(vs)
+	area := signedArea(hull)
+	if len(hull) < 3 || area <= 0 {
+		return nil, 0, ErrTooFewPoints
+	}
+
+	ring := make([]Point, len(hull)+1)
+	for i, v := range hull {
+		ring[i] = proj.inverse(v)
+	}
+	ring[len(hull)] = ring[0]
+
+	return ring, area, nil
+}
+
+// convexHull returns the convex hull of vs in counter-clockwise order using Andrew's
+// monotone chain, without repeating the first vertex.
+func convexHull(vs []vec) []vec {
+	sorted := append([]vec(nil), vs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].x != sorted[j].x {
+			return sorted[i].x < sorted[j].x
+		}
+		return sorted[i].y < sorted[j].y
+	})
+
+	hull := make([]vec, 0, 2*len(sorted))
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, v := range sorted {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], v) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, v)
+		}
+
+		// the last vertex of a chain is the first of the other one
+		hull = hull[:len(hull)-1]
+
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+	}
+
+	return hull
+}
+
+func cross(o, a, b vec) float64 {
+	return (a.x-o.x)*(b.y-o.y) - (a.y-o.y)*(b.x-o.x)
+}
+
+// signedArea is positive for counter-clockwise rings.
+func signedArea(ring []vec) float64 {
+	var area float64
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
+
+// KDE estimates a Gaussian kernel density of points with the reference bandwidth and
+// returns the contour of the smallest area holding the given share of it, e.g. 0.95.
+// The contour is a list of polygons, each a list of closed rings where the first ring
+// is the counter-clockwise outer boundary and the others are clockwise holes. The area
+// of the contour is returned in km².
+func KDE(points []Point, level float64) ([][][]Point, float64, error) {
+	if len(points) < 3 {
+		return nil, 0, ErrTooFewPoints
+	}
+
+	proj := newProjection(points)
+	vs := make([]vec, len(points))
+	for i, p := range points {
+		vs[i] = proj.forward(p)
+	}
+
+	h := bandwidth(vs)
+	if h == 0 {
+		return nil, 0, ErrTooFewPoints
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, v := range vs {
+		minX, maxX = math.Min(minX, v.x), math.Max(maxX, v.x)
+		minY, maxY = math.Min(minY, v.y), math.Max(maxY, v.y)
+	}
+
+	pad := kernelRadius * h
+	minX, minY = minX-pad, minY-pad
+	maxX, maxY = maxX+pad, maxY+pad
+
+	cell := math.Max(maxX-minX, maxY-minY) / gridSize
+	g := newGrid(int(math.Ceil((maxX-minX)/cell)), int(math.Ceil((maxY-minY)/cell)))
+
+	// only cells within kernelRadius bandwidths of a position are worth summing
+	r := int(math.Ceil(kernelRadius * h / cell))
+	for _, v := range vs {
+		ci, cj := int((v.x-minX)/cell), int((v.y-minY)/cell)
+		for j := max(cj-r, 0); j <= min(cj+r, g.ny-1); j++ {
+			for i := max(ci-r, 0); i <= min(ci+r, g.nx-1); i++ {
+				dx := minX + (float64(i)+0.5)*cell - v.x
+				dy := minY + (float64(j)+0.5)*cell - v.y
+				g.density[j*g.nx+i] += math.Exp(-(dx*dx + dy*dy) / (2 * h * h))
+			}
+		}
+	}
+
+	inside := g.highest(level)
+
+	var area float64
+	for _, in := range inside {
+		if in {
+			area += cell * cell
+		}
+	}
+
+	toPoint := func(c [2]int) Point {
+		return proj.inverse(vec{minX + float64(c[0])*cell, minY + float64(c[1])*cell})
+	}
+
+	var polygons [][][]Point
+	for _, rings := range g.trace(inside) {
+		polygon := make([][]Point, len(rings))
+		for i, ring := range rings {
+			polygon[i] = make([]Point, len(ring))
+			for k, c := range ring {
+				polygon[i][k] = toPoint(c)
+			}
+		}
+		polygons = append(polygons, polygon)
+	}
+
+	return polygons, area, nil
+}
+
+// bandwidth is the reference bandwidth, the usual default for home ranges.
+func bandwidth(vs []vec) float64 {
+	var mx, my float64
+	for _, v := range vs {
+		mx += v.x
+		my += v.y
+	}
+
+	n := float64(len(vs))
+	mx, my = mx/n, my/n
+
+	var sx, sy float64
+	for _, v := range vs {
+		sx += (v.x - mx) * (v.x - mx)
+		sy += (v.y - my) * (v.y - my)
+	}
+	sx, sy = sx/(n-1), sy/(n-1)
+
+	return math.Sqrt((sx+sy)/2) * math.Pow(n, -1.0/6)
+}
@@ -0,0 +1,162 @@
+package homerange
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// square returns the corners of a square of about side km centered on lat, lng, plus
+// its center.
+func square(lat, lng, side float64) []Point {
+	d := side / 2 / kmPerDegree
+	return []Point{
+		{lat - d, lng - d},
+		{lat - d, lng + d},
+		{lat + d, lng + d},
+		{lat + d, lng - d},
+		{lat, lng},
+	}
+}
+
+func TestMCP(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		points []Point
+
+		wantVertices int
+		wantArea     float64
+		wantErr      error
+	}{
+		{
+			name:         "should return the corners of a square and its area",
+			points:       square(0, 110, 10),
+			wantVertices: 5,
+			wantArea:     100,
+		},
+		{
+			name:         "should handle positions on both sides of the antimeridian",
+			points:       square(0, 180, 10),
+			wantVertices: 5,
+			wantArea:     100,
+		},
+		{
+			name:    "should return ErrTooFewPoints given two positions",
+			points:  []Point{{0, 110}, {0.1, 110}},
+			wantErr: ErrTooFewPoints,
+		},
+		{
+			name:    "should return ErrTooFewPoints given positions on a line",
+			points:  []Point{{0, 110}, {0.1, 110}, {0.2, 110}},
+			wantErr: ErrTooFewPoints,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ring, area, err := MCP(tc.points)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Len(t, ring, tc.wantVertices)
+			assert.InDelta(t, tc.wantArea, area, 0.5)
+			if len(ring) > 0 {
+				assert.Equal(t, ring[0], ring[len(ring)-1])
+				for _, p := range ring {
+					assert.True(t, p[1] >= -180 && p[1] <= 180)
+				}
+			}
+		})
+	}
+}
+
+func TestKDE(t *testing.T) {
+	near := square(-7.5, 110.8, 4)
+
+	testCases := []struct {
+		name string
+
+		points []Point
+		level  float64
+
+		wantPolygons int
+		wantErr      error
+	}{
+		{
+			name:         "should return a single contour around a cluster",
+			points:       near,
+			level:        0.95,
+			wantPolygons: 1,
+		},
+		{
+			name:         "should handle positions on both sides of the antimeridian",
+			points:       square(0, 180, 4),
+			level:        0.95,
+			wantPolygons: 1,
+		},
+		{
+			name:    "should return ErrTooFewPoints given two positions",
+			points:  near[:2],
+			level:   0.95,
+			wantErr: ErrTooFewPoints,
+		},
+		{
+			name:    "should return ErrTooFewPoints given the same position",
+			points:  []Point{{0, 110}, {0, 110}, {0, 110}},
+			level:   0.95,
+			wantErr: ErrTooFewPoints,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			polygons, area, err := KDE(tc.points, tc.level)
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Len(t, polygons, tc.wantPolygons)
+			if err != nil {
+				return
+			}
+
+			assert.Greater(t, area, 0.0)
+			for _, polygon := range polygons {
+				outer := polygon[0]
+				assert.Equal(t, outer[0], outer[len(outer)-1])
+				for _, p := range outer {
+					assert.True(t, p[1] >= -180 && p[1] <= 180)
+				}
+			}
+
+			// a lower level covers a smaller area
+			_, smaller, err := KDE(tc.points, tc.level/2)
+			assert.Nil(t, err)
+			assert.Less(t, smaller, area)
+		})
+	}
+}
+
+func TestGrid_Trace(t *testing.T) {
+	// a ring of cells around a hole, and a cell touching it at a corner
+	//
+	//	. . . . #
+	//	. # # # .
+	//	. # . # .
+	//	. # # # .
+	g := newGrid(5, 4)
+	inside := make([]bool, 20)
+	for _, c := range [][2]int{{1, 0}, {2, 0}, {3, 0}, {1, 1}, {3, 1}, {1, 2}, {2, 2}, {3, 2}, {4, 3}} {
+		inside[c[1]*g.nx+c[0]] = true
+	}
+
+	polygons := g.trace(inside)
+
+	assert.Equal(t, [][][][2]int{
+		{
+			{{1, 0}, {4, 0}, {4, 3}, {1, 3}, {1, 0}},
+			{{2, 1}, {2, 2}, {3, 2}, {3, 1}, {2, 1}},
+		},
+		{
+			{{4, 3}, {5, 3}, {5, 4}, {4, 4}, {4, 3}},
+		},
+	}, polygons)
+}
@@ -0,0 +1,320 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// EXIF tags read by ReadExif and StripMetadata.
+const (
+	tagOrientation        = 0x0112
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagMakerNote          = 0x927c
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+	tagGPSTimeStamp       = 0x0007
+	tagGPSDateStamp       = 0x001d
+)
+
+// exifDateLayout is the layout of the dates in EXIF data.
+const exifDateLayout = "2006:01:02 15:04:05"
+
+var ErrInvalidExif = errors.New("invalid exif data")
+
+// Exif is the part of the EXIF data of a photo that describes where and when it was
+// taken. Fields the photo does not have are nil.
+type Exif struct {
+	Latitude  *float64
+	Longitude *float64
+	// Date is when the photo was taken. Without a time zone in the EXIF data it is the
+	// GPS time, or else the camera time read as UTC.
+	Date *time.Time
+}
+
+// HasPosition reports whether both latitude and longitude are known.
+func (e *Exif) HasPosition() bool {
+	return e != nil && e.Latitude != nil && e.Longitude != nil
+}
+
+// ReadExif reads the position and capture time from the EXIF data of the JPEG image
+// in f. It returns an empty Exif for images without EXIF data, and ErrInvalidExif
+// when the data is malformed.
+func ReadExif(f io.ReadSeeker) (*Exif, error) {
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	tiff, err := findExif(b)
+	if err != nil || tiff == nil {
+		return &Exif{}, err
+	}
+
+	return parseExif(tiff)
+}
+
+// findExif returns the TIFF structure in the APP1 segment of the JPEG in b, or nil
+// when there is none.
+func findExif(b []byte) ([]byte, error) {
+	if len(b) < 2 || b[0] != 0xff || b[1] != 0xd8 {
+		return nil, nil
+	}
+
+	for i := 2; i+4 <= len(b); {
+		if b[i] != 0xff {
+			return nil, ErrInvalidExif
+		}
+
+		marker := b[i+1]
+		// markers without a length
+		if marker == 0xd8 || marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7) {
+			i += 2
+			continue
+		}
+
+		// the image data starts, metadata only comes before it
+		if marker == 0xda || marker == 0xd9 {
+			return nil, nil
+		}
+
+		size := int(binary.BigEndian.Uint16(b[i+2:]))
+		if size < 2 || i+2+size > len(b) {
+			return nil, ErrInvalidExif
+		}
+
+		data := b[i+4 : i+2+size]
+		if marker == 0xe1 && bytes.HasPrefix(data, []byte("Exif\x00\x00")) {
+			return data[6:], nil
+		}
+
+		i += 2 + size
+	}
+
+	return nil, nil
+}
+
+// ifdEntry is a field of an image file directory, with its value still encoded.
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+type tiffReader struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+// parseExif reads the fields of Exif from a TIFF structure.
+func parseExif(b []byte) (*Exif, error) {
+	if len(b) < 8 {
+		return nil, ErrInvalidExif
+	}
+
+	r := tiffReader{b: b}
+	switch string(b[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil, ErrInvalidExif
+	}
+
+	if r.order.Uint16(b[2:]) != 42 {
+		return nil, ErrInvalidExif
+	}
+
+	ifd0, err := r.readIFD(r.order.Uint32(b[4:]))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Exif{}
+	zoned := false
+
+	if e, ok := ifd0[tagExifIFD]; ok {
+		exif, err := r.readIFD(r.integer(e))
+		if err != nil {
+			return nil, err
+		}
+
+		if d, ok := exif[tagDateTimeOriginal]; ok {
+			loc := time.UTC
+			if o, ok := exif[tagOffsetTimeOriginal]; ok {
+				if t, err := time.Parse("-07:00", ascii(o)); err == nil {
+					loc = t.Location()
+					zoned = true
+				}
+			}
+
+			if t, err := time.ParseInLocation(exifDateLayout, ascii(d), loc); err == nil {
+				res.Date = &t
+			}
+		}
+	}
+
+	if e, ok := ifd0[tagGPSIFD]; ok {
+		gps, err := r.readIFD(r.integer(e))
+		if err != nil {
+			return nil, err
+		}
+
+		res.Latitude = r.coordinate(gps[tagGPSLatitude], gps[tagGPSLatitudeRef], "S")
+		res.Longitude = r.coordinate(gps[tagGPSLongitude], gps[tagGPSLongitudeRef], "W")
+
+		// GPS time is always UTC, which is more reliable than a camera clock without a
+		// time zone
+		if !zoned {
+			if t := r.gpsTime(gps[tagGPSDateStamp], gps[tagGPSTimeStamp]); t != nil {
+				res.Date = t
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// readIFD reads the entries of the image file directory at offset, by tag.
+func (r tiffReader) readIFD(offset uint32) (map[uint16]ifdEntry, error) {
+	if uint64(offset)+2 > uint64(len(r.b)) {
+		return nil, ErrInvalidExif
+	}
+
+	n := int(r.order.Uint16(r.b[offset:]))
+	start := int(offset) + 2
+	if start+n*12 > len(r.b) {
+		return nil, ErrInvalidExif
+	}
+
+	res := make(map[uint16]ifdEntry, n)
+	for i := 0; i < n; i++ {
+		e := r.b[start+i*12 : start+(i+1)*12]
+		typ := r.order.Uint16(e[2:])
+		count := r.order.Uint32(e[4:])
+
+		size := uint64(typeSize(typ)) * uint64(count)
+		if size == 0 {
+			continue
+		}
+
+		// values of up to 4 bytes are stored in the entry itself
+		var value []byte
+		if size <= 4 {
+			value = e[8 : 8+size]
+		} else {
+			o := uint64(r.order.Uint32(e[8:]))
+			if o+size > uint64(len(r.b)) {
+				return nil, ErrInvalidExif
+			}
+			value = r.b[o : o+size]
+		}
+
+		res[r.order.Uint16(e)] = ifdEntry{typ, count, value}
+	}
+
+	return res, nil
+}
+
+// typeSize returns the size in bytes of a value of a TIFF field type, or 0 for types
+// ReadExif has no use for.
+func typeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 7: // BYTE, ASCII, UNDEFINED
+		return 1
+	case 3: // SHORT
+		return 2
+	case 4, 9: // LONG, SLONG
+		return 4
+	case 5, 10: // RATIONAL, SRATIONAL
+		return 8
+	}
+
+	return 0
+}
+
+// integer returns the value of a SHORT or LONG entry.
+func (r tiffReader) integer(e ifdEntry) uint32 {
+	switch e.typ {
+	case 3:
+		return uint32(r.order.Uint16(e.value))
+	case 4:
+		return r.order.Uint32(e.value)
+	}
+
+	return 0
+}
+
+// rationals returns the values of a RATIONAL entry.
+func (r tiffReader) rationals(e ifdEntry) []float64 {
+	if e.typ != 5 {
+		return nil
+	}
+
+	res := make([]float64, e.count)
+	for i := range res {
+		num := r.order.Uint32(e.value[i*8:])
+		den := r.order.Uint32(e.value[i*8+4:])
+		if den == 0 {
+			return nil
+		}
+		res[i] = float64(num) / float64(den)
+	}
+
+	return res
+}
+
+// coordinate returns the degrees of a GPS coordinate given as degrees, minutes and
+// seconds, negative when ref is negativeRef.
+func (r tiffReader) coordinate(e, ref ifdEntry, negativeRef string) *float64 {
+	dms := r.rationals(e)
+	if len(dms) != 3 {
+		return nil
+	}
+
+	deg := dms[0] + dms[1]/60 + dms[2]/3600
+	if ascii(ref) == negativeRef {
+		deg = -deg
+	}
+
+	return &deg
+}
+
+// gpsTime returns the UTC time of a GPS date stamp and time stamp.
+func (r tiffReader) gpsTime(date, clock ifdEntry) *time.Time {
+	d, err := time.Parse("2006:01:02", ascii(date))
+	if err != nil {
+		return nil
+	}
+
+	hms := r.rationals(clock)
+	if len(hms) != 3 {
+		return nil
+	}
+
+	t := d.Add(time.Duration((hms[0]*3600 + hms[1]*60 + hms[2]) * float64(time.Second)))
+	return &t
+}
+
+// ascii returns the value of an ASCII entry without its terminating NUL.
+func ascii(e ifdEntry) string {
+	if e.typ != 2 {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+}
@@ -0,0 +1,283 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTag struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+// tiffWriter lays out a TIFF structure for tests. Directories are appended one after
+// another, so the ones others point to have to be written first.
+type tiffWriter struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+func newTIFFWriter(order binary.ByteOrder) *tiffWriter {
+	w := &tiffWriter{order: order, b: make([]byte, 8)}
+	if order == binary.LittleEndian {
+		copy(w.b, "II")
+	} else {
+		copy(w.b, "MM")
+	}
+	order.PutUint16(w.b[2:], 42)
+
+	return w
+}
+
+// ifd appends an image file directory with its values and returns its offset.
+func (w *tiffWriter) ifd(tags []testTag) uint32 {
+	off := len(w.b)
+	dataOff := off + 2 + 12*len(tags) + 4
+
+	dir := make([]byte, dataOff-off)
+	w.order.PutUint16(dir, uint16(len(tags)))
+
+	var data []byte
+	for i, t := range tags {
+		e := dir[2+12*i:]
+		w.order.PutUint16(e, t.tag)
+		w.order.PutUint16(e[2:], t.typ)
+		w.order.PutUint32(e[4:], t.count)
+
+		if len(t.data) <= 4 {
+			copy(e[8:12], t.data)
+		} else {
+			w.order.PutUint32(e[8:], uint32(dataOff+len(data)))
+			data = append(data, t.data...)
+		}
+	}
+
+	w.b = append(w.b, dir...)
+	w.b = append(w.b, data...)
+	return uint32(off)
+}
+
+// bytes returns the TIFF structure with ifd0 as its first directory.
+func (w *tiffWriter) bytes(ifd0 uint32) []byte {
+	w.order.PutUint32(w.b[4:], ifd0)
+	return w.b
+}
+
+func (w *tiffWriter) long(tag uint16, v uint32) testTag {
+	b := make([]byte, 4)
+	w.order.PutUint32(b, v)
+	return testTag{tag, 4, 1, b}
+}
+
+func (w *tiffWriter) short(tag uint16, v uint16) testTag {
+	b := make([]byte, 2)
+	w.order.PutUint16(b, v)
+	return testTag{tag, 3, 1, b}
+}
+
+func (w *tiffWriter) rationals(tag uint16, v ...[2]uint32) testTag {
+	b := make([]byte, 8*len(v))
+	for i, r := range v {
+		w.order.PutUint32(b[8*i:], r[0])
+		w.order.PutUint32(b[8*i+4:], r[1])
+	}
+	return testTag{tag, 5, uint32(len(v)), b}
+}
+
+func asciiTag(tag uint16, s string) testTag {
+	return testTag{tag, 2, uint32(len(s) + 1), append([]byte(s), 0)}
+}
+
+// testExif describes the EXIF data of a test photo, empty fields are left out.
+type testExif struct {
+	order       binary.ByteOrder
+	orientation uint16
+	date        string
+	offset      string
+	latRef      string
+	lngRef      string
+	gpsDate     string
+	gpsTime     [3]uint32
+}
+
+func (e testExif) tiff() []byte {
+	w := newTIFFWriter(e.order)
+
+	var ifd0 []testTag
+
+	if e.orientation != 0 {
+		ifd0 = append(ifd0, w.short(tagOrientation, e.orientation))
+	}
+
+	if e.date != "" {
+		exif := []testTag{asciiTag(tagDateTimeOriginal, e.date)}
+		if e.offset != "" {
+			exif = append(exif, asciiTag(tagOffsetTimeOriginal, e.offset))
+		}
+		ifd0 = append(ifd0, w.long(tagExifIFD, w.ifd(exif)))
+	}
+
+	if e.latRef != "" {
+		gps := []testTag{
+			asciiTag(tagGPSLatitudeRef, e.latRef),
+			// 7° 33' 2.4"
+			w.rationals(tagGPSLatitude, [2]uint32{7, 1}, [2]uint32{33, 1}, [2]uint32{24, 10}),
+			asciiTag(tagGPSLongitudeRef, e.lngRef),
+			// 110° 49' 41.94"
+			w.rationals(tagGPSLongitude, [2]uint32{110, 1}, [2]uint32{49, 1}, [2]uint32{4194, 100}),
+		}
+		if e.gpsDate != "" {
+			gps = append(gps,
+				asciiTag(tagGPSDateStamp, e.gpsDate),
+				w.rationals(tagGPSTimeStamp,
+					[2]uint32{e.gpsTime[0], 1}, [2]uint32{e.gpsTime[1], 1}, [2]uint32{e.gpsTime[2], 1}),
+			)
+		}
+		ifd0 = append(ifd0, w.long(tagGPSIFD, w.ifd(gps)))
+	}
+
+	return w.bytes(w.ifd(ifd0))
+}
+
+// jpegWithExif encodes img as a JPEG with tiff as its EXIF data.
+func jpegWithExif(t *testing.T, img image.Image, tiff []byte) []byte {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, nil)
+	assert.NoError(t, err)
+
+	b := buf.Bytes()
+	if tiff == nil {
+		return b
+	}
+
+	app1 := []byte{0xff, 0xe1, 0, 0}
+	app1 = append(app1, "Exif\x00\x00"...)
+	app1 = append(app1, tiff...)
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(app1)-2))
+
+	res := append([]byte{}, b[:2]...)
+	res = append(res, app1...)
+	return append(res, b[2:]...)
+}
+
+func TestReadExif(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 16, 16))
+	lat := 7.0 + 33.0/60 + 2.4/3600
+	lng := 110.0 + 49.0/60 + 41.94/3600
+	south, west := -lat, -lng
+	localDate := time.Date(2024, 3, 1, 6, 30, 0, 0, time.FixedZone("", 7*3600))
+	gpsDate := time.Date(2024, 2, 29, 23, 30, 5, 0, time.UTC)
+	cameraDate := time.Date(2024, 3, 1, 6, 30, 0, 0, time.UTC)
+
+	var pngBuf bytes.Buffer
+	err := png.Encode(&pngBuf, img)
+	assert.NoError(t, err)
+
+	truncated := jpegWithExif(t, img, testExif{order: binary.LittleEndian, date: "2024:03:01 06:30:00"}.tiff()[:12])
+
+	testCases := []struct {
+		name string
+
+		file []byte
+
+		wantLat  *float64
+		wantLng  *float64
+		wantDate *time.Time
+		wantErr  error
+	}{
+		{
+			name: "should read position and date in its time zone given little endian exif",
+			file: jpegWithExif(t, img, testExif{
+				order:   binary.LittleEndian,
+				date:    "2024:03:01 06:30:00",
+				offset:  "+07:00",
+				latRef:  "S",
+				lngRef:  "E",
+				gpsDate: "2024:02:29",
+				gpsTime: [3]uint32{23, 30, 5},
+			}.tiff()),
+			wantLat:  &south,
+			wantLng:  &lng,
+			wantDate: &localDate,
+		},
+		{
+			name: "should read position and GPS time given big endian exif without time zone",
+			file: jpegWithExif(t, img, testExif{
+				order:   binary.BigEndian,
+				date:    "2024:03:01 06:30:00",
+				latRef:  "N",
+				lngRef:  "W",
+				gpsDate: "2024:02:29",
+				gpsTime: [3]uint32{23, 30, 5},
+			}.tiff()),
+			wantLat:  &lat,
+			wantLng:  &west,
+			wantDate: &gpsDate,
+		},
+		{
+			name: "should read camera time as UTC given no time zone and no GPS",
+			file: jpegWithExif(t, img, testExif{
+				order: binary.LittleEndian,
+				date:  "2024:03:01 06:30:00",
+			}.tiff()),
+			wantDate: &cameraDate,
+		},
+		{
+			name: "should return empty exif given jpeg without exif",
+			file: jpegWithExif(t, img, nil),
+		},
+		{
+			name: "should return empty exif given png",
+			file: pngBuf.Bytes(),
+		},
+		{
+			name:    "should return ErrInvalidExif given truncated exif",
+			file:    truncated,
+			wantErr: ErrInvalidExif,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReadExif(bytes.NewReader(tc.file))
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				return
+			}
+
+			assertFloatPtr(t, tc.wantLat, got.Latitude)
+			assertFloatPtr(t, tc.wantLng, got.Longitude)
+			assert.Equal(t, tc.wantLat != nil, got.HasPosition())
+
+			if tc.wantDate == nil {
+				assert.Nil(t, got.Date)
+			} else if assert.NotNil(t, got.Date) {
+				assert.True(t, tc.wantDate.Equal(*got.Date), "got %v", got.Date)
+				_, wantOffset := tc.wantDate.Zone()
+				_, gotOffset := got.Date.Zone()
+				assert.Equal(t, wantOffset, gotOffset)
+			}
+		})
+	}
+}
+
+func assertFloatPtr(t *testing.T, want, got *float64) {
+	if want == nil {
+		assert.Nil(t, got)
+		return
+	}
+
+	if assert.NotNil(t, got) {
+		assert.InDelta(t, *want, *got, 1e-9)
+	}
+}
@@ -0,0 +1,303 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// StripMode selects which metadata StripMetadata removes.
+type StripMode string
+
+const (
+	// StripAll removes all metadata, leaving only what is needed to decode the image
+	// and its EXIF orientation, without which it would be displayed rotated.
+	StripAll StripMode = "all"
+	// StripLocation only removes what can reveal where a photo was taken: the GPS
+	// data and maker notes in the EXIF data, and the XMP and IPTC data. The rest of the
+	// EXIF data and the colour profile are kept.
+	StripLocation StripMode = "location"
+)
+
+// ParseStripMode returns the StripMode named s, or StripAll for anything else so a
+// typo never leaves location data in place.
+func ParseStripMode(s string) StripMode {
+	if StripMode(s) == StripLocation {
+		return StripLocation
+	}
+
+	return StripAll
+}
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	ErrInvalidImage     = errors.New("invalid image data")
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// StripMetadata returns the JPEG or PNG image in b without its metadata. The image data
+// itself is copied as is, so the image is not recompressed.
+func StripMetadata(b []byte, mode StripMode) ([]byte, error) {
+	switch {
+	case len(b) >= 2 && b[0] == 0xff && b[1] == 0xd8:
+		return stripJPEG(b, mode)
+	case bytes.HasPrefix(b, pngSignature):
+		return stripPNG(b, mode)
+	}
+
+	return nil, ErrUnsupportedImage
+}
+
+// stripJPEG copies the segments of a JPEG that are needed to decode it, and stops at
+// its end so nothing appended after the image is kept either.
+func stripJPEG(b []byte, mode StripMode) ([]byte, error) {
+	res := make([]byte, 0, len(b))
+	res = append(res, b[:2]...)
+
+	for i := 2; ; {
+		if i+2 > len(b) || b[i] != 0xff {
+			return nil, ErrInvalidImage
+		}
+
+		marker := b[i+1]
+		switch {
+		// fill bytes before a marker
+		case marker == 0xff:
+			i++
+			continue
+		case marker == 0xd9:
+			return append(res, b[i:i+2]...), nil
+		case marker == 0x01 || (marker >= 0xd0 && marker <= 0xd7):
+			res = append(res, b[i:i+2]...)
+			i += 2
+			continue
+		}
+
+		if i+4 > len(b) {
+			return nil, ErrInvalidImage
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(b[i+2:]))
+		if end < i+4 || end > len(b) {
+			return nil, ErrInvalidImage
+		}
+
+		segment := b[i:end]
+		if keep, ok := keepJPEGSegment(marker, segment[4:], mode); ok {
+			res = append(res, keep...)
+		}
+		i = end
+
+		// the compressed data of a scan follows its header up to the next marker that
+		// is not a restart marker or an escaped 0xff
+		if marker == 0xda {
+			start := i
+			for i+1 < len(b) && (b[i] != 0xff || b[i+1] == 0 || (b[i+1] >= 0xd0 && b[i+1] <= 0xd7)) {
+				i++
+			}
+			res = append(res, b[start:i]...)
+		}
+	}
+}
+
+// keepJPEGSegment returns the JPEG segment with the given marker and data as it is to
+// be kept, and false when it is to be dropped.
+func keepJPEGSegment(marker byte, data []byte, mode StripMode) ([]byte, bool) {
+	segment := func(data []byte) []byte {
+		s := []byte{0xff, marker, 0, 0}
+		binary.BigEndian.PutUint16(s[2:], uint16(len(data)+2))
+		return append(s, data...)
+	}
+
+	switch {
+	// the Adobe segment tells decoders how the colours are encoded
+	case marker == 0xe0 && bytes.HasPrefix(data, []byte("JFIF\x00")),
+		marker == 0xee && bytes.HasPrefix(data, []byte("Adobe")):
+		return segment(data), true
+	case marker == 0xe1 && bytes.HasPrefix(data, []byte("Exif\x00\x00")):
+		if mode != StripLocation {
+			o := orientation(data[6:])
+			if o == 0 {
+				return nil, false
+			}
+			return segment(append([]byte("Exif\x00\x00"), orientationTIFF(o)...)), true
+		}
+
+		exif := append([]byte{}, data...)
+		if err := blankLocation(exif[6:]); err != nil {
+			return nil, false
+		}
+		return segment(exif), true
+	case marker == 0xe2 && bytes.HasPrefix(data, []byte("ICC_PROFILE\x00")):
+		return segment(data), mode == StripLocation
+	// any other application segment or comment is metadata
+	case marker >= 0xe0 && marker <= 0xef, marker == 0xfe:
+		return nil, false
+	}
+
+	return segment(data), true
+}
+
+// blankLocation zeroes the GPS directory and the maker note of the TIFF structure in
+// b, which can hold a position as well in some camera makers' formats. The directories
+// stay where they are, so no offsets have to be rewritten.
+func blankLocation(b []byte) error {
+	if len(b) < 8 {
+		return ErrInvalidExif
+	}
+
+	r := tiffReader{b: b}
+	switch string(b[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return ErrInvalidExif
+	}
+
+	ifd0, err := r.readIFD(r.order.Uint32(b[4:]))
+	if err != nil {
+		return err
+	}
+
+	if e, ok := ifd0[tagGPSIFD]; ok {
+		if err := r.blankIFD(r.integer(e)); err != nil {
+			return err
+		}
+	}
+
+	if e, ok := ifd0[tagExifIFD]; ok {
+		exif, err := r.readIFD(r.integer(e))
+		if err != nil {
+			return err
+		}
+
+		if note, ok := exif[tagMakerNote]; ok {
+			clear(note.value)
+		}
+	}
+
+	return nil
+}
+
+// orientation returns the Orientation of the TIFF structure in b, or 0 when it has
+// none or it is the default one.
+func orientation(b []byte) uint16 {
+	if len(b) < 8 {
+		return 0
+	}
+
+	r := tiffReader{b: b}
+	switch string(b[:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd0, err := r.readIFD(r.order.Uint32(b[4:]))
+	if err != nil {
+		return 0
+	}
+
+	o := r.integer(ifd0[tagOrientation])
+	if o < 2 || o > 8 {
+		return 0
+	}
+
+	return uint16(o)
+}
+
+// orientationTIFF returns a TIFF structure that holds nothing but the orientation o.
+func orientationTIFF(o uint16) []byte {
+	b := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	// one entry of a single SHORT, and no next directory
+	b = binary.BigEndian.AppendUint16(b, 1)
+	b = binary.BigEndian.AppendUint16(b, tagOrientation)
+	b = binary.BigEndian.AppendUint16(b, 3)
+	b = binary.BigEndian.AppendUint32(b, 1)
+	b = binary.BigEndian.AppendUint16(b, o)
+	return append(b, 0, 0, 0, 0, 0, 0)
+}
+
+// blankIFD zeroes the values and entries of the image file directory at offset and
+// leaves it empty.
+func (r tiffReader) blankIFD(offset uint32) error {
+	entries, err := r.readIFD(offset)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		clear(e.value)
+	}
+
+	n := int(r.order.Uint16(r.b[offset:]))
+	clear(r.b[offset : int(offset)+2+n*12])
+
+	return nil
+}
+
+// PNG chunks that are needed to display an image the way it was meant to look. Any
+// other chunk, such as text, time or EXIF chunks, is metadata.
+var pngRenderingChunks = map[string]bool{
+	"IHDR": true,
+	"PLTE": true,
+	"IDAT": true,
+	"IEND": true,
+	"tRNS": true,
+	"gAMA": true,
+	"cHRM": true,
+	"sRGB": true,
+	"sBIT": true,
+	"bKGD": true,
+	"pHYs": true,
+}
+
+// stripPNG copies the chunks of a PNG that are needed to display it, up to its end.
+// EXIF chunks are replaced by one holding only the orientation in either mode.
+func stripPNG(b []byte, mode StripMode) ([]byte, error) {
+	res := make([]byte, 0, len(b))
+	res = append(res, pngSignature...)
+
+	for i := len(pngSignature); ; {
+		if i+8 > len(b) {
+			return nil, ErrInvalidImage
+		}
+
+		size := uint64(binary.BigEndian.Uint32(b[i:]))
+		end := uint64(i) + 12 + size
+		if end > uint64(len(b)) {
+			return nil, ErrInvalidImage
+		}
+
+		typ := string(b[i+4 : i+8])
+		switch {
+		case pngRenderingChunks[typ] || (typ == "iCCP" && mode == StripLocation):
+			res = append(res, b[i:end]...)
+		case typ == "eXIf":
+			if o := orientation(b[i+8 : end-4]); o != 0 {
+				res = appendPNGChunk(res, typ, orientationTIFF(o))
+			}
+		}
+
+		if typ == "IEND" {
+			return res, nil
+		}
+		i = int(end)
+	}
+}
+
+// appendPNGChunk appends a chunk of the given type and data to b.
+func appendPNGChunk(b []byte, typ string, data []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	start := len(b)
+	b = append(b, typ...)
+	b = append(b, data...)
+	return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b[start:]))
+}
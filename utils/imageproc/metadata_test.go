@@ -0,0 +1,227 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withSegment inserts a JPEG segment right after the start of image marker of b.
+func withSegment(b []byte, marker byte, data string) []byte {
+	s := []byte{0xff, marker, 0, 0}
+	binary.BigEndian.PutUint16(s[2:], uint16(len(data)+2))
+	s = append(s, data...)
+
+	res := append([]byte{}, b[:2]...)
+	res = append(res, s...)
+	return append(res, b[2:]...)
+}
+
+// withChunk inserts a PNG chunk right after the IHDR chunk of b.
+func withChunk(b []byte, typ, data string) []byte {
+	c := make([]byte, 4, 12+len(data))
+	binary.BigEndian.PutUint32(c, uint32(len(data)))
+	c = append(c, typ...)
+	c = append(c, data...)
+	c = binary.BigEndian.AppendUint32(c, crc32.ChecksumIEEE(c[4:]))
+
+	// the signature and the 25 bytes of the IHDR chunk
+	end := len(pngSignature) + 25
+	res := append([]byte{}, b[:end]...)
+	res = append(res, c...)
+	return append(res, b[end:]...)
+}
+
+func TestStripMetadata(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 24))
+	for y := 0; y < 24; y++ {
+		for x := 0; x < 32; x++ {
+			img.Set(x, y, color.RGBA{uint8(x * 8), uint8(y * 10), 128, 255})
+		}
+	}
+
+	tiff := testExif{
+		order:   binary.LittleEndian,
+		date:    "2024:03:01 06:30:00",
+		offset:  "+07:00",
+		latRef:  "S",
+		lngRef:  "E",
+		gpsDate: "2024:03:01",
+		gpsTime: [3]uint32{23, 30, 5},
+	}.tiff()
+
+	rotated := testExif{
+		order:       binary.LittleEndian,
+		orientation: 6,
+		date:        "2024:03:01 06:30:00",
+		latRef:      "S",
+		lngRef:      "E",
+	}.tiff()
+
+	photo := jpegWithExif(t, img, tiff)
+	photo = withSegment(photo, 0xe1, "http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>-7.550667</x:xmpmeta>")
+	photo = withSegment(photo, 0xe2, "ICC_PROFILE\x00\x01\x01profile")
+	photo = withSegment(photo, 0xfe, "Camera trap 12, Kebun Raya")
+	photo = append(photo, "trailing data"...)
+
+	var pngBuf bytes.Buffer
+	err := png.Encode(&pngBuf, img)
+	assert.NoError(t, err)
+
+	pngPhoto := withChunk(pngBuf.Bytes(), "tEXt", "Location\x00Kebun Raya")
+	pngPhoto = withChunk(pngPhoto, "eXIf", string(tiff))
+
+	rotatedPNG := withChunk(pngBuf.Bytes(), "eXIf", string(rotated))
+
+	testCases := []struct {
+		name string
+
+		file []byte
+		mode StripMode
+
+		wantKept        []string
+		wantDropped     []string
+		wantDate        bool
+		wantOrientation uint16
+		wantErr         error
+	}{
+		{
+			name:        "should remove all metadata from jpeg",
+			file:        photo,
+			mode:        StripAll,
+			wantDropped: []string{"Exif", "xmpmeta", "ICC_PROFILE", "Kebun Raya", "trailing data"},
+		},
+		{
+			name:        "should remove only location from jpeg",
+			file:        photo,
+			mode:        StripLocation,
+			wantKept:    []string{"Exif", "ICC_PROFILE"},
+			wantDropped: []string{"xmpmeta", "Kebun Raya", "trailing data"},
+			wantDate:    true,
+		},
+		{
+			name:        "should remove all metadata from png",
+			file:        pngPhoto,
+			mode:        StripAll,
+			wantDropped: []string{"tEXt", "eXIf", "Kebun Raya"},
+		},
+		{
+			name:        "should remove exif data from png given location mode",
+			file:        pngPhoto,
+			mode:        StripLocation,
+			wantDropped: []string{"tEXt", "eXIf", "Kebun Raya"},
+		},
+		{
+			name:            "should keep only the orientation of jpeg",
+			file:            jpegWithExif(t, img, rotated),
+			mode:            StripAll,
+			wantOrientation: 6,
+		},
+		{
+			name:            "should keep only the orientation of png",
+			file:            rotatedPNG,
+			mode:            StripAll,
+			wantKept:        []string{"eXIf"},
+			wantOrientation: 6,
+		},
+		{
+			name:            "should keep the orientation of png given location mode",
+			file:            rotatedPNG,
+			mode:            StripLocation,
+			wantKept:        []string{"eXIf"},
+			wantOrientation: 6,
+		},
+		{
+			name:    "should return ErrInvalidImage given truncated jpeg",
+			file:    photo[:len(photo)/2],
+			mode:    StripAll,
+			wantErr: ErrInvalidImage,
+		},
+		{
+			name:    "should return ErrUnsupportedImage given gif",
+			file:    []byte("GIF89a"),
+			mode:    StripAll,
+			wantErr: ErrUnsupportedImage,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := StripMetadata(tc.file, tc.mode)
+
+			assert.Equal(t, tc.wantErr, err)
+			if tc.wantErr != nil {
+				return
+			}
+
+			for _, s := range tc.wantKept {
+				assert.True(t, bytes.Contains(got, []byte(s)), "%q was removed", s)
+			}
+			for _, s := range tc.wantDropped {
+				assert.False(t, bytes.Contains(got, []byte(s)), "%q was kept", s)
+			}
+
+			exif, err := ReadExif(bytes.NewReader(got))
+			assert.NoError(t, err)
+			assert.False(t, exif.HasPosition())
+			assert.Equal(t, tc.wantDate, exif.Date != nil)
+			assert.Equal(t, tc.wantOrientation, orientationOf(t, got))
+
+			// the image data is copied as is
+			want, _, err := image.Decode(bytes.NewReader(tc.file))
+			assert.NoError(t, err)
+			res, _, err := image.Decode(bytes.NewReader(got))
+			assert.NoError(t, err)
+			assert.Equal(t, want, res)
+		})
+	}
+}
+
+// orientationOf returns the EXIF orientation of the JPEG or PNG image in b, or 0 when
+// it has none.
+func orientationOf(t *testing.T, b []byte) uint16 {
+	if !bytes.HasPrefix(b, pngSignature) {
+		tiff, err := findExif(b)
+		assert.NoError(t, err)
+		return orientation(tiff)
+	}
+
+	i := bytes.Index(b, []byte("eXIf"))
+	if i < 0 {
+		return 0
+	}
+
+	size := binary.BigEndian.Uint32(b[i-4:])
+	return orientation(b[i+4 : i+4+int(size)])
+}
+
+func TestResizeImage_Metadata(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 500, 400))
+	photo := jpegWithExif(t, img, testExif{
+		order:  binary.BigEndian,
+		date:   "2024:03:01 06:30:00",
+		latRef: "S",
+		lngRef: "E",
+	}.tiff())
+
+	r, _, err := ResizeImage(bytes.NewReader(photo), Thumb)
+	assert.NoError(t, err)
+
+	var got bytes.Buffer
+	_, err = got.ReadFrom(r)
+	assert.NoError(t, err)
+
+	exif, err := ReadExif(bytes.NewReader(got.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, &Exif{}, exif)
+
+	stripped, err := StripMetadata(got.Bytes(), StripAll)
+	assert.NoError(t, err)
+	assert.Equal(t, stripped, got.Bytes())
+}
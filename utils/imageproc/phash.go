@@ -0,0 +1,169 @@
+package imageproc
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// hashSide is the size in pixels images are scaled to before they are hashed.
+	hashSide = 32
+	// hashFreqs is the number of the lowest frequencies per axis the hash is made of.
+	hashFreqs = 8
+
+	// DuplicateDistance is the number of bits the hashes of two images may differ in
+	// for the images to be considered the same photo. Resizing, recompressing or
+	// slightly changing the brightness of a photo stays well below it.
+	DuplicateDistance = 8
+
+	// HashBands is the number of 16 bit bands a hash is split into to look up similar
+	// hashes through an index.
+	HashBands = 4
+)
+
+var ErrInvalidHash = errors.New("invalid image hash")
+
+// Hash is a perceptual hash of an image. Unlike a checksum, it changes little when the
+// image is resized or recompressed, so near-identical images have hashes that differ
+// in a few bits only.
+type Hash uint64
+
+// ComputeHash decodes the image in f and returns its hash.
+func ComputeHash(f io.ReadSeeker) (Hash, error) {
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+
+	img, err := imaging.Decode(f, imaging.AutoOrientation(true))
+	if err != nil {
+		return 0, err
+	}
+
+	return HashOf(img), nil
+}
+
+// HashOf returns the hash of img. The image is scaled down to grayscale and every
+// bit of the hash tells whether one of its lowest frequencies, as given by a discrete
+// cosine transform, is above their median.
+func HashOf(img image.Image) Hash {
+	g := imaging.Grayscale(imaging.Resize(img, hashSide, hashSide, imaging.Box))
+
+	var cos [hashFreqs][hashSide]float64
+	for u := 0; u < hashFreqs; u++ {
+		for x := 0; x < hashSide; x++ {
+			cos[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * hashSide))
+		}
+	}
+
+	var coeffs [hashFreqs * hashFreqs]float64
+	for v := 0; v < hashFreqs; v++ {
+		for u := 0; u < hashFreqs; u++ {
+			var sum float64
+			for y := 0; y < hashSide; y++ {
+				for x := 0; x < hashSide; x++ {
+					sum += float64(g.Pix[y*g.Stride+x*4]) * cos[u][x] * cos[v][y]
+				}
+			}
+			coeffs[v*hashFreqs+u] = sum
+		}
+	}
+
+	// the first coefficient is the average brightness, which says nothing about the
+	// content of the image and would skew the median
+	sorted := make([]float64, len(coeffs)-1)
+	copy(sorted, coeffs[1:])
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var h Hash
+	for i, c := range coeffs {
+		if c > median {
+			h |= 1 << i
+		}
+	}
+
+	return h
+}
+
+// Distance returns the number of bits the hashes differ in, from 0 for the same image
+// to 64.
+func (h Hash) Distance(other Hash) int {
+	return bits.OnesCount64(uint64(h ^ other))
+}
+
+// IsDuplicate reports whether the hashes are close enough for their images to be
+// considered the same photo.
+func (h Hash) IsDuplicate(other Hash) bool {
+	return h.Distance(other) <= DuplicateDistance
+}
+
+// Bands splits the hash into HashBands bands of 16 bits, lowest bits first.
+func (h Hash) Bands() [HashBands]uint16 {
+	var res [HashBands]uint16
+	for i := range res {
+		res[i] = uint16(h >> (16 * i))
+	}
+
+	return res
+}
+
+// NearBands returns, for every band of the hash, the values that differ from it in at
+// most DuplicateDistance / HashBands bits, the band itself included. Hashes that differ
+// in more bits than that in every band differ in more than DuplicateDistance bits in
+// total, so a duplicate of the hash always has at least one band among them.
+func (h Hash) NearBands() [HashBands][]uint16 {
+	var res [HashBands][]uint16
+	for i, band := range h.Bands() {
+		res[i] = nearBands(band, DuplicateDistance/HashBands)
+	}
+
+	return res
+}
+
+// nearBands returns the values that differ from band in at most n bits.
+func nearBands(band uint16, n int) []uint16 {
+	res := []uint16{band}
+	if n == 0 {
+		return res
+	}
+
+	fewer := nearBands(band, n-1)
+	for bit := 0; bit < 16; bit++ {
+		for _, v := range fewer {
+			// each set of flipped bits is only produced by its highest bit
+			if (v^band)>>bit == 0 {
+				res = append(res, v^1<<bit)
+			}
+		}
+	}
+
+	return res
+}
+
+// String encodes the hash as 16 hexadecimal digits to be stored as text.
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// ParseHash decodes a hash encoded by Hash.String.
+func ParseHash(s string) (Hash, error) {
+	if len(s) != 16 {
+		return 0, ErrInvalidHash
+	}
+
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, ErrInvalidHash
+	}
+
+	return Hash(v), nil
+}
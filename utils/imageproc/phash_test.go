@@ -0,0 +1,186 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// scene draws a photo-like image of smooth shading, a bright spot and stripes, all
+// shifted around by variant.
+func scene(variant float64) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			fx, fy := float64(x), float64(y)
+			spotX := 100 * variant
+
+			v := 120 + 60*math.Sin(fx/40*variant+fy/70) +
+				50*math.Exp(-((fx-spotX)*(fx-spotX)+(fy-80)*(fy-80))/1500)
+			if int(fx/15+fy/30*variant)%2 == 0 {
+				v -= 40
+			}
+
+			img.SetGray(x, y, color.Gray{uint8(math.Max(0, math.Min(255, v)))})
+		}
+	}
+
+	return img
+}
+
+// reencode returns img as it comes out of a JPEG encoder at the given quality.
+func reencode(t *testing.T, img image.Image, quality int) image.Image {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	assert.NoError(t, err)
+
+	res, err := jpeg.Decode(&buf)
+	assert.NoError(t, err)
+
+	return res
+}
+
+func TestHash_IsDuplicate(t *testing.T) {
+	photo := scene(1)
+
+	testCases := []struct {
+		name string
+
+		a, b image.Image
+
+		want bool
+	}{
+		{
+			name: "should be duplicate given the same image",
+			a:    photo,
+			b:    photo,
+			want: true,
+		},
+		{
+			name: "should be duplicate given the image resized and recompressed",
+			a:    photo,
+			b:    reencode(t, imaging.Resize(photo, 120, 0, imaging.Lanczos), 50),
+			want: true,
+		},
+		{
+			name: "should be duplicate given the image slightly brightened",
+			a:    photo,
+			b:    imaging.AdjustBrightness(photo, 10),
+			want: true,
+		},
+		{
+			name: "should not be duplicate given another scene",
+			a:    photo,
+			b:    scene(1.5),
+			want: false,
+		},
+		{
+			name: "should not be duplicate given a cropped image",
+			a:    photo,
+			b:    imaging.CropCenter(photo, 240, 160),
+			want: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := HashOf(tc.a), HashOf(tc.b)
+
+			assert.Equal(t, tc.want, a.IsDuplicate(b), "distance %d", a.Distance(b))
+		})
+	}
+}
+
+func TestComputeHash(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, scene(1))
+	assert.NoError(t, err)
+
+	got, err := ComputeHash(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, HashOf(scene(1)), got)
+
+	_, err = ComputeHash(bytes.NewReader([]byte("not an image")))
+	assert.Error(t, err)
+}
+
+func TestComputeHash_Orientation(t *testing.T) {
+	upright := jpegWithExif(t, scene(1), nil)
+	// the same photo taken with the camera turned left, which is turned right to view
+	sideways := jpegWithExif(t, imaging.Rotate90(scene(1)), testExif{
+		order:       binary.BigEndian,
+		orientation: 6,
+	}.tiff())
+
+	want, err := ComputeHash(bytes.NewReader(upright))
+	assert.NoError(t, err)
+
+	got, err := ComputeHash(bytes.NewReader(sideways))
+	assert.NoError(t, err)
+	assert.Equal(t, want, got, "distance %d", want.Distance(got))
+}
+
+func TestParseHash(t *testing.T) {
+	h := HashOf(scene(1))
+
+	got, err := ParseHash(h.String())
+	assert.NoError(t, err)
+	assert.Equal(t, h, got)
+	assert.Len(t, h.String(), 16)
+
+	for _, s := range []string{"", "abc", "zzzzzzzzzzzzzzzz"} {
+		_, err = ParseHash(s)
+		assert.Equal(t, ErrInvalidHash, err)
+	}
+}
+
+func TestHash_NearBands(t *testing.T) {
+	h := Hash(0x0123456789abcdef)
+
+	assert.Equal(t, [HashBands]uint16{0xcdef, 0x89ab, 0x4567, 0x0123}, h.Bands())
+
+	near := h.NearBands()
+	for i, band := range h.Bands() {
+		seen := map[uint16]bool{}
+		for _, v := range near[i] {
+			assert.False(t, seen[v], "band %d: %04x twice", i, v)
+			assert.LessOrEqual(t, Hash(v).Distance(Hash(band)), DuplicateDistance/HashBands)
+			seen[v] = true
+		}
+
+		// 1 + 16 + 16*15/2 values differ in at most 2 bits
+		assert.Len(t, near[i], 137)
+	}
+
+	testCases := []struct {
+		name  string
+		other Hash
+	}{
+		{name: "same hash", other: h},
+		{name: "2 bits in every band", other: h ^ 0x0003000300030003},
+		{name: "8 bits in one band", other: h ^ 0x00000000000000ff},
+		{name: "3 bits in two bands", other: h ^ 0x0007000700030000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.True(t, h.IsDuplicate(tc.other))
+
+			shared := false
+			for i, band := range tc.other.Bands() {
+				for _, v := range near[i] {
+					shared = shared || v == band
+				}
+			}
+			assert.True(t, shared)
+		})
+	}
+}
@@ -24,8 +24,23 @@ func IsContentTypeValid(contentType string, filename string) bool {
 	return true
 }
 
-// This function resizes an image to fit in 250x200 pixels bounding box (aspect ratio is maintained).
-func ResizeImage(f io.ReadSeeker, name string) (*bytes.Reader, int, error) {
+// Rendition is a size images are stored in besides their original size.
+type Rendition struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+var (
+	// Thumb is small enough for lists and map markers.
+	Thumb = Rendition{Name: "thumb", Width: 250, Height: 200}
+	// Medium is large enough to tell tigers apart by their stripes.
+	Medium = Rendition{Name: "medium", Width: 1280, Height: 1024}
+)
+
+// This function resizes an image to fit in the bounding box of the rendition (aspect ratio is maintained).
+// Images that already fit are not enlarged.
+func ResizeImage(f io.ReadSeeker, size Rendition) (*bytes.Reader, int, error) {
 	_, err := f.Seek(0, io.SeekStart)
 	if err != nil {
 		return nil, 0, err
@@ -36,11 +51,12 @@ func ResizeImage(f io.ReadSeeker, name string) (*bytes.Reader, int, error) {
 		return nil, 0, err
 	}
 
-	src, err := imaging.Decode(bytes.NewReader(b))
+	// renditions have no EXIF data, so their pixels are rotated as its orientation says
+	src, err := imaging.Decode(bytes.NewReader(b), imaging.AutoOrientation(true))
 	if err != nil {
 		return nil, 0, err
 	}
-	dst := imaging.Fit(src, 250, 200, imaging.Lanczos)
+	dst := imaging.Fit(src, size.Width, size.Height, imaging.Lanczos)
 
 	var o []byte
 	w := bytes.NewBuffer(o)
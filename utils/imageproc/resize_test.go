@@ -0,0 +1,83 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeImage(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		width  int
+		height int
+		size   Rendition
+
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name:       "should fit image in thumbnail",
+			width:      1000,
+			height:     500,
+			size:       Thumb,
+			wantWidth:  250,
+			wantHeight: 125,
+		},
+		{
+			name:       "should fit image in medium rendition",
+			width:      2560,
+			height:     2560,
+			size:       Medium,
+			wantWidth:  1024,
+			wantHeight: 1024,
+		},
+		{
+			name:       "should keep size given image smaller than rendition",
+			width:      500,
+			height:     400,
+			size:       Medium,
+			wantWidth:  500,
+			wantHeight: 400,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, tc.width, tc.height)))
+			assert.NoError(t, err)
+
+			r, size, err := ResizeImage(bytes.NewReader(buf.Bytes()), tc.size)
+			assert.NoError(t, err)
+			assert.Equal(t, int(r.Size()), size)
+
+			cfg, format, err := image.DecodeConfig(r)
+			assert.NoError(t, err)
+			assert.Equal(t, "jpeg", format)
+			assert.Equal(t, tc.wantWidth, cfg.Width)
+			assert.Equal(t, tc.wantHeight, cfg.Height)
+		})
+	}
+}
+
+func TestResizeImage_Orientation(t *testing.T) {
+	// a landscape photo taken with the camera turned right
+	photo := jpegWithExif(t, image.NewGray(image.Rect(0, 0, 500, 400)), testExif{
+		order:       binary.BigEndian,
+		orientation: 6,
+	}.tiff())
+
+	r, _, err := ResizeImage(bytes.NewReader(photo), Thumb)
+	assert.NoError(t, err)
+
+	cfg, _, err := image.DecodeConfig(r)
+	assert.NoError(t, err)
+	assert.Equal(t, 160, cfg.Width)
+	assert.Equal(t, 200, cfg.Height)
+}
@@ -0,0 +1,142 @@
+package imageproc
+
+import (
+	"encoding/base64"
+	"errors"
+	"image"
+	"io"
+	"math"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// signatureSide is the size in pixels images are scaled to before their edges are measured.
+	signatureSide = 64
+	// signatureGrid is the number of cells per side the scaled image is split into.
+	signatureGrid = 4
+	// orientationBins is the number of edge orientations counted per cell.
+	orientationBins = 8
+
+	SignatureSize = signatureGrid * signatureGrid * orientationBins
+)
+
+var ErrInvalidSignature = errors.New("invalid image signature")
+
+// Signature describes the stripe pattern of an image as a histogram of edge
+// orientations for each cell of a grid laid over it. Each cell is normalized on its
+// own, so the signature does not depend on the brightness or contrast of the photo.
+type Signature [SignatureSize]uint8
+
+// ComputeSignature decodes the image in f and returns its signature.
+func ComputeSignature(f io.ReadSeeker) (Signature, error) {
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return Signature{}, err
+	}
+
+	img, err := imaging.Decode(f, imaging.AutoOrientation(true))
+	if err != nil {
+		return Signature{}, err
+	}
+
+	return SignatureOf(img), nil
+}
+
+// SignatureOf returns the signature of img.
+func SignatureOf(img image.Image) Signature {
+	g := imaging.Grayscale(imaging.Resize(img, signatureSide, signatureSide, imaging.Box))
+
+	lum := func(x, y int) float64 {
+		return float64(g.Pix[y*g.Stride+x*4])
+	}
+
+	var hist [SignatureSize]float64
+	for y := 1; y < signatureSide-1; y++ {
+		for x := 1; x < signatureSide-1; x++ {
+			// Sobel operator
+			gx := lum(x+1, y-1) + 2*lum(x+1, y) + lum(x+1, y+1) -
+				lum(x-1, y-1) - 2*lum(x-1, y) - lum(x-1, y+1)
+			gy := lum(x-1, y+1) + 2*lum(x, y+1) + lum(x+1, y+1) -
+				lum(x-1, y-1) - 2*lum(x, y-1) - lum(x+1, y-1)
+
+			mag := math.Hypot(gx, gy)
+			if mag == 0 {
+				continue
+			}
+
+			// the direction an edge is crossed in does not matter, only its orientation
+			angle := math.Atan2(gy, gx)
+			if angle < 0 {
+				angle += math.Pi
+			}
+			bin := int(angle/math.Pi*orientationBins) % orientationBins
+
+			cell := (y*signatureGrid/signatureSide)*signatureGrid + x*signatureGrid/signatureSide
+			hist[cell*orientationBins+bin] += mag
+		}
+	}
+
+	for c := 0; c < signatureGrid*signatureGrid; c++ {
+		normalize(hist[c*orientationBins : (c+1)*orientationBins])
+	}
+
+	var s Signature
+	for i, v := range hist {
+		s[i] = uint8(math.Round(v * 255))
+	}
+
+	return s
+}
+
+// Similarity returns the cosine similarity of the signatures, from 0 for unrelated
+// patterns to 1 for identical ones.
+func (s Signature) Similarity(other Signature) float64 {
+	var dot, a, b float64
+	for i := range s {
+		dot += float64(s[i]) * float64(other[i])
+		a += float64(s[i]) * float64(s[i])
+		b += float64(other[i]) * float64(other[i])
+	}
+
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return dot / math.Sqrt(a*b)
+}
+
+// String encodes the signature to be stored as text.
+func (s Signature) String() string {
+	return base64.RawStdEncoding.EncodeToString(s[:])
+}
+
+// ParseSignature decodes a signature encoded by Signature.String.
+func ParseSignature(str string) (Signature, error) {
+	var s Signature
+
+	b, err := base64.RawStdEncoding.DecodeString(str)
+	if err != nil || len(b) != SignatureSize {
+		return s, ErrInvalidSignature
+	}
+
+	copy(s[:], b)
+	return s, nil
+}
+
+// normalize scales v to a length of 1, leaving it as is when it is all zeros.
+func normalize(v []float64) {
+	var sum float64
+	for _, x := range v {
+		sum += x * x
+	}
+
+	if sum == 0 {
+		return
+	}
+
+	l := math.Sqrt(sum)
+	for i := range v {
+		v[i] /= l
+	}
+}
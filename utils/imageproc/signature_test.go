@@ -0,0 +1,104 @@
+package imageproc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignature_Similarity(t *testing.T) {
+	testCases := []struct {
+		name string
+
+		a, b image.Image
+
+		wantMin, wantMax float64
+	}{
+		{
+			name:    "should be 1 given the same image",
+			a:       testutil.Stripes(12, 0, 230),
+			b:       testutil.Stripes(12, 0, 230),
+			wantMin: 1,
+			wantMax: 1,
+		},
+		{
+			name:    "should be close to 1 given the same pattern in other lighting",
+			a:       testutil.Stripes(12, 3, 230),
+			b:       testutil.Stripes(12, 3, 120),
+			wantMin: 0.95,
+			wantMax: 1,
+		},
+		{
+			name:    "should be low given stripes of another orientation",
+			a:       testutil.Stripes(12, 0, 230),
+			b:       testutil.Stripes(12, 30, 230),
+			wantMin: 0,
+			wantMax: 0.5,
+		},
+		{
+			name:    "should be 0 given an image without edges",
+			a:       testutil.Stripes(12, 0, 230),
+			b:       testutil.Stripes(12, 0, 20),
+			wantMin: 0,
+			wantMax: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SignatureOf(tc.a).Similarity(SignatureOf(tc.b))
+
+			assert.GreaterOrEqual(t, got, tc.wantMin-1e-9)
+			assert.LessOrEqual(t, got, tc.wantMax+1e-9)
+		})
+	}
+}
+
+func TestComputeSignature(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, testutil.Stripes(12, 3, 230))
+	assert.NoError(t, err)
+
+	got, err := ComputeSignature(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, SignatureOf(testutil.Stripes(12, 3, 230)), got)
+
+	_, err = ComputeSignature(bytes.NewReader([]byte("not an image")))
+	assert.Error(t, err)
+}
+
+func TestComputeSignature_Orientation(t *testing.T) {
+	img := testutil.Stripes(12, 3, 230)
+	upright := jpegWithExif(t, img, nil)
+	// the same photo taken with the camera turned left, which is turned right to view
+	sideways := jpegWithExif(t, imaging.Rotate90(img), testExif{
+		order:       binary.BigEndian,
+		orientation: 6,
+	}.tiff())
+
+	want, err := ComputeSignature(bytes.NewReader(upright))
+	assert.NoError(t, err)
+
+	got, err := ComputeSignature(bytes.NewReader(sideways))
+	assert.NoError(t, err)
+	assert.InDelta(t, 1, want.Similarity(got), 1e-3)
+}
+
+func TestParseSignature(t *testing.T) {
+	s := SignatureOf(testutil.Stripes(12, 3, 230))
+
+	got, err := ParseSignature(s.String())
+	assert.NoError(t, err)
+	assert.Equal(t, s, got)
+	assert.False(t, math.IsNaN(got.Similarity(s)))
+
+	_, err = ParseSignature("too short")
+	assert.Equal(t, ErrInvalidSignature, err)
+}
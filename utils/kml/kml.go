@@ -0,0 +1,80 @@
+package kml
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const namespace = "http://www.opengis.net/kml/2.2"
+
+// KML is the root element of a KML 2.2 document.
+type KML struct {
+	XMLName  xml.Name `xml:"kml"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Document Document `xml:"Document"`
+}
+
+type Document struct {
+	Name       string      `xml:"name,omitempty"`
+	Placemarks []Placemark `xml:"Placemark"`
+}
+
+type Placemark struct {
+	Name        string      `xml:"name,omitempty"`
+	Description string      `xml:"description,omitempty"`
+	TimeStamp   *TimeStamp  `xml:"TimeStamp,omitempty"`
+	Point       *Point      `xml:"Point,omitempty"`
+	LineString  *LineString `xml:"LineString,omitempty"`
+}
+
+type TimeStamp struct {
+	When time.Time `xml:"when"`
+}
+
+type Point struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type LineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+func New(name string, placemarks ...Placemark) *KML {
+	return &KML{
+		Xmlns: namespace,
+		Document: Document{
+			Name:       name,
+			Placemarks: placemarks,
+		},
+	}
+}
+
+func NewTimeStamp(t time.Time) *TimeStamp {
+	return &TimeStamp{When: t.UTC()}
+}
+
+func NewPoint(latitude, longitude float64) *Point {
+	return &Point{Coordinates: coordinate(latitude, longitude)}
+}
+
+// NewLineString returns a LineString through the given [latitude, longitude] pairs,
+// tessellated so it follows the terrain in Google Earth.
+func NewLineString(positions [][2]float64) *LineString {
+	coords := make([]string, len(positions))
+	for i, p := range positions {
+		coords[i] = coordinate(p[0], p[1])
+	}
+
+	return &LineString{
+		Tessellate:  1,
+		Coordinates: strings.Join(coords, " "),
+	}
+}
+
+// coordinate formats a position as KML expects it, longitude first.
+func coordinate(latitude, longitude float64) string {
+	return strconv.FormatFloat(longitude, 'f', -1, 64) + "," + strconv.FormatFloat(latitude, 'f', -1, 64)
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,16 +15,32 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	conf "github.com/muhwyndhamhp/tigerhall-kittens/utils/config"
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
 )
 
 type S3Client struct {
-	client *s3.Client
+	client    *s3.Client
+	stripMode imageproc.StripMode
 }
 
-const defaultBucketName = "tigerhall-kittens"
+const (
+	defaultBucketName = "tigerhall-kittens"
+	publicBaseURL     = "https://tigerhall-kittens.mwyndham.dev/"
+	// privateBucketName is the bucket of images that are only handed out through
+	// presigned URLs, it has no public domain.
+	privateBucketName = "tigerhall-kittens-originals"
+	presignExpiry     = 15 * time.Minute
+)
 
 type S3ClientInterface interface {
-	UploadImage(ctx context.Context, r *bytes.Reader, filename, contentType string, size int64) (string, error)
+	UploadImage(ctx context.Context, r *bytes.Reader, filename, contentType string) (string, error)
+	DeleteImage(ctx context.Context, url string) error
+	// UploadPrivateImage uploads an image that is not publicly reachable and returns its key.
+	UploadPrivateImage(ctx context.Context, r *bytes.Reader, filename, contentType string) (string, error)
+	// PresignPrivateImage returns a URL the private image with the given key can be
+	// downloaded from for a short while.
+	PresignPrivateImage(ctx context.Context, key string) (string, error)
+	DeletePrivateImage(ctx context.Context, key string) error
 }
 
 // Create S3 Client that connects to R2 Cloudflare Storage
@@ -48,26 +66,103 @@ func NewS3Client() S3ClientInterface {
 
 	client := s3.NewFromConfig(cfg)
 
-	return &S3Client{client}
+	return &S3Client{client, imageproc.ParseStripMode(conf.Get(conf.IMAGE_STRIP_METADATA))}
 }
 
-// UploadImage uploads image to S3
-func (c *S3Client) UploadImage(ctx context.Context, r *bytes.Reader, filename, contentType string, size int64) (string, error) {
+// UploadImage uploads image to S3 with the given content type. Its metadata is stripped
+// first, so photos never reveal where they were taken no matter how they were processed
+// before.
+func (c *S3Client) UploadImage(ctx context.Context, r *bytes.Reader, filename, contentType string) (string, error) {
+	key, err := c.upload(ctx, defaultBucketName, r, filename, contentType)
+	if err != nil {
+		return "", err
+	}
+
+	return publicBaseURL + key, nil
+}
+
+// UploadPrivateImage implements S3ClientInterface. The image is stored as it is, with
+// its metadata stripped like in UploadImage.
+func (c *S3Client) UploadPrivateImage(ctx context.Context, r *bytes.Reader, filename, contentType string) (string, error) {
+	return c.upload(ctx, privateBucketName, r, filename, contentType)
+}
+
+func (c *S3Client) upload(ctx context.Context, bucket string, r *bytes.Reader, filename, contentType string) (string, error) {
+	_, err := r.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	b, err = imageproc.StripMetadata(b, c.stripMode)
+	if err != nil {
+		return "", err
+	}
+
 	filename = AppendTimestamp(filename)
 	obj := &s3.PutObjectInput{
-		Bucket:        aws.String(defaultBucketName),
+		Bucket:        aws.String(bucket),
 		Key:           aws.String(filename),
-		ContentType:   aws.String("image/jpeg"),
-		Body:          r,
-		ContentLength: aws.Int64(size),
+		ContentType:   aws.String(contentType),
+		Body:          bytes.NewReader(b),
+		ContentLength: aws.Int64(int64(len(b))),
 	}
 
-	_, err := c.client.PutObject(ctx, obj)
+	_, err = c.client.PutObject(ctx, obj)
 	if err != nil {
 		return "", err
 	}
 
-	return fmt.Sprintf("https://tigerhall-kittens.mwyndham.dev/%s", filename), nil
+	return filename, nil
+}
+
+// PresignPrivateImage implements S3ClientInterface.
+func (c *S3Client) PresignPrivateImage(ctx context.Context, key string) (string, error) {
+	obj := &s3.GetObjectInput{
+		Bucket: aws.String(privateBucketName),
+		Key:    aws.String(key),
+	}
+
+	req, err := s3.NewPresignClient(c.client).PresignGetObject(ctx, obj, s3.WithPresignExpires(presignExpiry))
+	if err != nil {
+		return "", err
+	}
+
+	return req.URL, nil
+}
+
+// DeleteImage deletes an image previously uploaded by UploadImage, identified by its public URL
+func (c *S3Client) DeleteImage(ctx context.Context, url string) error {
+	obj := &s3.DeleteObjectInput{
+		Bucket: aws.String(defaultBucketName),
+		Key:    aws.String(strings.TrimPrefix(url, publicBaseURL)),
+	}
+
+	_, err := c.client.DeleteObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeletePrivateImage deletes an image previously uploaded by UploadPrivateImage, identified by its key
+func (c *S3Client) DeletePrivateImage(ctx context.Context, key string) error {
+	obj := &s3.DeleteObjectInput{
+		Bucket: aws.String(privateBucketName),
+		Key:    aws.String(key),
+	}
+
+	_, err := c.client.DeleteObject(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func AppendTimestamp(fileName string) string {
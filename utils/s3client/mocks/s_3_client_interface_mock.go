@@ -14,23 +14,99 @@ type S3ClientInterface struct {
 	mock.Mock
 }
 
-// UploadImage provides a mock function with given fields: ctx, r, filename, contentType, size
-func (_m *S3ClientInterface) UploadImage(ctx context.Context, r *bytes.Reader, filename string, contentType string, size int64) (string, error) {
-	ret := _m.Called(ctx, r, filename, contentType, size)
+// DeleteImage provides a mock function with given fields: ctx, url
+func (_m *S3ClientInterface) DeleteImage(ctx context.Context, url string) error {
+	ret := _m.Called(ctx, url)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, url)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePrivateImage provides a mock function with given fields: ctx, key
+func (_m *S3ClientInterface) DeletePrivateImage(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PresignPrivateImage provides a mock function with given fields: ctx, key
+func (_m *S3ClientInterface) PresignPrivateImage(ctx context.Context, key string) (string, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadImage provides a mock function with given fields: ctx, r, filename, contentType
+func (_m *S3ClientInterface) UploadImage(ctx context.Context, r *bytes.Reader, filename string, contentType string) (string, error) {
+	ret := _m.Called(ctx, r, filename, contentType)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *bytes.Reader, string, string) (string, error)); ok {
+		return rf(ctx, r, filename, contentType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *bytes.Reader, string, string) string); ok {
+		r0 = rf(ctx, r, filename, contentType)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *bytes.Reader, string, string) error); ok {
+		r1 = rf(ctx, r, filename, contentType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadPrivateImage provides a mock function with given fields: ctx, r, filename, contentType
+func (_m *S3ClientInterface) UploadPrivateImage(ctx context.Context, r *bytes.Reader, filename string, contentType string) (string, error) {
+	ret := _m.Called(ctx, r, filename, contentType)
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *bytes.Reader, string, string, int64) (string, error)); ok {
-		return rf(ctx, r, filename, contentType, size)
+	if rf, ok := ret.Get(0).(func(context.Context, *bytes.Reader, string, string) (string, error)); ok {
+		return rf(ctx, r, filename, contentType)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *bytes.Reader, string, string, int64) string); ok {
-		r0 = rf(ctx, r, filename, contentType, size)
+	if rf, ok := ret.Get(0).(func(context.Context, *bytes.Reader, string, string) string); ok {
+		r0 = rf(ctx, r, filename, contentType)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *bytes.Reader, string, string, int64) error); ok {
-		r1 = rf(ctx, r, filename, contentType, size)
+	if rf, ok := ret.Get(1).(func(context.Context, *bytes.Reader, string, string) error); ok {
+		r1 = rf(ctx, r, filename, contentType)
 	} else {
 		r1 = ret.Error(1)
 	}
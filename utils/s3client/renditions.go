@@ -0,0 +1,94 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/imageproc"
+)
+
+// Renditions are where the renditions of an uploaded image are stored.
+type Renditions struct {
+	ThumbURL  string
+	MediumURL string
+	// OriginalKey is the key of the full resolution image, which is kept private.
+	OriginalKey string
+}
+
+// UploadRenditions uploads the thumbnail and medium renditions of the image in f, and
+// the image itself privately. The renditions are stored under a directory named after
+// them. When one of the uploads fails, the ones that succeeded are deleted again.
+func UploadRenditions(ctx context.Context, c S3ClientInterface, f io.ReadSeeker, filename, contentType string) (Renditions, error) {
+	var res Renditions
+
+	var err error
+	res.ThumbURL, err = uploadRendition(ctx, c, f, imageproc.Thumb, filename)
+	if err == nil {
+		res.MediumURL, err = uploadRendition(ctx, c, f, imageproc.Medium, filename)
+	}
+	if err == nil {
+		res.OriginalKey, err = uploadOriginal(ctx, c, f, filename, contentType)
+	}
+
+	if err != nil {
+		if err := DeleteRenditions(context.Background(), c, res); err != nil {
+			log.Println(err)
+		}
+
+		return Renditions{}, err
+	}
+
+	return res, nil
+}
+
+func uploadRendition(
+	ctx context.Context,
+	c S3ClientInterface,
+	f io.ReadSeeker,
+	size imageproc.Rendition,
+	filename string,
+) (string, error) {
+	r, _, err := imageproc.ResizeImage(f, size)
+	if err != nil {
+		return "", err
+	}
+
+	// renditions are always encoded as JPEG, whatever the original was
+	return c.UploadImage(ctx, r, path.Join(size.Name, filename), "image/jpeg")
+}
+
+func uploadOriginal(ctx context.Context, c S3ClientInterface, f io.ReadSeeker, filename, contentType string) (string, error) {
+	_, err := f.Seek(0, io.SeekStart)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+
+	return c.UploadPrivateImage(ctx, bytes.NewReader(b), filename, contentType)
+}
+
+// DeleteRenditions deletes the renditions uploaded by UploadRenditions, skipping the
+// ones that are empty.
+func DeleteRenditions(ctx context.Context, c S3ClientInterface, r Renditions) error {
+	var errs []error
+
+	for _, url := range []string{r.ThumbURL, r.MediumURL} {
+		if url != "" {
+			errs = append(errs, c.DeleteImage(ctx, url))
+		}
+	}
+
+	if r.OriginalKey != "" {
+		errs = append(errs, c.DeletePrivateImage(ctx, r.OriginalKey))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/muhwyndhamhp/tigerhall-kittens/utils/s3client/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// sized matches an image reader with the given dimensions.
+func sized(width, height int) interface{} {
+	return mock.MatchedBy(func(r *bytes.Reader) bool {
+		defer r.Seek(0, io.SeekStart)
+
+		cfg, _, err := image.DecodeConfig(r)
+		return err == nil && cfg.Width == width && cfg.Height == height
+	})
+}
+
+func TestUploadRenditions(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2000, 1000)))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name string
+
+		mediumErr   error
+		originalErr error
+
+		want        Renditions
+		wantDeleted []string
+		wantErr     error
+	}{
+		{
+			name: "should upload thumbnail, medium and private original",
+			want: Renditions{
+				ThumbURL:    "https://img/thumb/tiger.png",
+				MediumURL:   "https://img/medium/tiger.png",
+				OriginalKey: "tiger.png",
+			},
+		},
+		{
+			name:        "should delete thumbnail given medium upload fails",
+			mediumErr:   errors.New("upload failed"),
+			wantDeleted: []string{"https://img/thumb/tiger.png"},
+			wantErr:     errors.New("upload failed"),
+		},
+		{
+			name:        "should delete thumbnail and medium given original upload fails",
+			originalErr: errors.New("upload failed"),
+			wantDeleted: []string{"https://img/thumb/tiger.png", "https://img/medium/tiger.png"},
+			wantErr:     errors.New("upload failed"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := mocks.NewS3ClientInterface(t)
+
+			c.
+				On("UploadImage", mock.Anything, sized(250, 125), "thumb/tiger.png", "image/jpeg").
+				Return("https://img/thumb/tiger.png", nil).
+				Once()
+
+			mediumURL := "https://img/medium/tiger.png"
+			if tc.mediumErr != nil {
+				mediumURL = ""
+			}
+			c.
+				On("UploadImage", mock.Anything, sized(1280, 640), "medium/tiger.png", "image/jpeg").
+				Return(mediumURL, tc.mediumErr).
+				Once()
+
+			originalKey := "tiger.png"
+			if tc.originalErr != nil {
+				originalKey = ""
+			}
+			c.
+				On("UploadPrivateImage", mock.Anything, sized(2000, 1000), "tiger.png", "image/png").
+				Return(originalKey, tc.originalErr).
+				Maybe()
+
+			for _, url := range tc.wantDeleted {
+				c.
+					On("DeleteImage", mock.Anything, url).
+					Return(nil).
+					Once()
+			}
+
+			got, err := UploadRenditions(context.Background(), c, bytes.NewReader(buf.Bytes()), "tiger.png", "image/png")
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -4,17 +4,23 @@ import "gorm.io/gorm"
 
 func Paginate(page, pageSize int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		if page <= 0 {
-			page = 1
-		}
-		switch {
-		case pageSize > 1000:
-			pageSize = 1000
-		case pageSize <= 0:
-			pageSize = 10
-		}
+		offset, limit := PageBounds(page, pageSize)
+		return db.Offset(offset).Limit(limit)
+	}
+}
 
-		offset := (page - 1) * pageSize
-		return db.Offset(offset).Limit(pageSize)
+// PageBounds returns the offset and limit of a page, for results that are paginated
+// in memory instead of in SQL.
+func PageBounds(page, pageSize int) (offset, limit int) {
+	if page <= 0 {
+		page = 1
 	}
+	switch {
+	case pageSize > 1000:
+		pageSize = 1000
+	case pageSize <= 0:
+		pageSize = 10
+	}
+
+	return (page - 1) * pageSize, pageSize
 }
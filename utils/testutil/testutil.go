@@ -0,0 +1,47 @@
+// Package testutil holds the image fixtures shared by the tests of several packages.
+// It must not import anything that depends on imageproc, since imageproc's own tests
+// use it too.
+package testutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Stripes draws dark stripes of the given width on a light background, slanted by
+// shift pixels every 10 rows.
+func Stripes(width, shift int, light uint8) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 200, 150))
+	for y := 0; y < 150; y++ {
+		for x := 0; x < 200; x++ {
+			c := light
+			if ((x+y*shift/10)/width)%2 == 0 {
+				c = 20
+			}
+			img.SetGray(x, y, color.Gray{c})
+		}
+	}
+
+	return img
+}
+
+// PNGUpload encodes img as PNG and wraps it in an upload named tiger.png.
+func PNGUpload(t *testing.T, img image.Image) *graphql.Upload {
+	var b bytes.Buffer
+	err := png.Encode(&b, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &graphql.Upload{
+		File:        bytes.NewReader(b.Bytes()),
+		Filename:    "tiger.png",
+		Size:        int64(b.Len()),
+		ContentType: "image/png",
+	}
+}